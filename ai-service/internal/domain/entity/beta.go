@@ -0,0 +1,16 @@
+package entity
+
+// Beta — бета акции к индексу из financial-data. Adjusted скорректирована по
+// Блюму и учитывает долговую нагрузку компании, Unlevered — без неё.
+type Beta struct {
+	Ticker       string   `json:"ticker"`
+	Benchmark    string   `json:"benchmark"`
+	Frequency    string   `json:"frequency"`
+	WindowMonths int      `json:"windowMonths"`
+	Observations int      `json:"observations"`
+	Raw          float64  `json:"raw"`
+	Adjusted     float64  `json:"adjusted"`
+	Unlevered    *float64 `json:"unlevered,omitempty"`
+	DebtToEquity *float64 `json:"debtToEquity,omitempty"`
+	RSquared     float64  `json:"rSquared"`
+}
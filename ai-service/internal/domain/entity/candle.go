@@ -9,4 +9,8 @@ type Candle struct {
 	Volume float64 `json:"volume"`
 	Begin  string  `json:"begin"`
 	End    string  `json:"end"`
+
+	// Заполняются при запросе скорректированной серии (adjusted=true)
+	AdjustedClose    float64 `json:"adjustedClose,omitempty"`
+	TotalReturnIndex float64 `json:"totalReturnIndex,omitempty"`
 }
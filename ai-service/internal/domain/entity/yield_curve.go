@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type YieldCurvePoint struct {
+	Tenor float64 `json:"tenor"`
+	Yield float64 `json:"yield"`
+}
+
+// YieldCurve — кривая бескупонной доходности ОФЗ на дату, доходности в процентах.
+// Tenor и Yield заполнены, если запрашивался конкретный срок.
+type YieldCurve struct {
+	Date   time.Time         `json:"date"`
+	Points []YieldCurvePoint `json:"points"`
+	Tenor  *float64          `json:"tenor,omitempty"`
+	Yield  *float64          `json:"yield,omitempty"`
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -58,7 +59,8 @@ func (c *Client) GetDraft(ctx context.Context, ticker string, year int, period e
 	return &result.Data, nil
 }
 
-func (c *Client) SaveDraft(ctx context.Context, rawData *entity.RawData) error {
+// SaveDraft posts raw data on behalf of actor, which ends up in its change history.
+func (c *Client) SaveDraft(ctx context.Context, rawData *entity.RawData, actor string) error {
 	body, err := json.Marshal(rawData)
 	if err != nil {
 		return fmt.Errorf("failed to marshal raw data: %w", err)
@@ -73,6 +75,7 @@ func (c *Client) SaveDraft(ctx context.Context, rawData *entity.RawData) error {
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("X-Actor", actor)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -87,12 +90,13 @@ func (c *Client) SaveDraft(ctx context.Context, rawData *entity.RawData) error {
 	return nil
 }
 
+// GetDailyPrices returns a year of daily candles adjusted for splits and dividends.
 func (c *Client) GetDailyPrices(ctx context.Context, ticker string) ([]entity.Candle, error) {
-	return c.getPrice(ctx, ticker, 365, 24)
+	return c.getPrice(ctx, ticker, 365, 24, true)
 }
 
-func (c *Client) getPrice(ctx context.Context, ticker string, days, interval int) ([]entity.Candle, error) {
-	url := fmt.Sprintf("%s/price?ticker=%s&days=%d&interval=%d", c.baseURL, ticker, days, interval)
+func (c *Client) getPrice(ctx context.Context, ticker string, days, interval int, adjusted bool) ([]entity.Candle, error) {
+	url := fmt.Sprintf("%s/price?ticker=%s&days=%d&interval=%d&adjusted=%t", c.baseURL, ticker, days, interval, adjusted)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -147,6 +151,64 @@ func (c *Client) GetCBRates(ctx context.Context) (*entity.CBRate, error) {
 	return &result.Data, nil
 }
 
+// GetBeta возвращает бету акции к IMOEX по недельным доходностям за два года.
+func (c *Client) GetBeta(ctx context.Context, ticker string) (*entity.Beta, error) {
+	url := fmt.Sprintf("%s/risk/%s/beta", c.baseURL, ticker)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call financial-data API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("financial-data API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data entity.Beta `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result.Data, nil
+}
+
+// GetYieldCurve возвращает кривую ОФЗ на сегодня с доходностью на срок tenor в годах.
+func (c *Client) GetYieldCurve(ctx context.Context, tenor float64) (*entity.YieldCurve, error) {
+	url := fmt.Sprintf("%s/macro/yield-curve?tenor=%s", c.baseURL, strconv.FormatFloat(tenor, 'f', -1, 64))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call financial-data API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("financial-data API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data entity.YieldCurve `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result.Data, nil
+}
+
 func (c *Client) GetMarketCap(ctx context.Context, ticker string) (float64, error) {
 	url := fmt.Sprintf("%s/market-cap?ticker=%s", c.baseURL, ticker)
 
@@ -203,6 +265,40 @@ func (c *Client) GetPriceAt(ctx context.Context, ticker string, date time.Time)
 	return result.Data, nil
 }
 
+// GetSharesAt returns the shares outstanding on date from the share count history.
+// Zero means the history has no record for that date.
+func (c *Client) GetSharesAt(ctx context.Context, ticker string, date time.Time) (int64, error) {
+	url := fmt.Sprintf("%s/shares/%s/at?date=%s", c.baseURL, ticker, date.Format("2006-01-02"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call financial-data API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("financial-data API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data int64 `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
 func (c *Client) GetStockInfo(ctx context.Context, ticker string) (*entity.StockInfo, error) {
 	url := fmt.Sprintf("%s/stock-info?ticker=%s", c.baseURL, ticker)
 
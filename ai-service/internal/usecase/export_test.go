@@ -2,3 +2,5 @@ package usecase
 
 var BuildDCFInput = buildDCFInput
 var UnitDivisor = unitDivisor
+var RiskFreeRate = riskFreeRate
+var BetaForWACC = betaForWACC
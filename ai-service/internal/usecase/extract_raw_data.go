@@ -85,8 +85,14 @@ func (u *ExtractRawDataUsecase) Execute(ctx context.Context, task entity.Task) e
 			} else {
 				unitDivisor := unitDivisorForReportUnits(rawData.ReportUnits)
 				shares := int64(stockInfo.NumberOfShares)
+				historical, err := u.fd.GetSharesAt(ctx, task.Ticker, periodEnd)
+				if err != nil {
+					logger.Warn("failed to get share count at period end, using current one", slog.Any("error", err))
+				} else if historical > 0 {
+					shares = historical
+				}
 				rawData.SharesOutstanding = &shares
-				marketCap := int64(math.Round(price*float64(stockInfo.NumberOfShares))) / unitDivisor
+				marketCap := int64(math.Round(price*float64(shares))) / unitDivisor
 				rawData.MarketCap = &marketCap
 			}
 		}
@@ -94,7 +100,7 @@ func (u *ExtractRawDataUsecase) Execute(ctx context.Context, task entity.Task) e
 		rawData.ComputeDerivedFields()
 		rawData.Status = entity.RawDataStatusConfirmed
 
-		if err := u.fd.SaveDraft(ctx, &rawData); err != nil {
+		if err := u.fd.SaveDraft(ctx, &rawData, "ai-service:task:"+task.Id); err != nil {
 			return fmt.Errorf("save raw data: %w", err)
 		}
 	}
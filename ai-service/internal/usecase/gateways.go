@@ -10,12 +10,15 @@ import (
 type FinancialDataGateway interface {
 	GetDailyPrices(ctx context.Context, ticker string) ([]entity.Candle, error)
 	GetCBRates(ctx context.Context) (*entity.CBRate, error)
+	GetYieldCurve(ctx context.Context, tenor float64) (*entity.YieldCurve, error)
+	GetBeta(ctx context.Context, ticker string) (*entity.Beta, error)
 	GetMarketCap(ctx context.Context, ticker string) (float64, error)
 	GetPriceAt(ctx context.Context, ticker string, date time.Time) (float64, error)
 	GetStockInfo(ctx context.Context, ticker string) (*entity.StockInfo, error)
+	GetSharesAt(ctx context.Context, ticker string, date time.Time) (int64, error)
 	GetRawData(ctx context.Context, ticker string, year int, period entity.ReportPeriod) (*entity.RawData, error)
 	GetRawDataHistory(ctx context.Context, ticker string) ([]entity.RawData, error)
-	SaveDraft(ctx context.Context, rawData *entity.RawData) error
+	SaveDraft(ctx context.Context, rawData *entity.RawData, actor string) error
 }
 
 type ParserGateway interface {
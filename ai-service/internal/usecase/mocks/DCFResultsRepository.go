@@ -14,9 +14,9 @@ type DCFResultsRepository struct {
 	mock.Mock
 }
 
-// GetDCFResults provides a mock function with given fields: ctx, ticker
-func (_m *DCFResultsRepository) GetDCFResults(ctx context.Context, ticker string) (*entity.DCFResult, error) {
-	ret := _m.Called(ctx, ticker)
+// GetDCFResults provides a mock function with given fields: ctx, ticker, id
+func (_m *DCFResultsRepository) GetDCFResults(ctx context.Context, ticker string, id string) (*entity.DCFResult, error) {
+	ret := _m.Called(ctx, ticker, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetDCFResults")
@@ -24,19 +24,19 @@ func (_m *DCFResultsRepository) GetDCFResults(ctx context.Context, ticker string
 
 	var r0 *entity.DCFResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.DCFResult, error)); ok {
-		return rf(ctx, ticker)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.DCFResult, error)); ok {
+		return rf(ctx, ticker, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.DCFResult); ok {
-		r0 = rf(ctx, ticker)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.DCFResult); ok {
+		r0 = rf(ctx, ticker, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.DCFResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, ticker)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ticker, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -16,6 +16,36 @@ type FinancialDataGateway struct {
 	mock.Mock
 }
 
+// GetBeta provides a mock function with given fields: ctx, ticker
+func (_m *FinancialDataGateway) GetBeta(ctx context.Context, ticker string) (*entity.Beta, error) {
+	ret := _m.Called(ctx, ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBeta")
+	}
+
+	var r0 *entity.Beta
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Beta, error)); ok {
+		return rf(ctx, ticker)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Beta); ok {
+		r0 = rf(ctx, ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Beta)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCBRates provides a mock function with given fields: ctx
 func (_m *FinancialDataGateway) GetCBRates(ctx context.Context) (*entity.CBRate, error) {
 	ret := _m.Called(ctx)
@@ -192,6 +222,34 @@ func (_m *FinancialDataGateway) GetRawDataHistory(ctx context.Context, ticker st
 	return r0, r1
 }
 
+// GetSharesAt provides a mock function with given fields: ctx, ticker, date
+func (_m *FinancialDataGateway) GetSharesAt(ctx context.Context, ticker string, date time.Time) (int64, error) {
+	ret := _m.Called(ctx, ticker, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSharesAt")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, ticker, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, ticker, date)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, ticker, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStockInfo provides a mock function with given fields: ctx, ticker
 func (_m *FinancialDataGateway) GetStockInfo(ctx context.Context, ticker string) (*entity.StockInfo, error) {
 	ret := _m.Called(ctx, ticker)
@@ -222,17 +280,47 @@ func (_m *FinancialDataGateway) GetStockInfo(ctx context.Context, ticker string)
 	return r0, r1
 }
 
-// SaveDraft provides a mock function with given fields: ctx, rawData
-func (_m *FinancialDataGateway) SaveDraft(ctx context.Context, rawData *entity.RawData) error {
-	ret := _m.Called(ctx, rawData)
+// GetYieldCurve provides a mock function with given fields: ctx, tenor
+func (_m *FinancialDataGateway) GetYieldCurve(ctx context.Context, tenor float64) (*entity.YieldCurve, error) {
+	ret := _m.Called(ctx, tenor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetYieldCurve")
+	}
+
+	var r0 *entity.YieldCurve
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, float64) (*entity.YieldCurve, error)); ok {
+		return rf(ctx, tenor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, float64) *entity.YieldCurve); ok {
+		r0 = rf(ctx, tenor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.YieldCurve)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, float64) error); ok {
+		r1 = rf(ctx, tenor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveDraft provides a mock function with given fields: ctx, rawData, actor
+func (_m *FinancialDataGateway) SaveDraft(ctx context.Context, rawData *entity.RawData, actor string) error {
+	ret := _m.Called(ctx, rawData, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveDraft")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.RawData) error); ok {
-		r0 = rf(ctx, rawData)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RawData, string) error); ok {
+		r0 = rf(ctx, rawData, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
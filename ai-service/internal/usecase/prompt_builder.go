@@ -138,16 +138,17 @@ func writePriceHistory(b *strings.Builder, candles []entity.Candle) {
 	b.WriteString("Для анализа используй цены только отсюда.")
 	b.WriteString("При расчете оценки акции бери цену за дату генерации отчета, или за последний самый близкий к дате отчета день из таблицы")
 	b.WriteString("История цен за последние 12 месяцев (дневные свечи):\n")
-	b.WriteString("Дата       | Открытие | Закрытие | Макс   | Мин    | Объём\n")
-	b.WriteString("-----------|----------|----------|--------|--------|----------\n")
+	b.WriteString("Скорр. закрытие учитывает сплиты и дивиденды, TR-индекс — полная доходность с реинвестированием дивидендов (100 на начало периода). Для расчёта доходности используй их, для текущей цены — столбец Закрытие.\n")
+	b.WriteString("Дата       | Открытие | Закрытие | Макс   | Мин    | Объём      | Скорр. закрытие | TR-индекс\n")
+	b.WriteString("-----------|----------|----------|--------|--------|------------|-----------------|----------\n")
 
 	for _, c := range candles {
 		date := c.Begin
 		if len(date) > 10 {
 			date = date[:10]
 		}
-		fmt.Fprintf(b, "%s | %8.2f | %8.2f | %6.2f | %6.2f | %.0f\n",
-			date, c.Open, c.Close, c.High, c.Low, c.Volume)
+		fmt.Fprintf(b, "%s | %8.2f | %8.2f | %6.2f | %6.2f | %.0f | %8.2f | %7.2f\n",
+			date, c.Open, c.Close, c.High, c.Low, c.Volume, c.AdjustedClose, c.TotalReturnIndex)
 	}
 
 	b.WriteString("</price_history>\n\n")
@@ -64,6 +64,13 @@ func (s *ScenarioGenerator) Execute(ctx context.Context, task entity.Task) error
 		return fmt.Errorf("get cb rate: %w", err)
 	}
 
+	// безрисковая ставка берётся с кривой ОФЗ на горизонт прогноза
+	yieldCurve, err := s.finData.GetYieldCurve(ctx, float64(YearsToForecast))
+	if err != nil {
+		slog.Warn("yield curve unavailable, falling back to cb rate", "ticker", task.Ticker, "error", err)
+	}
+	rf, rfSource := riskFreeRate(yieldCurve, cbRate)
+
 	riskAndGrowth, err := s.riskAndGrowthRepo.GetFreshRiskAndGrowth(ctx, task.Ticker, 72*time.Hour)
 	if err != nil {
 		return fmt.Errorf("get risk and growth: %w", err)
@@ -88,7 +95,13 @@ func (s *ScenarioGenerator) Execute(ctx context.Context, task entity.Task) error
 		return fmt.Errorf("get market cap: %w", err)
 	}
 
-	wacc := calculateWACC(latest, cbRate, marketCap)
+	beta, err := s.finData.GetBeta(ctx, task.Ticker)
+	if err != nil {
+		slog.Warn("beta unavailable, falling back to default", "ticker", task.Ticker, "error", err)
+	}
+	equityBeta, betaSource := betaForWACC(beta)
+
+	wacc := calculateWACC(latest, rf, equityBeta, marketCap)
 
 	historyJSON, err := json.Marshal(history)
 	if err != nil {
@@ -101,7 +114,7 @@ func (s *ScenarioGenerator) Execute(ctx context.Context, task entity.Task) error
 
 	prompt += fmt.Sprintf("\n\n## Исторические данные компании\n\nТикер: %s\n\n%s", task.Ticker, string(historyJSON))
 
-	prompt += fmt.Sprintf("\n\n## Макроэкономические данные\n\nСтавка ЦБ РФ: %.2f%%\nWACC: %.4f", cbRate.Rate, wacc)
+	prompt += fmt.Sprintf("\n\n## Макроэкономические данные\n\nСтавка ЦБ РФ: %.2f%%\nБезрисковая ставка: %.2f%% (%s)\nБета: %.2f (%s)\nWACC: %.4f", cbRate.Rate, rf*100, rfSource, equityBeta, betaSource, wacc)
 
 	var risks, growthFactors []entity.RiskAndGrowthFactor
 	for _, f := range riskAndGrowth.Factors {
@@ -241,12 +254,29 @@ func getLatestFullYearData(history []entity.RawData) (entity.RawData, bool) {
 	return annual[0], true
 }
 
+// riskFreeRate возвращает безрисковую ставку в долях и её источник: доходность
+// ОФЗ на горизонт прогноза, а без кривой — ставку ЦБ.
+func riskFreeRate(curve *entity.YieldCurve, cbRate *entity.CBRate) (float64, string) {
+	if curve != nil && curve.Yield != nil {
+		return *curve.Yield / 100, fmt.Sprintf("доходность ОФЗ на %d г. от %s", YearsToForecast, curve.Date.Format("2006-01-02"))
+	}
+	return cbRate.Rate / 100, "ставка ЦБ РФ"
+}
+
+// betaForWACC возвращает бету для CAPM и её источник: скорректированную по
+// Блюму бету к IMOEX, а без неё — defaultBeta.
+func betaForWACC(beta *entity.Beta) (float64, string) {
+	if beta != nil && beta.Observations > 0 {
+		return beta.Adjusted, fmt.Sprintf("к %s за %d мес., скорректирована по Блюму", beta.Benchmark, beta.WindowMonths)
+	}
+	return defaultBeta, "по умолчанию"
+}
+
 // calculateWACC вычисляет WACC через CAPM для стоимости капитала и фактическую
-// стоимость долга из отчётности. CB rate передаётся в долях (0.16 = 16%).
+// стоимость долга из отчётности. rf передаётся в долях (0.16 = 16%).
 // marketCap — актуальная рыночная капитализация в рублях из MOEX API.
-func calculateWACC(d entity.RawData, cbRate *entity.CBRate, marketCap float64) float64 {
-	rf := cbRate.Rate / 100
-	ke := rf + defaultBeta*equityRiskPremium
+func calculateWACC(d entity.RawData, rf, beta float64, marketCap float64) float64 {
+	ke := rf + beta*equityRiskPremium
 
 	var kd float64
 	if d.Debt != nil && *d.Debt != 0 && d.InterestOnLoans != nil && *d.InterestOnLoans != 0 {
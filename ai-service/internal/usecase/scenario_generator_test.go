@@ -2,6 +2,7 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 
 func ptr64(v int64) *int64 { return &v }
 
+func ptrF64(v float64) *float64 { return &v }
+
 func moexRawData() entity.RawData {
 	return entity.RawData{
 		Ticker:      "MOEX",
@@ -72,6 +75,28 @@ func TestUnitDivisor(t *testing.T) {
 	}
 }
 
+func TestRiskFreeRate(t *testing.T) {
+	cbRate := &entity.CBRate{Date: time.Now(), Rate: 21.0}
+	yield := 14.5
+
+	rf, _ := usecase.RiskFreeRate(&entity.YieldCurve{Date: time.Now(), Tenor: ptrF64(3), Yield: &yield}, cbRate)
+	assert.InDelta(t, 0.145, rf, 1e-9, "доходность ОФЗ на горизонт прогноза")
+
+	rf, _ = usecase.RiskFreeRate(nil, cbRate)
+	assert.InDelta(t, 0.21, rf, 1e-9, "без кривой — ставка ЦБ")
+
+	rf, _ = usecase.RiskFreeRate(&entity.YieldCurve{Date: time.Now()}, cbRate)
+	assert.InDelta(t, 0.21, rf, 1e-9, "кривая без доходности на срок — ставка ЦБ")
+}
+
+func TestBetaForWACC(t *testing.T) {
+	beta, _ := usecase.BetaForWACC(&entity.Beta{Benchmark: "IMOEX", WindowMonths: 24, Observations: 104, Raw: 1.3, Adjusted: 1.201})
+	assert.InDelta(t, 1.201, beta, 1e-9, "скорректированная бета из financial-data")
+
+	beta, _ = usecase.BetaForWACC(nil)
+	assert.Equal(t, 1.0, beta, "без беты — значение по умолчанию")
+}
+
 const mockScenarioJSON = `[
   {
     "id": "base",
@@ -116,6 +141,7 @@ func TestScenarioGenerator_Execute_PriceIsNonZero(t *testing.T) {
 
 	finData.On("GetRawDataHistory", ctx, "MOEX").Return([]entity.RawData{moexRawData()}, nil)
 	finData.On("GetCBRates", ctx).Return(&entity.CBRate{Date: time.Now(), Rate: 21.0}, nil)
+	finData.On("GetYieldCurve", ctx, 3.0).Return(nil, errors.New("no curve"))
 	finData.On("GetStockInfo", ctx, "MOEX").Return(&entity.StockInfo{
 		Ticker:         "MOEX",
 		NumberOfShares: 2_276_401_458,
@@ -123,6 +149,9 @@ func TestScenarioGenerator_Execute_PriceIsNonZero(t *testing.T) {
 	}, nil)
 	// ~210 руб/акцию × 2.276 млрд акций
 	finData.On("GetMarketCap", ctx, "MOEX").Return(478_044_306_180.0, nil)
+	finData.On("GetBeta", ctx, "MOEX").Return(&entity.Beta{
+		Ticker: "MOEX", Benchmark: "IMOEX", WindowMonths: 24, Observations: 104, Raw: 0.8, Adjusted: 0.866,
+	}, nil)
 
 	riskRepo.On("GetFreshRiskAndGrowth", ctx, "MOEX", 72*time.Hour).Return(
 		&entity.RiskAndGrowthResponse{Ticker: "MOEX", Factors: []entity.RiskAndGrowthFactor{}}, nil,
@@ -136,7 +165,7 @@ func TestScenarioGenerator_Execute_PriceIsNonZero(t *testing.T) {
 		fn(ctx)
 	}).Return(nil)
 
-	scenarioRepo.On("SaveScenarios", ctx, "MOEX", mock.Anything).Return(nil)
+	scenarioRepo.On("SaveScenarios", ctx, "test-task-id", "MOEX", mock.Anything).Return(nil)
 
 	var capturedResult entity.DCFResult
 	dcfRepo.On("SaveDCFResults", ctx, "MOEX", mock.Anything).Run(func(args mock.Arguments) {
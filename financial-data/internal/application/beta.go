@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// BenchmarkSector asks for the beta against the sector index of the company.
+const BenchmarkSector = "sector"
+
+// betaLookbackDays covers the first return of the window and holidays around it.
+const betaLookbackDays = 14
+
+// BetaService estimates betas from the daily candles in the local store. The
+// stock side uses closes adjusted for splits and dividends, so corporate
+// actions do not show up as market moves.
+type BetaService struct {
+	prices      routers.PriceHistory
+	companyRepo routers.CompanyRepository
+	rawDataRepo routers.RawDataRepository
+}
+
+func NewBetaService(prices routers.PriceHistory, companyRepo routers.CompanyRepository, rawDataRepo routers.RawDataRepository) *BetaService {
+	return &BetaService{
+		prices:      prices,
+		companyRepo: companyRepo,
+		rawDataRepo: rawDataRepo,
+	}
+}
+
+// Estimate returns the beta of the ticker over the last windowMonths against
+// benchmark: an index code, or BenchmarkSector for the sector index, which
+// falls back to IMOEX for sectors without one. Rolling betas cover the same
+// span as the window.
+func (s *BetaService) Estimate(ctx context.Context, ticker, benchmark string, frequency domain.BetaFrequency, windowMonths int) (*domain.Beta, error) {
+	company, err := s.companyRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	index := strings.ToUpper(benchmark)
+	switch {
+	case benchmark == "":
+		index = domain.IndexIMOEX
+	case benchmark == BenchmarkSector:
+		var ok bool
+		if index, ok = domain.SectorIndex(domain.Sector(company.SectorID)); !ok {
+			index = domain.IndexIMOEX
+		}
+	default:
+		if _, ok := domain.LookupIndex(index); !ok {
+			return nil, fmt.Errorf("unknown benchmark %s: %w", benchmark, domain.ErrInvalidInput)
+		}
+	}
+
+	till := time.Now()
+	since := till.AddDate(0, -windowMonths, 0)
+	from := since.AddDate(0, -windowMonths, -betaLookbackDays)
+
+	adjusted, err := s.prices.GetAdjustedCandles(ctx, ticker, domain.Period1D, from, till)
+	if err != nil {
+		return nil, fmt.Errorf("beta: failed to get candles of %s: %w", ticker, err)
+	}
+	stock := make([]domain.Candle, len(adjusted))
+	for i, c := range adjusted {
+		stock[i] = domain.Candle{Begin: c.Begin, Close: c.AdjustedClose}
+	}
+
+	market, err := s.prices.GetIndexCandles(ctx, index, domain.Period1D, from, till)
+	if err != nil {
+		return nil, fmt.Errorf("beta: failed to get candles of %s: %w", index, err)
+	}
+
+	beta, err := domain.EstimateBeta(domain.PairedReturns(stock, market, frequency), since)
+	if err != nil {
+		return nil, err
+	}
+	beta.Ticker = ticker
+	beta.Benchmark = index
+	beta.Frequency = frequency
+	beta.WindowMonths = windowMonths
+
+	// share classes carry the leverage of the issuer
+	reportTicker := ticker
+	if company.IssuerTicker != "" {
+		reportTicker = company.IssuerTicker
+	}
+	report, err := s.rawDataRepo.GetLatestByTicker(ctx, reportTicker)
+	switch {
+	case err == nil:
+		beta.Unlever(*report)
+	case !errors.Is(err, domain.ErrNotFound):
+		slog.Warn("beta: failed to get report for leverage", "ticker", reportTicker, "error", err)
+	}
+
+	return beta, nil
+}
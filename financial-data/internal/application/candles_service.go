@@ -0,0 +1,295 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// candlesFreshTTL limits how often a read triggers a top-up from MOEX for the same series.
+const candlesFreshTTL = 15 * time.Minute
+
+// candlesBackgroundSyncTimeout bounds a top-up queued by a read.
+const candlesBackgroundSyncTimeout = 10 * time.Minute
+
+var candleIntervals = []domain.Period{domain.Period1D, domain.Period1H, domain.Period1W}
+
+// indexIntervals are synced for every index in the catalog; hourly index candles
+// are only loaded when they are read.
+var indexIntervals = []domain.Period{domain.Period1D, domain.Period1W}
+
+// adjustmentLookback is how far before the requested range candles are loaded
+// so that a dividend at the very start still has a previous close.
+const adjustmentLookback = 14 * 24 * time.Hour
+
+type CandlesService struct {
+	candlesRepo   routers.CandlesRepository
+	companyRepo   routers.CompanyRepository
+	dividendsRepo routers.DividendsRepository
+	sharesRepo    routers.ShareEventsRepository
+	market        domain.MarketService
+	redis         *redis.Client
+	historyFrom   time.Time
+}
+
+func NewCandlesService(candlesRepo routers.CandlesRepository, companyRepo routers.CompanyRepository, dividendsRepo routers.DividendsRepository, sharesRepo routers.ShareEventsRepository, market domain.MarketService, redis *redis.Client, historyFrom time.Time) *CandlesService {
+	return &CandlesService{
+		candlesRepo:   candlesRepo,
+		companyRepo:   companyRepo,
+		dividendsRepo: dividendsRepo,
+		sharesRepo:    sharesRepo,
+		market:        market,
+		redis:         redis,
+		historyFrom:   historyFrom,
+	}
+}
+
+// GetCandles serves candles of a registered company from the local store. A
+// series not synced recently is topped up from MOEX in the background, so the
+// read returns what is stored. A series with nothing stored yet is read through
+// from MOEX for [from, till] only; its history is loaded by the scheduled sync.
+func (s *CandlesService) GetCandles(ctx context.Context, ticker string, interval domain.Period, from, till time.Time) ([]domain.Candle, error) {
+	if _, err := s.companyRepo.GetByTicker(ctx, ticker); err != nil {
+		return nil, err
+	}
+	return s.getStored(ctx, ticker, interval, from, till, s.market.GetStockPriceRange)
+}
+
+// GetIndexCandles serves candles of an index from the catalog like GetCandles.
+// Index candles are stored next to the shares under the index code.
+func (s *CandlesService) GetIndexCandles(ctx context.Context, index string, interval domain.Period, from, till time.Time) ([]domain.Candle, error) {
+	if _, ok := domain.LookupIndex(index); !ok {
+		return nil, fmt.Errorf("unknown index %s: %w", index, domain.ErrNotFound)
+	}
+	return s.getStored(ctx, index, interval, from, till, s.market.GetIndexRange)
+}
+
+func (s *CandlesService) getStored(ctx context.Context, ticker string, interval domain.Period, from, till time.Time, load candlesLoader) ([]domain.Candle, error) {
+	_, err := s.candlesRepo.GetLastBegin(ctx, ticker, interval)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		// not stored on purpose: a partial series would make the scheduled
+		// sync skip the history before it
+		candles, err := load(ticker, from, till, interval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candles from MOEX: %w", err)
+		}
+		return candles, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if !s.isFresh(ctx, ticker, interval) {
+		s.queueSync(ticker, interval, load)
+	}
+	return s.candlesRepo.GetRange(ctx, ticker, interval, from, till)
+}
+
+// GetAdjustedCandles returns candles back-adjusted for splits and dividends with a
+// total-return index. Adjustment is relative to today, so candles up to now are
+// loaded even when till is earlier, and the result is cut to [from, till] afterwards.
+func (s *CandlesService) GetAdjustedCandles(ctx context.Context, ticker string, interval domain.Period, from, till time.Time) ([]domain.AdjustedCandle, error) {
+	candles, err := s.GetCandles(ctx, ticker, interval, from.Add(-adjustmentLookback), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	dividends, err := s.dividendsRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("candles: failed to get dividends: %w", err)
+	}
+
+	events, err := s.sharesRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("candles: failed to get share events: %w", err)
+	}
+
+	adjusted := domain.AdjustCandles(candles, events, dividends)
+
+	fromDay := from.Format("2006-01-02")
+	tillDay := till.Format("2006-01-02")
+	result := make([]domain.AdjustedCandle, 0, len(adjusted))
+	for _, c := range adjusted {
+		day := c.Begin[:min(len(c.Begin), 10)]
+		if day >= fromDay && day <= tillDay {
+			result = append(result, c)
+		}
+	}
+
+	// the index should start at 100 on the first returned candle
+	if len(result) > 0 && result[0].TotalReturnIndex != 0 {
+		base := result[0].TotalReturnIndex
+		for i := range result {
+			result[i].TotalReturnIndex = result[i].TotalReturnIndex / base * 100
+		}
+	}
+
+	return result, nil
+}
+
+// Sync imports candles of every interval for the ticker. With full set the whole
+// history is re-imported, otherwise only candles since the last stored one.
+func (s *CandlesService) Sync(ctx context.Context, ticker string, full bool) error {
+	var errs []error
+	for _, interval := range candleIntervals {
+		if err := s.syncInterval(ctx, ticker, interval, full); err != nil {
+			errs = append(errs, fmt.Errorf("interval %d: %w", interval, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *CandlesService) SyncAll(ctx context.Context, full bool) error {
+	companies, err := s.companyRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("candles: failed to get companies: %w", err)
+	}
+
+	var synced int
+	for _, company := range companies {
+		if err := s.Sync(ctx, company.Ticker, full); err != nil {
+			slog.Error("candles: failed to sync", "ticker", company.Ticker, "error", err)
+			continue
+		}
+		synced++
+	}
+
+	slog.Info("candles: synced all", "total", len(companies), "synced", synced, "full", full)
+	return nil
+}
+
+// SyncIndex imports daily and weekly candles of the index like Sync.
+func (s *CandlesService) SyncIndex(ctx context.Context, index string, full bool) error {
+	var errs []error
+	for _, interval := range indexIntervals {
+		if err := s.sync(ctx, index, interval, full, s.market.GetIndexRange); err != nil {
+			errs = append(errs, fmt.Errorf("interval %d: %w", interval, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *CandlesService) SyncIndices(ctx context.Context, full bool) error {
+	indices := domain.Indices()
+
+	var synced int
+	for _, index := range indices {
+		if err := s.SyncIndex(ctx, index.Code, full); err != nil {
+			slog.Error("candles: failed to sync index", "index", index.Code, "error", err)
+			continue
+		}
+		synced++
+	}
+
+	slog.Info("candles: synced indices", "total", len(indices), "synced", synced, "full", full)
+	return nil
+}
+
+// RunSync tops up all companies and indices right away and then every period
+// until ctx is done.
+func (s *CandlesService) RunSync(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		if err := s.SyncAll(ctx, false); err != nil {
+			slog.Error("candles: scheduled sync failed", "error", err)
+		}
+		if err := s.SyncIndices(ctx, false); err != nil {
+			slog.Error("candles: scheduled index sync failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *CandlesService) syncInterval(ctx context.Context, ticker string, interval domain.Period, full bool) error {
+	return s.sync(ctx, ticker, interval, full, s.market.GetStockPriceRange)
+}
+
+// candlesLoader is a share or an index range of MarketService.
+type candlesLoader func(ticker string, from, till time.Time, interval domain.Period) ([]domain.Candle, error)
+
+// sync stores candles of the security loaded by load.
+func (s *CandlesService) sync(ctx context.Context, ticker string, interval domain.Period, full bool, load candlesLoader) error {
+	from := s.historyFrom
+	if !full {
+		last, err := s.candlesRepo.GetLastBegin(ctx, ticker, interval)
+		switch {
+		case err == nil:
+			// re-read the last stored candle, it may have been incomplete
+			from = last
+		case !errors.Is(err, domain.ErrNotFound):
+			return err
+		}
+	}
+
+	candles, err := load(ticker, from, time.Now(), interval)
+	if err != nil {
+		return fmt.Errorf("failed to load candles from MOEX: %w", err)
+	}
+
+	if err := s.candlesRepo.Upsert(ctx, ticker, interval, candles); err != nil {
+		return err
+	}
+
+	if err := s.redis.Set(ctx, candlesFreshKey(ticker, interval), time.Now().Format(time.RFC3339), candlesFreshTTL).Err(); err != nil {
+		slog.Warn("candles: failed to mark series as fresh", "ticker", ticker, "interval", interval, "error", err)
+	}
+
+	slog.Info("candles: imported", "ticker", ticker, "interval", interval, "from", from.Format("2006-01-02"), "count", len(candles))
+	return nil
+}
+
+// queueSync starts an incremental sync of the series in the background unless
+// one is already running for it on any instance.
+func (s *CandlesService) queueSync(ticker string, interval domain.Period, load candlesLoader) {
+	key := candlesSyncingKey(ticker, interval)
+	ok, err := s.redis.SetNX(context.Background(), key, time.Now().Format(time.RFC3339), candlesBackgroundSyncTimeout).Result()
+	if err != nil {
+		slog.Warn("candles: failed to lock background sync", "ticker", ticker, "interval", interval, "error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), candlesBackgroundSyncTimeout)
+		defer cancel()
+
+		if err := s.sync(ctx, ticker, interval, false, load); err != nil {
+			slog.Warn("candles: background sync failed", "ticker", ticker, "interval", interval, "error", err)
+		}
+		if err := s.redis.Del(ctx, key).Err(); err != nil {
+			slog.Warn("candles: failed to unlock background sync", "ticker", ticker, "interval", interval, "error", err)
+		}
+	}()
+}
+
+func (s *CandlesService) isFresh(ctx context.Context, ticker string, interval domain.Period) bool {
+	n, err := s.redis.Exists(ctx, candlesFreshKey(ticker, interval)).Result()
+	if err != nil {
+		slog.Warn("redis exists error", "ticker", ticker, "error", err)
+		return false
+	}
+	return n > 0
+}
+
+func candlesFreshKey(ticker string, interval domain.Period) string {
+	return fmt.Sprintf("candles:synced:%s:%d", ticker, interval)
+}
+
+func candlesSyncingKey(ticker string, interval domain.Period) string {
+	return fmt.Sprintf("candles:syncing:%s:%d", ticker, interval)
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// CompanyRefreshService keeps company profiles in line with the ISS
+// description and notices securities that stopped trading.
+type CompanyRefreshService struct {
+	companyRepo routers.CompanyRepository
+	market      domain.MarketService
+	events      routers.EventPublisher
+}
+
+func NewCompanyRefreshService(companyRepo routers.CompanyRepository, market domain.MarketService, events routers.EventPublisher) *CompanyRefreshService {
+	return &CompanyRefreshService{
+		companyRepo: companyRepo,
+		market:      market,
+		events:      events,
+	}
+}
+
+// Refresh reloads the profile of the ticker from ISS. A security ISS no longer
+// describes is marked delisted as well as one off its primary board. An event
+// is published when a company becomes delisted.
+func (s *CompanyRefreshService) Refresh(ctx context.Context, ticker string) (*domain.CompanyRefreshResult, error) {
+	company, err := s.companyRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+	company.ApplyDefaults()
+	wasDelisted := company.Status == domain.CompanyDelisted
+
+	now := time.Now().UTC()
+	info, err := s.market.GetStockInfo(ticker)
+	if errors.Is(err, domain.ErrNotFound) {
+		// no description at all: the security was removed from ISS
+		info = &domain.StockInfo{Board: company.Board}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load description from MOEX: %w", err)
+	}
+
+	changed := company.Enrich(*info, now)
+	if err := s.companyRepo.UpdateProfile(ctx, company); err != nil {
+		return nil, err
+	}
+
+	result := &domain.CompanyRefreshResult{Ticker: ticker, Changed: changed, Status: company.Status}
+	if len(changed) == 0 {
+		result.Changed = []string{}
+		return result, nil
+	}
+	slog.Info("companies: profile changed", "ticker", ticker, "changed", changed, "status", company.Status)
+
+	if company.Status == domain.CompanyDelisted && !wasDelisted {
+		if err := s.events.PublishCompanyDelisted(ctx, *company); err != nil {
+			slog.Error("companies: failed to publish delisting", "ticker", ticker, "error", err)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *CompanyRefreshService) RefreshAll(ctx context.Context) error {
+	companies, err := s.companyRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("companies: failed to get companies: %w", err)
+	}
+
+	var refreshed, changed, delisted int
+	for _, company := range companies {
+		result, err := s.Refresh(ctx, company.Ticker)
+		if err != nil {
+			slog.Error("companies: failed to refresh", "ticker", company.Ticker, "error", err)
+			continue
+		}
+		refreshed++
+		if len(result.Changed) > 0 {
+			changed++
+		}
+		if result.Status == domain.CompanyDelisted {
+			delisted++
+		}
+	}
+
+	slog.Info("companies: refreshed all", "total", len(companies), "refreshed", refreshed, "changed", changed, "delisted", delisted)
+	return nil
+}
+
+// RunRefresh refreshes all company profiles right away and then every period until ctx is done.
+func (s *CompanyRefreshService) RunRefresh(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		if err := s.RefreshAll(ctx); err != nil {
+			slog.Error("companies: scheduled refresh failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
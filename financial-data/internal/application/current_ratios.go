@@ -0,0 +1,199 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CurrentRatiosService keeps ratios at the live market price. Report-date ratios
+// freeze the market cap at the end of the period, so valuation multiples go
+// stale until the next report.
+type CurrentRatiosService struct {
+	rawDataRepo routers.RawDataRepository
+	companyRepo routers.CompanyRepository
+	sharesRepo  routers.ShareEventsRepository
+	market      domain.MarketService
+	redis       *redis.Client
+	ttl         time.Duration
+}
+
+// NewCurrentRatiosService caches computed ratios for ttl; it should be longer
+// than the refresh interval so that reads between refreshes hit the cache.
+func NewCurrentRatiosService(rawDataRepo routers.RawDataRepository, companyRepo routers.CompanyRepository, sharesRepo routers.ShareEventsRepository, market domain.MarketService, redis *redis.Client, ttl time.Duration) *CurrentRatiosService {
+	return &CurrentRatiosService{
+		rawDataRepo: rawDataRepo,
+		companyRepo: companyRepo,
+		sharesRepo:  sharesRepo,
+		market:      market,
+		redis:       redis,
+		ttl:         ttl,
+	}
+}
+
+// GetCurrent serves ratios from the cache, computing them on a miss.
+func (s *CurrentRatiosService) GetCurrent(ctx context.Context, ticker string) (*domain.CurrentRatios, error) {
+	cached, err := s.redis.Get(ctx, currentRatiosKey(ticker)).Result()
+	switch {
+	case err == nil:
+		var ratios domain.CurrentRatios
+		if err := json.Unmarshal([]byte(cached), &ratios); err == nil {
+			return &ratios, nil
+		}
+		slog.Warn("current ratios: corrupted cache entry", "ticker", ticker)
+	case !errors.Is(err, redis.Nil):
+		slog.Warn("redis get error", "ticker", ticker, "error", err)
+	}
+
+	return s.Refresh(ctx, ticker)
+}
+
+// Refresh recomputes ratios of the ticker and stores them in the cache.
+func (s *CurrentRatiosService) Refresh(ctx context.Context, ticker string) (*domain.CurrentRatios, error) {
+	company, err := s.companyRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	var ratios *domain.CurrentRatios
+	if company.IssuerTicker != "" {
+		// share classes report nothing themselves, valuation is the issuer's
+		ratios, err = s.GetCurrent(ctx, company.IssuerTicker)
+		if err != nil {
+			return nil, err
+		}
+		classRatios := *ratios
+		classRatios.Ticker = ticker
+		classRatios.DividendPerShare = nil
+		classRatios.DividendYield = nil
+		ratios = &classRatios
+	} else {
+		ratios, err = s.calculate(ctx, ticker)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(ratios)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.Set(ctx, currentRatiosKey(ticker), data, s.ttl).Err(); err != nil {
+		slog.Warn("current ratios: failed to cache", "ticker", ticker, "error", err)
+	}
+
+	return ratios, nil
+}
+
+func (s *CurrentRatiosService) calculate(ctx context.Context, ticker string) (*domain.CurrentRatios, error) {
+	history, err := s.rawDataRepo.GetHistoryByTicker(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("current ratios: failed to get fundamentals: %w", err)
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no confirmed reports for %s: %w", ticker, domain.ErrNotFound)
+	}
+
+	// history is ordered from the latest report
+	fundamentals, previous := &history[0], (*domain.RawData)(nil)
+	var ttmThrough *domain.ReportPeriod
+	if ttm, through, ok := domain.LatestTTM(history, history[0].Year); ok {
+		fundamentals = ttm
+		ttmThrough = &through
+		if prev, ok := domain.BuildTTM(history, ttm.Year-1, through); ok {
+			previous = prev
+		}
+	} else {
+		for i := range history {
+			if history[i].Year == fundamentals.Year-1 && history[i].Period == fundamentals.Period {
+				previous = &history[i]
+				break
+			}
+		}
+	}
+
+	marketCap, err := s.market.GetMarketCap(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("current ratios: failed to get market cap: %w", err)
+	}
+
+	shares, err := s.currentShares(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("current ratios: failed to get share count: %w", err)
+	}
+
+	result, err := domain.NewCurrentRatios(fundamentals, previous, marketCap, shares, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("current ratios: %w", err)
+	}
+	result.Ticker = ticker
+	result.TTMThrough = ttmThrough
+	return result, nil
+}
+
+// currentShares is the count from share history, falling back to ISSUESIZE.
+func (s *CurrentRatiosService) currentShares(ctx context.Context, ticker string) (int64, error) {
+	shares, err := s.sharesRepo.GetSharesAt(ctx, ticker, time.Now())
+	if err == nil {
+		return shares, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return 0, err
+	}
+
+	stockInfo, err := s.market.GetStockInfo(ticker)
+	if err != nil {
+		return 0, err
+	}
+	return int64(stockInfo.NumberOfShares), nil
+}
+
+func (s *CurrentRatiosService) RefreshAll(ctx context.Context) error {
+	companies, err := s.companyRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("current ratios: failed to get companies: %w", err)
+	}
+
+	var refreshed int
+	for _, company := range companies {
+		if _, err := s.Refresh(ctx, company.Ticker); err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				slog.Error("current ratios: failed to refresh", "ticker", company.Ticker, "error", err)
+			}
+			continue
+		}
+		refreshed++
+	}
+
+	slog.Info("current ratios: refreshed all", "total", len(companies), "refreshed", refreshed)
+	return nil
+}
+
+// RunRefresh refreshes all companies right away and then every period until ctx is done.
+func (s *CurrentRatiosService) RunRefresh(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		if err := s.RefreshAll(ctx); err != nil {
+			slog.Error("current ratios: scheduled refresh failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func currentRatiosKey(ticker string) string {
+	return fmt.Sprintf("ratios:current:%s", ticker)
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// calendarLookaround widens the ex-date query so that dividends whose last
+// buy date falls into the range are found even when the ex-date is outside it.
+const calendarLookaround = 7 * 24 * time.Hour
+
+type DividendAnalyticsService struct {
+	dividendsRepo routers.DividendsRepository
+	companyRepo   routers.CompanyRepository
+	market        domain.MarketService
+}
+
+func NewDividendAnalyticsService(dividendsRepo routers.DividendsRepository, companyRepo routers.CompanyRepository, market domain.MarketService) *DividendAnalyticsService {
+	return &DividendAnalyticsService{dividendsRepo: dividendsRepo, companyRepo: companyRepo, market: market}
+}
+
+// Calendar returns dividends of all companies with the last day to buy in [from, till].
+func (s *DividendAnalyticsService) Calendar(ctx context.Context, from, till time.Time) ([]domain.DividendCalendarEntry, error) {
+	dividends, err := s.dividendsRepo.GetByExDateRange(ctx, from.Add(-calendarLookaround), till.Add(calendarLookaround))
+	if err != nil {
+		return nil, fmt.Errorf("dividends: failed to get calendar: %w", err)
+	}
+
+	names := make(map[string]string)
+	if companies, err := s.companyRepo.GetAll(ctx); err != nil {
+		slog.Warn("dividends: failed to get company names for calendar", "error", err)
+	} else {
+		for _, c := range companies {
+			names[c.Ticker] = c.Name
+		}
+	}
+
+	entries := []domain.DividendCalendarEntry{}
+	for _, d := range dividends {
+		lastBuy := d.LastBuyDate()
+		if lastBuy.Before(from) || lastBuy.After(till) {
+			continue
+		}
+		entries = append(entries, domain.DividendCalendarEntry{Dividends: d, CompanyName: names[d.Ticker], LastBuyDate: lastBuy})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].LastBuyDate.Before(entries[j].LastBuyDate) })
+	return entries, nil
+}
+
+// Stats summarizes the dividend history of a ticker. The TTM yield is against
+// the live price; without a price the rest of the stats is still returned.
+func (s *DividendAnalyticsService) Stats(ctx context.Context, ticker string) (*domain.DividendStats, error) {
+	dividends, err := s.dividendsRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("dividends: failed to get dividends: %w", err)
+	}
+
+	var price *float64
+	candles, err := s.market.GetStockPrice(ticker, 5, domain.Period1H)
+	switch {
+	case err != nil:
+		slog.Warn("dividends: failed to get live price", "ticker", ticker, "error", err)
+	case len(candles) > 0:
+		price = &candles[len(candles)-1].Close
+	}
+
+	return domain.CalculateDividendStats(ticker, dividends, price, time.Now()), nil
+}
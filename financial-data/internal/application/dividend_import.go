@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// DividendImportService keeps dividends in line with the ISS dividends
+// resource. Dividends entered by hand are matched by ex-date and keep their
+// payment date and payout ratio.
+type DividendImportService struct {
+	dividendsRepo routers.DividendsRepository
+	companyRepo   routers.CompanyRepository
+	market        domain.MarketService
+	events        routers.EventPublisher
+}
+
+func NewDividendImportService(dividendsRepo routers.DividendsRepository, companyRepo routers.CompanyRepository, market domain.MarketService, events routers.EventPublisher) *DividendImportService {
+	return &DividendImportService{
+		dividendsRepo: dividendsRepo,
+		companyRepo:   companyRepo,
+		market:        market,
+		events:        events,
+	}
+}
+
+// Import upserts dividends of the ticker listed by ISS. The yield is taken from
+// the close of the last day to buy once it is over. An event is published for
+// every new dividend, except for past ones loaded into an empty history, so the
+// first import of a ticker does not announce its whole record.
+func (s *DividendImportService) Import(ctx context.Context, ticker string) (*domain.DividendImportResult, error) {
+	listed, err := s.market.GetDividends(ticker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dividends from MOEX: %w", err)
+	}
+
+	stored, err := s.dividendsRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+	byExDate := make(map[string]domain.Dividends, len(stored))
+	for _, d := range stored {
+		byExDate[d.ExDividendDate.Format("2006-01-02")] = d
+	}
+
+	result := &domain.DividendImportResult{Ticker: ticker, Listed: len(listed)}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	var errs []error
+	for _, d := range listed {
+		d.Ticker = ticker
+		existing, found := byExDate[d.ExDividendDate.Format("2006-01-02")]
+		sameAmount := found && math.Abs(existing.AmountPerShare-d.AmountPerShare) < 1e-6
+
+		if (!sameAmount || existing.DividendYield == nil) && d.LastBuyDate().Before(today) {
+			price, err := s.market.GetPriceAt(ticker, d.LastBuyDate())
+			if err != nil {
+				slog.Warn("dividends: no price for yield", "ticker", ticker, "ex_date", d.ExDividendDate.Format("2006-01-02"), "error", err)
+			} else {
+				d.SetYield(price)
+			}
+		}
+
+		if sameAmount && existing.Currency == d.Currency && existing.RecordDate != nil && d.DividendYield == nil {
+			result.Unchanged++
+			continue
+		}
+
+		inserted, err := s.dividendsRepo.Upsert(ctx, &d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ex-date %s: %w", d.ExDividendDate.Format("2006-01-02"), err))
+			continue
+		}
+		if !inserted {
+			result.Updated++
+			continue
+		}
+		result.Created++
+
+		if len(stored) == 0 && d.ExDividendDate.Before(today) {
+			continue
+		}
+		if err := s.events.PublishDividendAnnounced(ctx, d); err != nil {
+			slog.Error("dividends: failed to publish new dividend", "ticker", ticker, "ex_date", d.ExDividendDate.Format("2006-01-02"), "error", err)
+			continue
+		}
+		result.Announced++
+	}
+
+	slog.Info("dividends: imported", "ticker", ticker, "listed", result.Listed, "created", result.Created, "updated", result.Updated, "announced", result.Announced)
+	return result, errors.Join(errs...)
+}
+
+func (s *DividendImportService) ImportAll(ctx context.Context) error {
+	companies, err := s.companyRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("dividends: failed to get companies: %w", err)
+	}
+
+	var imported, created int
+	for _, company := range companies {
+		result, err := s.Import(ctx, company.Ticker)
+		if result != nil {
+			created += result.Created
+		}
+		if err != nil {
+			slog.Error("dividends: failed to import", "ticker", company.Ticker, "error", err)
+			continue
+		}
+		imported++
+	}
+
+	slog.Info("dividends: imported all", "total", len(companies), "imported", imported, "created", created)
+	return nil
+}
+
+// RunImport imports dividends of all companies right away and then every period until ctx is done.
+func (s *DividendImportService) RunImport(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		if err := s.ImportAll(ctx); err != nil {
+			slog.Error("dividends: scheduled import failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
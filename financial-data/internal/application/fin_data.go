@@ -25,17 +25,34 @@ import (
 
 type FinData struct {
 	ratiosRepo     routers.RatiosRepository
+	sharesRepo     routers.ShareEventsRepository
 	rawDataRepo    routers.RawDataRepository
+	rawDataHistory routers.RawDataHistory
+	rawDataSources routers.RawDataSources
 	companyRepo    routers.CompanyRepository
 	sectorRepo     routers.SectorRepository
 	dividendsRepo  routers.DividendsRepository
 	cbRateRepo     routers.MacroDataRepository
+	macroSeries    *MacroSeriesService
+	macroSync      time.Duration
 	newsRepo       routers.NewsRepository
 	marketService  domain.MarketService
+	marketCaps     routers.MarketCapHistory
 	ratiosService  routers.RatiosCalculator
+	candlesService *CandlesService
+	candlesSync    time.Duration
+	dividendImport *DividendImportService
+	dividendsSync  time.Duration
+	companyRefresh *CompanyRefreshService
+	companiesSync  time.Duration
+	currentRatios  *CurrentRatiosService
+	sectorStats    routers.SectorBenchmarks
+	ratiosRefresh  time.Duration
+	jobsLeader     *JobsLeader
 	eventPublisher routers.EventPublisher
 	kafkaProducer  *kafka.Producer
 	aiProducer     *kafka.Producer
+	eventsProducer *kafka.Producer
 	pool           *pgxpool.Pool
 	redisClient    *redis.Client
 	srv            *http.Server
@@ -64,37 +81,111 @@ func NewFinData() (*FinData, error) {
 
 	ratiosRepo := infrastructure.NewRatiosRepository(pool)
 	rawDataRepo := infrastructure.NewRawDataRepository(pool)
+	transactor := infrastructure.NewTransactor(pool)
+	rawDataHistory := NewRawDataHistory(infrastructure.NewRawDataVersionsRepository(pool), rawDataRepo, transactor)
 	companyRepo := infrastructure.NewCompanyRepository(pool, redisClient)
 	sectorRepo := infrastructure.NewSectorRepository(pool)
 	dividendsRepo := infrastructure.NewDividendsRepository(pool)
 	cbRateRepo := infrastructure.NewCBRateRepository(pool)
 	newsRepo := infrastructure.NewNewsRepository(pool)
+	candlesRepo := infrastructure.NewCandlesRepository(pool)
+	sharesRepo := infrastructure.NewShareEventsRepository(pool)
 
-	moexDataProvider := infrastructure.NewMoexDataProvider(redisClient)
-	ratiosService := NewRatiosService(rawDataRepo, ratiosRepo, companyRepo)
+	marketProvider, err := infrastructure.NewMarketService(redisClient, companyRepo)
+	if err != nil {
+		return nil, err
+	}
+	marketService := NewShareAwareMarket(marketProvider, sharesRepo)
+	slog.Info("market service initialized", "provider", getEnv("MARKET_PROVIDER", infrastructure.MarketProviderMoex))
+
+	ratiosService := NewRatiosService(rawDataRepo, ratiosRepo, companyRepo, sharesRepo)
+	rawDataSources := NewRawDataSourcesService(infrastructure.NewRawDataSourcesRepository(pool), rawDataRepo, NewRawDataValidator(rawDataRepo), rawDataHistory, ratiosService, transactor)
+
+	historyFrom, err := time.Parse("2006-01-02", getEnv("CANDLES_HISTORY_FROM", "2000-01-01"))
+	if err != nil {
+		return nil, fmt.Errorf("parse CANDLES_HISTORY_FROM: %w", err)
+	}
+	candlesSync, err := time.ParseDuration(getEnv("CANDLES_SYNC_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("parse CANDLES_SYNC_INTERVAL: %w", err)
+	}
+	candlesService := NewCandlesService(candlesRepo, companyRepo, dividendsRepo, sharesRepo, marketService, redisClient, historyFrom)
+
+	ratiosRefresh, err := time.ParseDuration(getEnv("CURRENT_RATIOS_REFRESH_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("parse CURRENT_RATIOS_REFRESH_INTERVAL: %w", err)
+	}
+	currentRatios := NewCurrentRatiosService(rawDataRepo, companyRepo, sharesRepo, marketService, redisClient, 2*ratiosRefresh)
 
 	kafkaBrokers := []string{getEnv("KAFKA_URL", "kafka:9092")}
 	parserTopic := getEnv("KAFKA_PARSER_TOPIC", "parser.parse_ticker")
 	aiTopic := getEnv("KAFKA_AI_TOPIC", "ai-analyze-tasks")
+	eventsTopic := getEnv("KAFKA_EVENTS_TOPIC", "financial-data.events")
 	kafkaProducer := kafka.NewProducer(kafkaBrokers, parserTopic)
 	aiProducer := kafka.NewProducer(kafkaBrokers, aiTopic)
-	eventPublisher := kafka.NewKafkaEventPublisher(kafkaProducer, aiProducer)
+	eventsProducer := kafka.NewProducer(kafkaBrokers, eventsTopic)
+	eventPublisher := kafka.NewKafkaEventPublisher(kafkaProducer, aiProducer, eventsProducer)
+
+	slog.Info("Kafka producers initialized", "parser_topic", parserTopic, "ai_topic", aiTopic, "events_topic", eventsTopic)
+
+	dividendsSync, err := time.ParseDuration(getEnv("DIVIDENDS_SYNC_INTERVAL", "12h"))
+	if err != nil {
+		return nil, fmt.Errorf("parse DIVIDENDS_SYNC_INTERVAL: %w", err)
+	}
+	dividendImport := NewDividendImportService(dividendsRepo, companyRepo, marketService, eventPublisher)
+
+	companiesSync, err := time.ParseDuration(getEnv("COMPANIES_REFRESH_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("parse COMPANIES_REFRESH_INTERVAL: %w", err)
+	}
+	companyRefresh := NewCompanyRefreshService(companyRepo, marketService, eventPublisher)
 
-	slog.Info("Kafka producers initialized", "parser_topic", parserTopic, "ai_topic", aiTopic)
+	macroHistoryFrom, err := time.Parse("2006-01-02", getEnv("MACRO_HISTORY_FROM", "2015-01-01"))
+	if err != nil {
+		return nil, fmt.Errorf("parse MACRO_HISTORY_FROM: %w", err)
+	}
+	macroSync, err := time.ParseDuration(getEnv("MACRO_SYNC_INTERVAL", "6h"))
+	if err != nil {
+		return nil, fmt.Errorf("parse MACRO_SYNC_INTERVAL: %w", err)
+	}
+	jobsLeaseTTL, err := time.ParseDuration(getEnv("JOBS_LEASE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse JOBS_LEASE_TTL: %w", err)
+	}
+	jobsLease := infrastructure.NewJobsLease(redisClient, getEnv("INSTANCE_ID", defaultInstanceID()), jobsLeaseTTL)
+
+	macroSeries := NewMacroSeriesService(cbRateRepo, infrastructure.NewMacroSeriesRepository(pool), infrastructure.NewMacroDataProvider(), eventPublisher, macroHistoryFrom)
 
 	return &FinData{
 		ratiosRepo:     ratiosRepo,
+		sharesRepo:     sharesRepo,
 		rawDataRepo:    rawDataRepo,
+		rawDataHistory: rawDataHistory,
+		rawDataSources: rawDataSources,
 		companyRepo:    companyRepo,
 		sectorRepo:     sectorRepo,
 		dividendsRepo:  dividendsRepo,
 		cbRateRepo:     cbRateRepo,
+		macroSeries:    macroSeries,
+		macroSync:      macroSync,
 		newsRepo:       newsRepo,
-		marketService:  moexDataProvider,
+		marketService:  marketService,
+		marketCaps:     marketService,
 		ratiosService:  ratiosService,
+		candlesService: candlesService,
+		candlesSync:    candlesSync,
+		dividendImport: dividendImport,
+		dividendsSync:  dividendsSync,
+		companyRefresh: companyRefresh,
+		companiesSync:  companiesSync,
+		currentRatios:  currentRatios,
+		sectorStats:    NewSectorStatsService(ratiosRepo, companyRepo),
+		ratiosRefresh:  ratiosRefresh,
+		jobsLeader:     NewJobsLeader(jobsLease, jobsLeaseTTL/3),
 		eventPublisher: eventPublisher,
 		kafkaProducer:  kafkaProducer,
 		aiProducer:     aiProducer,
+		eventsProducer: eventsProducer,
 		pool:           pool,
 		redisClient:    redisClient,
 	}, nil
@@ -123,14 +214,20 @@ func (f *FinData) prepareRouter() error {
 		w.Write([]byte(`{"status":"healthy"}`))
 	})
 
-	routers.RegisterRatiosRoutes(r, f.ratiosRepo, f.ratiosService, m)
-	routers.RegisterRawDataRoutes(r, f.rawDataRepo, f.ratiosService, m)
-	routers.RegisterCompanyRoutes(r, f.companyRepo, f.marketService, f.eventPublisher, m)
+	routers.RegisterRatiosRoutes(r, f.ratiosRepo, f.ratiosService, f.currentRatios, f.sectorStats, m)
+	routers.RegisterScreenerRoutes(r, f.ratiosRepo)
+	routers.RegisterRawDataRoutes(r, f.rawDataRepo, f.ratiosService, NewRawDataValidator(f.rawDataRepo), f.rawDataHistory, m)
+	routers.RegisterRawDataFileRoutes(r, f.rawDataRepo, infrastructure.NewRawDataFileCodec(), NewRawDataImporter(f.rawDataRepo, f.companyRepo, f.rawDataHistory, f.ratiosService), m)
+	routers.RegisterRawDataSourcesRoutes(r, f.rawDataSources, m)
+	routers.RegisterCompanyRoutes(r, f.companyRepo, f.marketService, f.eventPublisher, f.companyRefresh, m)
 	routers.RegisterSectorRoutes(r, f.sectorRepo, m)
-	routers.RegisterDividendsRoutes(r, f.dividendsRepo, m)
-	routers.RegisterMacroRoutes(r, f.cbRateRepo, m)
+	routers.RegisterDividendsRoutes(r, f.dividendsRepo, NewDividendAnalyticsService(f.dividendsRepo, f.companyRepo, f.marketService), f.dividendImport, m)
+	routers.RegisterMacroRoutes(r, f.cbRateRepo, f.macroSeries, m)
 	routers.RegisterNewsRoutes(r, f.newsRepo, m)
-	routers.RegisterPriceRoutes(r, f.marketService, m)
+	routers.RegisterPriceRoutes(r, f.marketService, f.candlesService, f.marketCaps, m)
+	routers.RegisterSharesRoutes(r, f.sharesRepo, f.ratiosService, m)
+	routers.RegisterRiskRoutes(r, NewBetaService(f.candlesService, f.companyRepo, f.rawDataRepo))
+	routers.RegisterIndexRoutes(r, NewIndexService(f.candlesService, f.companyRepo, f.dividendsRepo, f.sharesRepo), m)
 
 	srv := &http.Server{
 		Addr:         ":8082",
@@ -148,6 +245,20 @@ func (f *FinData) runRouter() error {
 		return errors.New("srv is not initialize")
 	}
 
+	// the background jobs run only on the replica holding the jobs lease;
+	// stopJobs waits for the lease to be released, so it is called before
+	// the connections are closed
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		f.jobsLeader.Run(jobsCtx, f.runJobs)
+	}()
+	stopJobs := func() {
+		cancelJobs()
+		<-jobsDone
+	}
+
 	serverErrors := make(chan error, 1)
 	go func() {
 		slog.Info("starting server", "address", f.srv.Addr)
@@ -159,8 +270,10 @@ func (f *FinData) runRouter() error {
 
 	select {
 	case err := <-serverErrors:
+		stopJobs()
 		f.kafkaProducer.Close()
 		f.aiProducer.Close()
+		f.eventsProducer.Close()
 		f.pool.Close()
 		f.redisClient.Close()
 		return err
@@ -171,12 +284,15 @@ func (f *FinData) runRouter() error {
 		defer cancel()
 
 		if err := f.srv.Shutdown(ctx); err != nil {
+			stopJobs()
 			f.srv.Close()
 			return err
 		}
 
+		stopJobs()
 		f.kafkaProducer.Close()
 		f.aiProducer.Close()
+		f.eventsProducer.Close()
 		f.pool.Close()
 		f.redisClient.Close()
 		slog.Info("Server stopped gracefully")
@@ -185,6 +301,23 @@ func (f *FinData) runRouter() error {
 	return nil
 }
 
+// runJobs starts the background jobs; they stop when ctx is cancelled.
+func (f *FinData) runJobs(ctx context.Context) {
+	go f.candlesService.RunSync(ctx, f.candlesSync)
+	go f.dividendImport.RunImport(ctx, f.dividendsSync)
+	go f.companyRefresh.RunRefresh(ctx, f.companiesSync)
+	go f.macroSeries.RunImport(ctx, f.macroSync)
+	go f.currentRatios.RunRefresh(ctx, f.ratiosRefresh)
+}
+
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "financial-data"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
 func getEnv(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
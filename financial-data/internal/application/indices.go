@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"time"
+)
+
+// IndexService serves the MOEX indices of the catalog and compares shares with
+// their sector index and the broad market. Index candles live in the candles
+// store next to the shares.
+type IndexService struct {
+	prices        routers.PriceHistory
+	companyRepo   routers.CompanyRepository
+	dividendsRepo routers.DividendsRepository
+	sharesRepo    routers.ShareEventsRepository
+}
+
+func NewIndexService(prices routers.PriceHistory, companyRepo routers.CompanyRepository, dividendsRepo routers.DividendsRepository, sharesRepo routers.ShareEventsRepository) *IndexService {
+	return &IndexService{
+		prices:        prices,
+		companyRepo:   companyRepo,
+		dividendsRepo: dividendsRepo,
+		sharesRepo:    sharesRepo,
+	}
+}
+
+func (s *IndexService) Catalog() []domain.IndexInfo {
+	return domain.Indices()
+}
+
+func (s *IndexService) GetCandles(ctx context.Context, index string, interval domain.Period, from, till time.Time) ([]domain.Candle, error) {
+	if _, ok := domain.LookupIndex(index); !ok {
+		return nil, fmt.Errorf("unknown index %s: %w", index, domain.ErrNotFound)
+	}
+	return s.prices.GetIndexCandles(ctx, index, interval, from, till)
+}
+
+func (s *IndexService) Performance(ctx context.Context, index string, from, till time.Time) (*domain.Performance, error) {
+	candles, err := s.GetCandles(ctx, index, domain.Period1D, from, till)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPerformance(index, candles)
+}
+
+// Compare measures the share against its sector index, when the sector has
+// one, and IMOEX over [from, till]. The price return is adjusted for splits
+// only; the total return adds dividends reinvested at the close.
+func (s *IndexService) Compare(ctx context.Context, ticker string, from, till time.Time) (*domain.PerformanceComparison, error) {
+	company, err := s.companyRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	candles, err := s.prices.GetCandles(ctx, ticker, domain.Period1D, from, till)
+	if err != nil {
+		return nil, fmt.Errorf("performance: failed to get candles of %s: %w", ticker, err)
+	}
+	events, err := s.sharesRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("performance: failed to get share events: %w", err)
+	}
+	dividends, err := s.dividendsRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("performance: failed to get dividends: %w", err)
+	}
+
+	splitAdjusted := domain.AdjustCandles(candles, events, nil)
+	stock := make([]domain.Candle, len(splitAdjusted))
+	for i, c := range splitAdjusted {
+		stock[i] = domain.Candle{Begin: c.Begin, Close: c.AdjustedClose}
+	}
+
+	sectorIndex, _ := domain.SectorIndex(domain.Sector(company.SectorID))
+	var sector []domain.Candle
+	if sectorIndex != "" {
+		sector, err = s.prices.GetIndexCandles(ctx, sectorIndex, domain.Period1D, from, till)
+		if err != nil {
+			return nil, fmt.Errorf("performance: failed to get candles of %s: %w", sectorIndex, err)
+		}
+	}
+	market, err := s.prices.GetIndexCandles(ctx, domain.IndexIMOEX, domain.Period1D, from, till)
+	if err != nil {
+		return nil, fmt.Errorf("performance: failed to get candles of %s: %w", domain.IndexIMOEX, err)
+	}
+
+	comparison, err := domain.ComparePerformance(ticker, stock, sectorIndex, sector, domain.IndexIMOEX, market)
+	if err != nil {
+		return nil, err
+	}
+
+	withDividends := domain.AdjustCandles(candles, events, dividends)
+	totalReturn := make([]domain.Candle, len(withDividends))
+	for i, c := range withDividends {
+		totalReturn[i] = domain.Candle{Begin: c.Begin, Close: c.TotalReturnIndex}
+	}
+	if p, err := domain.NewPerformance(ticker, totalReturn); err == nil {
+		comparison.TotalReturn = p.Return
+	}
+
+	return comparison, nil
+}
+
+func (s *IndexService) Sync(ctx context.Context, index string, full bool) error {
+	if _, ok := domain.LookupIndex(index); !ok {
+		return fmt.Errorf("unknown index %s: %w", index, domain.ErrNotFound)
+	}
+	return s.prices.SyncIndex(ctx, index, full)
+}
+
+func (s *IndexService) SyncAll(ctx context.Context, full bool) error {
+	return s.prices.SyncIndices(ctx, full)
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"financial_data/internal/application/routers"
+	"log/slog"
+	"time"
+)
+
+// JobsLeader keeps renewing the jobs lease so that the background jobs run on
+// one replica at a time.
+type JobsLeader struct {
+	lease    routers.JobsLease
+	interval time.Duration
+	leader   bool
+
+	// endTerm cancels the context of the running jobs once the lease is lost.
+	endTerm context.CancelFunc
+}
+
+func NewJobsLeader(lease routers.JobsLease, interval time.Duration) *JobsLeader {
+	return &JobsLeader{lease: lease, interval: interval}
+}
+
+// Run blocks until ctx is done and releases the lease before returning. jobs
+// is started in its own goroutine every time this replica takes the lease; its
+// context is cancelled when the lease is lost.
+func (l *JobsLeader) Run(ctx context.Context, jobs func(ctx context.Context)) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	l.tick(ctx, jobs)
+	for {
+		select {
+		case <-ctx.Done():
+			l.stopTerm()
+			l.resign()
+			return
+		case <-ticker.C:
+			l.tick(ctx, jobs)
+		}
+	}
+}
+
+func (l *JobsLeader) tick(ctx context.Context, jobs func(ctx context.Context)) {
+	acquired, err := l.lease.Acquire(ctx)
+	if err != nil {
+		slog.Error("jobs: failed to acquire lease", "error", err)
+		acquired = false
+	}
+
+	wasLeader := l.leader
+	l.leader = acquired
+	switch {
+	case acquired && !wasLeader:
+		slog.Info("jobs: lease acquired, starting background jobs")
+		termCtx, cancel := context.WithCancel(ctx)
+		l.endTerm = cancel
+		go jobs(termCtx)
+	case !acquired && wasLeader:
+		slog.Warn("jobs: lease lost, stopping background jobs")
+		l.stopTerm()
+	}
+}
+
+func (l *JobsLeader) stopTerm() {
+	if l.endTerm != nil {
+		l.endTerm()
+		l.endTerm = nil
+	}
+}
+
+func (l *JobsLeader) resign() {
+	if !l.leader {
+		return
+	}
+	l.leader = false
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := l.lease.Release(ctx); err != nil {
+		slog.Error("jobs: failed to release lease", "error", err)
+		return
+	}
+	slog.Info("jobs: lease released")
+}
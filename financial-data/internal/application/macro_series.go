@@ -0,0 +1,307 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// keyRateSince is the day the Bank of Russia introduced the key rate.
+var keyRateSince = time.Date(2013, time.September, 13, 0, 0, 0, 0, time.UTC)
+
+// macroImportChunk bounds the range of a single request to cbr.ru.
+const macroImportChunk = 2 * 365 * 24 * time.Hour
+
+// yieldCurveImportDays bounds the scheduled yield curve import to recent trade
+// dates: ISS serves one date per request, so older curves are loaded only by
+// an explicit backfill.
+const yieldCurveImportDays = 30
+
+// yieldCurveMaxAge is how old a stored curve may be to be served for the asked
+// date; a few days cover weekends and holidays.
+const yieldCurveMaxAge = 5 * 24 * time.Hour
+
+// MacroSeriesService imports macroeconomic series and serves their history.
+// The key rate keeps living in cb_rates as the dates it changed; every other
+// series is stored in macro_series.
+type MacroSeriesService struct {
+	cbRates     routers.MacroDataRepository
+	series      routers.MacroSeriesRepository
+	provider    domain.MacroProvider
+	events      routers.EventPublisher
+	historyFrom time.Time
+}
+
+func NewMacroSeriesService(cbRates routers.MacroDataRepository, series routers.MacroSeriesRepository, provider domain.MacroProvider, events routers.EventPublisher, historyFrom time.Time) *MacroSeriesService {
+	return &MacroSeriesService{
+		cbRates:     cbRates,
+		series:      series,
+		provider:    provider,
+		events:      events,
+		historyFrom: historyFrom,
+	}
+}
+
+func (s *MacroSeriesService) Catalog() []domain.MacroSeriesInfo {
+	return domain.MacroSeriesCatalog()
+}
+
+// History returns points of the series within [from, till], earliest first. The
+// key rate starts with the rate in force on from, so the range is never empty
+// once the rate is known.
+func (s *MacroSeriesService) History(ctx context.Context, series domain.MacroSeries, from, till time.Time) ([]domain.MacroPoint, error) {
+	if _, ok := domain.LookupMacroSeries(series); !ok {
+		return nil, fmt.Errorf("unknown macro series %s: %w", series, domain.ErrNotFound)
+	}
+	if series != domain.SeriesKeyRate {
+		return s.series.GetRange(ctx, series, from, till)
+	}
+
+	points := make([]domain.MacroPoint, 0)
+	inForce, err := s.cbRates.GetAsOf(ctx, from)
+	switch {
+	case err == nil:
+		points = append(points, keyRatePoint(*inForce))
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	}
+
+	rates, err := s.cbRates.GetHistory(ctx, from, till)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	// history comes latest first
+	for i := len(rates) - 1; i >= 0; i-- {
+		if inForce != nil && rates[i].Date.Equal(inForce.Date) {
+			continue
+		}
+		points = append(points, keyRatePoint(rates[i]))
+	}
+	return points, nil
+}
+
+func (s *MacroSeriesService) Latest(ctx context.Context, series domain.MacroSeries) (*domain.MacroPoint, error) {
+	if _, ok := domain.LookupMacroSeries(series); !ok {
+		return nil, fmt.Errorf("unknown macro series %s: %w", series, domain.ErrNotFound)
+	}
+	if series == domain.SeriesKeyRate {
+		rate, err := s.cbRates.GetCurrent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		point := keyRatePoint(*rate)
+		return &point, nil
+	}
+	return s.series.GetAsOf(ctx, series, time.Now())
+}
+
+// YieldCurve returns the stored OFZ curve of the latest trade date on or before
+// date. Curves are only loaded by the import, so a date it has not reached is
+// not found rather than fetched from ISS on a read.
+func (s *MacroSeriesService) YieldCurve(ctx context.Context, date time.Time) (*domain.YieldCurve, error) {
+	if date.After(time.Now()) {
+		return nil, fmt.Errorf("yield curve date %s is in the future: %w", date.Format("2006-01-02"), domain.ErrInvalidInput)
+	}
+
+	points, err := s.series.GetYieldCurve(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	if date.Sub(points[0].Date) > yieldCurveMaxAge {
+		return nil, fmt.Errorf("no yield curve stored within %s before %s, the latest is of %s: %w",
+			yieldCurveMaxAge, date.Format("2006-01-02"), points[0].Date.Format("2006-01-02"), domain.ErrNotFound)
+	}
+
+	return domain.NewYieldCurve(points), nil
+}
+
+// Import loads new points of the series and returns how many were stored. Any
+// OFZ series imports the whole yield curve.
+func (s *MacroSeriesService) Import(ctx context.Context, series domain.MacroSeries) (int, error) {
+	if _, ok := domain.LookupMacroSeries(series); !ok {
+		return 0, fmt.Errorf("unknown macro series %s: %w", series, domain.ErrNotFound)
+	}
+
+	switch series {
+	case domain.SeriesKeyRate:
+		return s.importKeyRate(ctx)
+	case domain.SeriesCPI, domain.SeriesUSDRUB, domain.SeriesCNYRUB, domain.SeriesRUONIA:
+		return s.importSeries(ctx, series)
+	default:
+		return s.importYieldCurve(ctx)
+	}
+}
+
+func (s *MacroSeriesService) ImportAll(ctx context.Context) error {
+	series := []domain.MacroSeries{
+		domain.SeriesKeyRate, domain.SeriesCPI, domain.SeriesUSDRUB, domain.SeriesCNYRUB, domain.SeriesRUONIA,
+		domain.OFZSeries(domain.OFZTenors[0]),
+	}
+
+	var imported int
+	for _, name := range series {
+		count, err := s.Import(ctx, name)
+		if err != nil {
+			slog.Error("macro: failed to import", "series", name, "error", err)
+			continue
+		}
+		imported += count
+	}
+
+	slog.Info("macro: imported all", "points", imported)
+	return nil
+}
+
+// RunImport imports every series right away and then every period until ctx is done.
+func (s *MacroSeriesService) RunImport(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		if err := s.ImportAll(ctx); err != nil {
+			slog.Error("macro: scheduled import failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// importKeyRate stores the days the key rate changed since the last stored
+// change and publishes an event for each of them. The first import into an
+// empty table loads the whole history without events.
+func (s *MacroSeriesService) importKeyRate(ctx context.Context) (int, error) {
+	current, err := s.cbRates.GetCurrent(ctx)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return 0, err
+	}
+
+	from := keyRateSince
+	if current != nil {
+		from = current.Date
+	}
+
+	var rates []domain.CBRate
+	for chunkFrom := from; !chunkFrom.After(time.Now()); chunkFrom = chunkFrom.Add(macroImportChunk) {
+		chunk, err := s.provider.GetKeyRates(chunkFrom, chunkFrom.Add(macroImportChunk-24*time.Hour))
+		if err != nil {
+			return 0, fmt.Errorf("failed to load key rate from cbr.ru: %w", err)
+		}
+		rates = append(rates, chunk...)
+	}
+
+	changes := domain.KeyRateChanges(rates, current)
+	for _, rate := range changes {
+		if err := s.cbRates.Upsert(ctx, &rate); err != nil {
+			return 0, err
+		}
+
+		if current != nil {
+			slog.Info("macro: key rate changed", "date", rate.Date.Format("2006-01-02"), "rate", rate.Rate, "previous", current.Rate)
+			if err := s.events.PublishKeyRateChanged(ctx, rate, *current); err != nil {
+				slog.Error("macro: failed to publish key rate change", "date", rate.Date.Format("2006-01-02"), "error", err)
+			}
+		}
+		current = &rate
+	}
+
+	slog.Info("macro: imported", "series", domain.SeriesKeyRate, "from", from.Format("2006-01-02"), "changes", len(changes))
+	return len(changes), nil
+}
+
+// importSeries re-reads the series from its last stored point, since recent
+// values may be revised, or from the start of the history.
+func (s *MacroSeriesService) importSeries(ctx context.Context, series domain.MacroSeries) (int, error) {
+	from, err := s.lastDate(ctx, series)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for chunkFrom := from; !chunkFrom.After(time.Now()); chunkFrom = chunkFrom.Add(macroImportChunk) {
+		points, err := s.provider.GetSeries(series, chunkFrom, chunkFrom.Add(macroImportChunk-24*time.Hour))
+		if err != nil {
+			return count, fmt.Errorf("failed to load %s: %w", series, err)
+		}
+		if err := s.series.Upsert(ctx, points); err != nil {
+			return count, err
+		}
+		count += len(points)
+	}
+
+	slog.Info("macro: imported", "series", series, "from", from.Format("2006-01-02"), "count", count)
+	return count, nil
+}
+
+// importYieldCurve loads the curve of every weekday after the last stored one,
+// but not further back than yieldCurveImportDays.
+func (s *MacroSeriesService) importYieldCurve(ctx context.Context) (int, error) {
+	recent := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -yieldCurveImportDays)
+	from, err := s.series.GetLastDate(ctx, domain.OFZSeries(domain.OFZTenors[0]))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		from = recent
+	case err != nil:
+		return 0, err
+	case from.Before(recent):
+		slog.Warn("macro: yield curve is behind the scheduled import window, backfill the gap", "last", from.Format("2006-01-02"), "from", recent.Format("2006-01-02"))
+		from = recent
+	}
+
+	return s.loadYieldCurves(ctx, from, time.Now())
+}
+
+// BackfillYieldCurve loads the curve of every weekday in [from, till]. It makes
+// a request to ISS per date, so a long range takes a while.
+func (s *MacroSeriesService) BackfillYieldCurve(ctx context.Context, from, till time.Time) (int, error) {
+	if from.After(till) {
+		return 0, fmt.Errorf("from %s is after till %s: %w", from.Format("2006-01-02"), till.Format("2006-01-02"), domain.ErrInvalidInput)
+	}
+	if now := time.Now(); till.After(now) {
+		till = now
+	}
+	return s.loadYieldCurves(ctx, from, till)
+}
+
+func (s *MacroSeriesService) loadYieldCurves(ctx context.Context, from, till time.Time) (int, error) {
+	var count int
+	for date := from; !date.After(till); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		points, err := s.provider.GetYieldCurve(date)
+		if err != nil {
+			return count, fmt.Errorf("failed to load OFZ yield curve on %s: %w", date.Format("2006-01-02"), err)
+		}
+		if err := s.series.Upsert(ctx, points); err != nil {
+			return count, err
+		}
+		count += len(points)
+	}
+
+	slog.Info("macro: imported", "series", "ofz", "from", from.Format("2006-01-02"), "till", till.Format("2006-01-02"), "count", count)
+	return count, nil
+}
+
+func (s *MacroSeriesService) lastDate(ctx context.Context, series domain.MacroSeries) (time.Time, error) {
+	last, err := s.series.GetLastDate(ctx, series)
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.historyFrom, nil
+	}
+	return last, err
+}
+
+func keyRatePoint(rate domain.CBRate) domain.MacroPoint {
+	return domain.MacroPoint{Series: domain.SeriesKeyRate, Date: rate.Date, Value: rate.Rate}
+}
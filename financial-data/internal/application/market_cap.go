@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"time"
+)
+
+// ShareAwareMarket wraps a MarketService so that market caps use the share count
+// history instead of the current ISSUESIZE. Tickers without history fall back
+// to ISSUESIZE.
+type ShareAwareMarket struct {
+	domain.MarketService
+	shares routers.ShareEventsRepository
+}
+
+func NewShareAwareMarket(market domain.MarketService, shares routers.ShareEventsRepository) *ShareAwareMarket {
+	return &ShareAwareMarket{MarketService: market, shares: shares}
+}
+
+func (m *ShareAwareMarket) GetMarketCap(ctx context.Context, ticker string) (float64, error) {
+	price, err := m.GetStockPrice(ticker, 5, domain.Period1H)
+	if err != nil {
+		return 0, err
+	}
+	if len(price) == 0 {
+		return 0, fmt.Errorf("no recent candles for %s", ticker)
+	}
+
+	shares, err := m.sharesAt(ctx, ticker, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return price[len(price)-1].Close * float64(shares), nil
+}
+
+// GetMarketCapAt is the close on (or shortly before) date times the shares outstanding on that date.
+func (m *ShareAwareMarket) GetMarketCapAt(ctx context.Context, ticker string, date time.Time) (float64, error) {
+	price, err := m.GetPriceAt(ticker, date)
+	if err != nil {
+		return 0, err
+	}
+
+	shares, err := m.sharesAt(ctx, ticker, date)
+	if err != nil {
+		return 0, err
+	}
+
+	return price * float64(shares), nil
+}
+
+func (m *ShareAwareMarket) sharesAt(ctx context.Context, ticker string, date time.Time) (int64, error) {
+	shares, err := m.shares.GetSharesAt(ctx, ticker, date)
+	if err == nil {
+		return shares, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return 0, err
+	}
+
+	stockInfo, err := m.GetStockInfo(ticker)
+	if err != nil {
+		return 0, err
+	}
+	return int64(stockInfo.NumberOfShares), nil
+}
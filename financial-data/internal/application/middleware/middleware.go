@@ -15,8 +15,25 @@ type contextKey string
 
 const (
 	contextKeyAPIKey = contextKey("api_key")
+	contextKeyActor  = contextKey("actor")
 )
 
+// ActorHeader names who makes a change with the admin key, e.g.
+// "ai-service:task:<id>". Requests without it are attributed to "admin".
+const (
+	ActorHeader  = "X-Actor"
+	defaultActor = "admin"
+	maxActorLen  = 100
+)
+
+// ActorFromContext returns the actor of an authenticated request.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(contextKeyActor).(string); ok && actor != "" {
+		return actor
+	}
+	return defaultActor
+}
+
 type MiddlewareConfig struct {
 	AdminAPIKey    string
 	allowedOrigins map[string]struct{}
@@ -72,7 +89,16 @@ func (m *MiddlewareConfig) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+		if actor == "" {
+			actor = defaultActor
+		}
+		if len(actor) > maxActorLen {
+			actor = actor[:maxActorLen]
+		}
+
 		ctx := context.WithValue(r.Context(), contextKeyAPIKey, apiKey)
+		ctx = context.WithValue(ctx, contextKeyActor, actor)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
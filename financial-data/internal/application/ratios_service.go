@@ -13,13 +13,15 @@ type RatiosService struct {
 	rawDataRepo routers.RawDataRepository
 	ratiosRepo  routers.RatiosRepository
 	companyRepo routers.CompanyRepository
+	sharesRepo  routers.ShareEventsRepository
 }
 
-func NewRatiosService(rawDataRepo routers.RawDataRepository, ratiosRepo routers.RatiosRepository, companyRepo routers.CompanyRepository) *RatiosService {
+func NewRatiosService(rawDataRepo routers.RawDataRepository, ratiosRepo routers.RatiosRepository, companyRepo routers.CompanyRepository, sharesRepo routers.ShareEventsRepository) *RatiosService {
 	return &RatiosService{
 		rawDataRepo: rawDataRepo,
 		ratiosRepo:  ratiosRepo,
 		companyRepo: companyRepo,
+		sharesRepo:  sharesRepo,
 	}
 }
 
@@ -35,25 +37,130 @@ func (s *RatiosService) CalculateAndSave(ctx context.Context, rawData *domain.Ra
 		previous = nil
 	}
 
+	rawData = s.withHistoricalShares(ctx, rawData, rawData.Period)
+
 	ratios := domain.CalculateRatios(rawData, previous)
 	ratios.Ticker = rawData.Ticker
 	ratios.Year = rawData.Year
 	ratios.Period = rawData.Period
 
-	err = s.ratiosRepo.Update(ctx, ratios)
-	if errors.Is(err, domain.ErrNotFound) {
-		err = s.ratiosRepo.Create(ctx, domain.Sector(company.SectorID), ratios)
+	if err := s.save(ctx, domain.Sector(company.SectorID), ratios); err != nil {
+		slog.Error("ratios: failed to save ratios", "ticker", rawData.Ticker, "error", err)
+		return err
 	}
 
+	slog.Info("ratios: calculated and saved", "ticker", rawData.Ticker, "year", rawData.Year, "period", rawData.Period)
+
+	s.saveForShareClasses(ctx, rawData.Ticker, ratios)
+
+	// the report is part of the TTM of its own year and of the next one
+	history, err := s.rawDataRepo.GetHistoryByTicker(ctx, rawData.Ticker)
 	if err != nil {
-		slog.Error("ratios: failed to save ratios", "ticker", rawData.Ticker, "error", err)
+		slog.Warn("ratios: failed to get history for TTM", "ticker", rawData.Ticker, "error", err)
+		return nil
+	}
+	for _, year := range []int{rawData.Year, rawData.Year + 1} {
+		if err := s.calculateTTM(ctx, company, history, year); err != nil {
+			slog.Warn("ratios: failed to save TTM ratios", "ticker", rawData.Ticker, "year", year, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// calculateTTM saves ratios over the trailing twelve months ending at the
+// latest report of year that allows it, with growth against the TTM a year
+// earlier ending at the same period. Years without enough reports are skipped.
+func (s *RatiosService) calculateTTM(ctx context.Context, company *domain.Company, history []domain.RawData, year int) error {
+	ttm, through, ok := domain.LatestTTM(history, year)
+	if !ok {
+		return nil
+	}
+	ttm = s.withHistoricalShares(ctx, ttm, through)
+
+	previous, ok := domain.BuildTTM(history, year-1, through)
+	if !ok {
+		previous = nil
+	}
+
+	ratios := domain.CalculateRatios(ttm, previous)
+	ratios.Ticker = company.Ticker
+	ratios.Year = year
+	ratios.Period = domain.TTM
+	ratios.TTMThrough = &through
+
+	if err := s.save(ctx, domain.Sector(company.SectorID), ratios); err != nil {
 		return err
 	}
 
-	slog.Info("ratios: calculated and saved", "ticker", rawData.Ticker, "year", rawData.Year, "period", rawData.Period)
+	slog.Info("ratios: calculated and saved TTM", "ticker", company.Ticker, "year", year, "through", through)
+
+	s.saveForShareClasses(ctx, company.Ticker, ratios)
 	return nil
 }
 
+// withHistoricalShares applies the share count at the end of period: raw data
+// carries the count known at extraction time, which is wrong for periods
+// before a split, buyback or issue.
+func (s *RatiosService) withHistoricalShares(ctx context.Context, rawData *domain.RawData, period domain.ReportPeriod) *domain.RawData {
+	periodEnd := domain.PeriodEndDate(rawData.Year, period)
+	shares, err := s.sharesRepo.GetSharesAt(ctx, rawData.Ticker, periodEnd)
+	switch {
+	case err == nil:
+		return rawData.WithSharesOutstanding(shares)
+	case !errors.Is(err, domain.ErrNotFound):
+		slog.Warn("ratios: failed to get share count, using reported one", "ticker", rawData.Ticker, "error", err)
+	}
+	return rawData
+}
+
+func (s *RatiosService) save(ctx context.Context, sector domain.Sector, ratios *domain.Ratios) error {
+	err := s.ratiosRepo.Update(ctx, ratios)
+	if errors.Is(err, domain.ErrNotFound) {
+		err = s.ratiosRepo.Create(ctx, sector, ratios)
+	}
+	return err
+}
+
+// saveForShareClasses copies the issuer's ratios to its other share classes
+// (e.g. SBERP for SBER): they are computed from the same reports. Dividend per
+// share and yield differ between classes, and the market cap, EV and the
+// multiples built on them are priced off the issuer's share, so only the
+// ratios that do not depend on the price are copied.
+func (s *RatiosService) saveForShareClasses(ctx context.Context, issuer string, ratios *domain.Ratios) {
+	companies, err := s.companyRepo.GetAll(ctx)
+	if err != nil {
+		slog.Error("ratios: failed to get share classes", "ticker", issuer, "error", err)
+		return
+	}
+
+	for _, company := range companies {
+		if company.IssuerTicker != issuer {
+			continue
+		}
+
+		classRatios := *ratios
+		classRatios.Ticker = company.Ticker
+		classRatios.DividendPerShare = nil
+		classRatios.DividendYield = nil
+		classRatios.PriceToEarnings = nil
+		classRatios.PriceToBook = nil
+		classRatios.PriceToCashFlow = nil
+		classRatios.EVToEBITDA = nil
+		classRatios.EVToSales = nil
+		classRatios.EVToFCF = nil
+		classRatios.PEG = nil
+		classRatios.MarketCap = nil
+		classRatios.EnterpriseValue = nil
+
+		if err := s.save(ctx, domain.Sector(company.SectorID), &classRatios); err != nil {
+			slog.Error("ratios: failed to save share class ratios", "ticker", company.Ticker, "issuer", issuer, "error", err)
+			continue
+		}
+		slog.Info("ratios: saved for share class", "ticker", company.Ticker, "issuer", issuer, "year", ratios.Year, "period", ratios.Period)
+	}
+}
+
 func (s *RatiosService) RecalculateAll(ctx context.Context, ticker string) error {
 	history, err := s.rawDataRepo.GetHistoryByTicker(ctx, ticker)
 	if err != nil {
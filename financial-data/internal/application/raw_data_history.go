@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+)
+
+// RawDataHistory is the change history of raw data kept in versions, aware of
+// reports stored before the history was introduced.
+type RawDataHistory struct {
+	routers.RawDataVersionsRepository
+	rawDataRepo routers.RawDataRepository
+	tx          routers.Transactor
+}
+
+func NewRawDataHistory(versions routers.RawDataVersionsRepository, rawDataRepo routers.RawDataRepository, tx routers.Transactor) *RawDataHistory {
+	return &RawDataHistory{RawDataVersionsRepository: versions, rawDataRepo: rawDataRepo, tx: tx}
+}
+
+// Change runs change and appends the report state it returns as a new version
+// in one transaction, so a report is never changed without its version.
+// Changes of the same report wait for each other. change returns nil when
+// there is nothing to record; its errors are returned as is.
+func (h *RawDataHistory) Change(ctx context.Context, ticker string, year int, period domain.ReportPeriod, action domain.RawDataAction, actor string,
+	change func(ctx context.Context) (*domain.RawData, error)) (*domain.RawDataVersion, error) {
+	var version *domain.RawDataVersion
+	err := h.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := h.LockReport(ctx, ticker, year, period); err != nil {
+			return err
+		}
+		if err := h.ensureBaseline(ctx, ticker, year, period); err != nil {
+			return err
+		}
+
+		rawData, err := change(ctx)
+		if err != nil || rawData == nil {
+			return err
+		}
+
+		version, err = h.Append(ctx, rawData, action, actor)
+		if err != nil {
+			return fmt.Errorf("failed to save raw data version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// ensureBaseline saves the stored state of a report changed for the first time
+// since versioning was introduced, so that its first change has something to
+// diff against.
+func (h *RawDataHistory) ensureBaseline(ctx context.Context, ticker string, year int, period domain.ReportPeriod) error {
+	versions, err := h.GetVersions(ctx, ticker, year, period)
+	if err != nil {
+		return fmt.Errorf("failed to get raw data versions: %w", err)
+	}
+	if len(versions) > 0 {
+		return nil
+	}
+
+	stored, err := loadStoredRawData(ctx, h.rawDataRepo, ticker, year, period)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to load raw data for baseline version: %w", err)
+	}
+
+	if _, err := h.Append(ctx, stored, domain.RawDataActionBaseline, "system"); err != nil {
+		return fmt.Errorf("failed to save baseline raw data version: %w", err)
+	}
+	return nil
+}
+
+// loadStoredRawData returns the stored report: the pending draft if there is
+// one, since it is what would be confirmed next, otherwise the confirmed one.
+func loadStoredRawData(ctx context.Context, repo routers.RawDataRepository, ticker string, year int, period domain.ReportPeriod) (*domain.RawData, error) {
+	draft, err := repo.GetDraftByTickerAndPeriod(ctx, ticker, year, period)
+	if err != nil {
+		return nil, err
+	}
+	if draft != nil {
+		return draft, nil
+	}
+	return repo.GetByTickerAndPeriod(ctx, ticker, year, period)
+}
@@ -0,0 +1,195 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// RawDataImporter saves reports decoded from an uploaded file row by row. Every
+// row is checked like a single report sent to the API; rows that fail are
+// reported and skipped without stopping the rest of the import.
+type RawDataImporter struct {
+	rawDataRepo   routers.RawDataRepository
+	companyRepo   routers.CompanyRepository
+	versions      routers.RawDataHistory
+	ratiosService routers.RatiosCalculator
+}
+
+func NewRawDataImporter(rawDataRepo routers.RawDataRepository, companyRepo routers.CompanyRepository, versions routers.RawDataHistory, ratiosService routers.RatiosCalculator) *RawDataImporter {
+	return &RawDataImporter{
+		rawDataRepo:   rawDataRepo,
+		companyRepo:   companyRepo,
+		versions:      versions,
+		ratiosService: ratiosService,
+	}
+}
+
+// tickerState is what the import knows about a ticker: the stored reports
+// merged with rows of the file accepted so far, so that a dry run validates
+// units against the same history as the real import would.
+type tickerState struct {
+	err     error
+	history []domain.RawData
+	stored  map[string]bool
+}
+
+func reportKey(year int, period domain.ReportPeriod) string {
+	return fmt.Sprintf("%d-%s", year, period)
+}
+
+// Import processes rows in file order. With dryRun set nothing is written.
+// Ratios of tickers that got confirmed reports are recalculated in the background.
+func (i *RawDataImporter) Import(ctx context.Context, rows []domain.RawDataFileRow, dryRun bool, actor string) (*domain.RawDataImportResult, error) {
+	result := &domain.RawDataImportResult{DryRun: dryRun, Rows: make([]domain.RawDataImportRow, 0, len(rows))}
+	tickers := make(map[string]*tickerState)
+	var recalculate []string
+
+	for _, row := range rows {
+		outcome := domain.RawDataImportRow{Row: row.Row, Outcome: domain.ImportRejected}
+		if row.Err != nil {
+			outcome.Error = row.Err.Error()
+			result.Add(outcome)
+			continue
+		}
+
+		rawData := row.Data
+		outcome.Ticker, outcome.Year, outcome.Period = rawData.Ticker, rawData.Year, rawData.Period
+		if err := checkImportedReport(rawData); err != nil {
+			outcome.Error = err.Error()
+			result.Add(outcome)
+			continue
+		}
+
+		state, ok := tickers[rawData.Ticker]
+		if !ok {
+			state = i.loadTicker(ctx, rawData.Ticker)
+			tickers[rawData.Ticker] = state
+		}
+		if state.err != nil {
+			outcome.Error = state.err.Error()
+			result.Add(outcome)
+			continue
+		}
+
+		others := make([]domain.RawData, 0, len(state.history))
+		for _, h := range state.history {
+			if h.Year != rawData.Year || h.Period != rawData.Period {
+				others = append(others, h)
+			}
+		}
+		validation := domain.ValidateRawData(rawData, others)
+		validation.ApplyStatus(rawData)
+		outcome.Validation = validation
+		outcome.ReportStatus = rawData.Status
+
+		key := reportKey(rawData.Year, rawData.Period)
+		exists := state.stored[key]
+		if !dryRun {
+			if err := i.save(ctx, rawData, exists, actor); err != nil {
+				slog.Error("raw data import: failed to save row", "row", row.Row, "ticker", rawData.Ticker, "year", rawData.Year, "period", rawData.Period, "error", err)
+				outcome.Error = err.Error()
+				result.Add(outcome)
+				continue
+			}
+		}
+
+		outcome.Outcome = domain.ImportCreated
+		if exists {
+			outcome.Outcome = domain.ImportUpdated
+		}
+		result.Add(outcome)
+
+		// the row replaces the stored report, and only confirmed ones are a reference
+		state.stored[key] = true
+		state.history = others
+		if rawData.Status == domain.StatusConfirmed {
+			state.history = append(state.history, *rawData)
+			if !slices.Contains(recalculate, rawData.Ticker) {
+				recalculate = append(recalculate, rawData.Ticker)
+			}
+		}
+	}
+
+	if !dryRun && len(recalculate) > 0 {
+		go i.recalculate(recalculate)
+	}
+
+	slog.Info("raw data import finished", "dry_run", dryRun, "actor", actor, "total", result.Total, "created", result.Created, "updated", result.Updated, "rejected", result.Rejected)
+	return result, nil
+}
+
+func checkImportedReport(rawData *domain.RawData) error {
+	if rawData.Ticker == "" {
+		return errors.New("ticker is empty")
+	}
+	if rawData.Year < 1900 || rawData.Year > 2100 {
+		return fmt.Errorf("invalid year: %d", rawData.Year)
+	}
+	if !rawData.Period.IsValid() {
+		return fmt.Errorf("invalid period %q (allowed: Q1, Q2, Q3, Q4, YEAR)", rawData.Period)
+	}
+	if rawData.Status != "" && !rawData.Status.IsValid() {
+		return fmt.Errorf("invalid status %q (allowed: draft, confirmed)", rawData.Status)
+	}
+	return nil
+}
+
+func (i *RawDataImporter) loadTicker(ctx context.Context, ticker string) *tickerState {
+	if _, err := i.companyRepo.GetByTicker(ctx, ticker); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return &tickerState{err: fmt.Errorf("company %s not found", ticker)}
+		}
+		return &tickerState{err: fmt.Errorf("failed to get company: %w", err)}
+	}
+
+	history, err := i.rawDataRepo.GetHistoryByTicker(ctx, ticker)
+	if err != nil {
+		return &tickerState{err: fmt.Errorf("failed to get history: %w", err)}
+	}
+	drafts, err := i.rawDataRepo.GetDraftsByTicker(ctx, ticker)
+	if err != nil {
+		return &tickerState{err: fmt.Errorf("failed to get drafts: %w", err)}
+	}
+
+	state := &tickerState{history: history, stored: make(map[string]bool, len(history)+len(drafts))}
+	for _, h := range history {
+		state.stored[reportKey(h.Year, h.Period)] = true
+	}
+	for _, d := range drafts {
+		state.stored[reportKey(d.Year, d.Period)] = true
+	}
+	return state
+}
+
+func (i *RawDataImporter) save(ctx context.Context, rawData *domain.RawData, exists bool, actor string) error {
+	action, write := domain.RawDataActionCreate, i.rawDataRepo.Create
+	if exists {
+		action, write = domain.RawDataActionUpdate, i.rawDataRepo.Update
+	}
+
+	_, err := i.versions.Change(ctx, rawData.Ticker, rawData.Year, rawData.Period, action, actor, func(ctx context.Context) (*domain.RawData, error) {
+		if err := write(ctx, rawData); err != nil {
+			return nil, err
+		}
+		return rawData, nil
+	})
+	return err
+}
+
+func (i *RawDataImporter) recalculate(tickers []string) {
+	defer func() {
+		if rv := recover(); rv != nil {
+			slog.Error("panic in ratios calculation after import", "recover", rv)
+		}
+	}()
+	for _, ticker := range tickers {
+		if err := i.ratiosService.RecalculateAll(context.Background(), ticker); err != nil {
+			slog.Error("failed to recalculate ratios after import", "ticker", ticker, "error", err)
+		}
+	}
+}
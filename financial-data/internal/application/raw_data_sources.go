@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+	"log/slog"
+)
+
+// RawDataSourcesService keeps the figures of a report as published in each
+// source report. The canonical source is copied into metrics, so ratios and
+// growth of the following period are calculated from it.
+type RawDataSourcesService struct {
+	sources       routers.RawDataSourcesRepository
+	rawDataRepo   routers.RawDataRepository
+	validator     routers.RawDataValidator
+	versions      routers.RawDataHistory
+	ratiosService routers.RatiosCalculator
+	tx            routers.Transactor
+}
+
+func NewRawDataSourcesService(sources routers.RawDataSourcesRepository, rawDataRepo routers.RawDataRepository, validator routers.RawDataValidator, versions routers.RawDataHistory, ratiosService routers.RatiosCalculator, tx routers.Transactor) *RawDataSourcesService {
+	return &RawDataSourcesService{
+		sources:       sources,
+		rawDataRepo:   rawDataRepo,
+		validator:     validator,
+		versions:      versions,
+		ratiosService: ratiosService,
+		tx:            tx,
+	}
+}
+
+// AddSource saves a source of a report. The first source of a report that
+// was stored before sources were kept is preceded by the stored figures as the
+// original one. A source becomes canonical when asked to or when the report
+// has no canonical source yet. validation is set only for a canonical source.
+// Everything is saved in one transaction, so a failed copy into metrics leaves
+// neither the source nor the original behind.
+func (s *RawDataSourcesService) AddSource(ctx context.Context, source *domain.RawDataSource, makeCanonical bool, actor string) (*domain.ValidationReport, error) {
+	source.Data.Ticker, source.Data.Year, source.Data.Period = source.Ticker, source.Year, source.Period
+	if err := source.Validate(); err != nil {
+		return nil, err
+	}
+	source.SetKind()
+
+	var validation *domain.ValidationReport
+	var canonical *domain.RawData
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		// taken before the stored figures are read as the original
+		if err := s.versions.LockReport(ctx, source.Ticker, source.Year, source.Period); err != nil {
+			return err
+		}
+
+		existing, err := s.sources.GetByPeriod(ctx, source.Ticker, source.Year, source.Period)
+		if err != nil {
+			return err
+		}
+
+		hasCanonical := false
+		for _, e := range existing {
+			hasCanonical = hasCanonical || e.Canonical
+		}
+
+		if len(existing) == 0 && source.Kind == domain.SourceRestated {
+			baseline, err := s.saveStoredAsOriginal(ctx, source.Ticker, source.Year, source.Period)
+			if err != nil {
+				return err
+			}
+			hasCanonical = baseline
+		}
+
+		if err := s.sources.Save(ctx, source); err != nil {
+			return err
+		}
+
+		// a canonical source replaced with new figures has to be copied again
+		if !makeCanonical && hasCanonical && !source.Canonical {
+			return nil
+		}
+		validation, canonical, err = s.setCanonical(ctx, source.Ticker, source.ID, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if canonical != nil {
+		source.Canonical = true
+		s.recalculateRatios(canonical)
+	}
+	return validation, nil
+}
+
+// SetCanonical copies the source into metrics after the usual validation and
+// recalculates ratios of the ticker, since growth of the following period
+// depends on it as well.
+func (s *RawDataSourcesService) SetCanonical(ctx context.Context, ticker string, id int, actor string) (*domain.ValidationReport, error) {
+	validation, rawData, err := s.setCanonical(ctx, ticker, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	s.recalculateRatios(rawData)
+	return validation, nil
+}
+
+// setCanonical copies the source into metrics and returns the saved figures.
+func (s *RawDataSourcesService) setCanonical(ctx context.Context, ticker string, id int, actor string) (*domain.ValidationReport, *domain.RawData, error) {
+	source, err := s.sources.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.Ticker != ticker {
+		return nil, nil, fmt.Errorf("raw data source %d of %s not found: %w", id, ticker, domain.ErrNotFound)
+	}
+
+	rawData := source.Data
+	rawData.Ticker, rawData.Year, rawData.Period = source.Ticker, source.Year, source.Period
+
+	validation, err := s.validator.Validate(ctx, &rawData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to validate source: %w", err)
+	}
+	if validation.ApplyStatus(&rawData) {
+		slog.Warn("canonical raw data source failed validation, saving as draft", "ticker", rawData.Ticker, "year", rawData.Year, "period", rawData.Period, "source_id", id)
+	}
+
+	_, err = s.versions.Change(ctx, rawData.Ticker, rawData.Year, rawData.Period, domain.RawDataActionCanonical, actor, func(ctx context.Context) (*domain.RawData, error) {
+		err := s.rawDataRepo.Update(ctx, &rawData)
+		if errors.Is(err, domain.ErrNotFound) {
+			err = s.rawDataRepo.Create(ctx, &rawData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save canonical figures: %w", err)
+		}
+
+		if err := s.sources.SetCanonical(ctx, id); err != nil {
+			return nil, err
+		}
+		return &rawData, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return validation, &rawData, nil
+}
+
+// recalculateRatios recalculates ratios of the ticker in the background once
+// confirmed canonical figures are committed.
+func (s *RawDataSourcesService) recalculateRatios(rawData *domain.RawData) {
+	if rawData.Status == domain.StatusConfirmed {
+		go func() {
+			defer func() {
+				if rv := recover(); rv != nil {
+					slog.Error("panic in ratios calculation after canonical source change", "ticker", rawData.Ticker, "recover", rv)
+				}
+			}()
+			if err := s.ratiosService.RecalculateAll(context.Background(), rawData.Ticker); err != nil {
+				slog.Error("failed to recalculate ratios after canonical source change", "ticker", rawData.Ticker, "error", err)
+			}
+		}()
+	}
+}
+
+func (s *RawDataSourcesService) GetSources(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.RawDataSource, error) {
+	return s.sources.GetByPeriod(ctx, ticker, year, period)
+}
+
+// GetRestatements returns deltas of every restated report of the ticker, or of
+// a single report when period is set.
+func (s *RawDataSourcesService) GetRestatements(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.Restatement, error) {
+	var sources []domain.RawDataSource
+	var err error
+	if period != "" {
+		sources, err = s.sources.GetByPeriod(ctx, ticker, year, period)
+	} else {
+		sources, err = s.sources.GetByTicker(ctx, ticker)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return domain.Restatements(sources), nil
+}
+
+// saveStoredAsOriginal keeps the report stored in metrics as its original,
+// canonical source. It reports whether there was anything stored.
+func (s *RawDataSourcesService) saveStoredAsOriginal(ctx context.Context, ticker string, year int, period domain.ReportPeriod) (bool, error) {
+	stored, err := loadStoredRawData(ctx, s.rawDataRepo, ticker, year, period)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	original := &domain.RawDataSource{
+		Ticker:       ticker,
+		Year:         year,
+		Period:       period,
+		SourceYear:   year,
+		SourcePeriod: period,
+		Data:         *stored,
+	}
+	if err := s.sources.Save(ctx, original); err != nil {
+		return false, err
+	}
+	if err := s.sources.SetCanonical(ctx, original.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
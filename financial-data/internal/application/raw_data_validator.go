@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+)
+
+// RawDataValidator checks reports against accounting identities and against the
+// company's confirmed reports for unit consistency.
+type RawDataValidator struct {
+	rawDataRepo routers.RawDataRepository
+}
+
+func NewRawDataValidator(rawDataRepo routers.RawDataRepository) *RawDataValidator {
+	return &RawDataValidator{rawDataRepo: rawDataRepo}
+}
+
+func (v *RawDataValidator) Validate(ctx context.Context, rawData *domain.RawData) (*domain.ValidationReport, error) {
+	history, err := v.rawDataRepo.GetHistoryByTicker(ctx, rawData.Ticker)
+	if err != nil {
+		return nil, fmt.Errorf("validation: failed to get history: %w", err)
+	}
+
+	// the stored version of the same report is not a reference for itself
+	others := make([]domain.RawData, 0, len(history))
+	for _, h := range history {
+		if h.Year != rawData.Year || h.Period != rawData.Period {
+			others = append(others, h)
+		}
+	}
+
+	return domain.ValidateRawData(rawData, others), nil
+}
@@ -9,6 +9,7 @@ import (
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	Details any    `json:"details,omitempty"`
 }
 
 type SuccessResponse struct {
@@ -33,6 +34,22 @@ func RespondWithError(w http.ResponseWriter, r *http.Request, code int, message
 	})
 }
 
+// RespondWithErrorDetails is RespondWithError with a structured explanation for the client.
+func RespondWithErrorDetails(w http.ResponseWriter, r *http.Request, code int, message string, details any) {
+	slog.Warn("Request rejected",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", code,
+		"message", message,
+	)
+
+	RespondWithJSON(w, code, ErrorResponse{
+		Error:   http.StatusText(code),
+		Message: message,
+		Details: details,
+	})
+}
+
 func RespondWithJSON(w http.ResponseWriter, code int, payload any) {
 	response, err := json.Marshal(payload)
 	if err != nil {
@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"financial_data/internal/application/middleware"
@@ -9,6 +10,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -18,14 +20,15 @@ type CompanyHandler struct {
 	repo           CompanyRepository
 	marketService  domain.MarketService
 	eventPublisher EventPublisher
+	refresher      CompanyRefresher
 }
 
-func NewCompanyHandler(repo CompanyRepository, marketService domain.MarketService, eventPublisher EventPublisher) *CompanyHandler {
-	return &CompanyHandler{repo: repo, marketService: marketService, eventPublisher: eventPublisher}
+func NewCompanyHandler(repo CompanyRepository, marketService domain.MarketService, eventPublisher EventPublisher, refresher CompanyRefresher) *CompanyHandler {
+	return &CompanyHandler{repo: repo, marketService: marketService, eventPublisher: eventPublisher, refresher: refresher}
 }
 
-func RegisterCompanyRoutes(r chi.Router, repo CompanyRepository, marketService domain.MarketService, eventPublisher EventPublisher, m *middleware.MiddlewareConfig) {
-	handler := NewCompanyHandler(repo, marketService, eventPublisher)
+func RegisterCompanyRoutes(r chi.Router, repo CompanyRepository, marketService domain.MarketService, eventPublisher EventPublisher, refresher CompanyRefresher, m *middleware.MiddlewareConfig) {
+	handler := NewCompanyHandler(repo, marketService, eventPublisher, refresher)
 
 	r.Get("/companies", handler.HandleGetAll)
 	r.Get("/companies/{ticker}", handler.HandleGetByTicker)
@@ -35,6 +38,7 @@ func RegisterCompanyRoutes(r chi.Router, repo CompanyRepository, marketService d
 		protected.Use(m.AuthMiddleware)
 
 		protected.Post("/companies", handler.HandleCreate)
+		protected.Post("/companies/refresh", handler.HandleRefresh)
 		protected.Put("/companies/{ticker}", handler.HandleUpdate)
 		protected.Delete("/companies/{ticker}", handler.HandleDelete)
 	})
@@ -116,16 +120,40 @@ func (h *CompanyHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		response.RespondWithError(w, r, 400, "Company with this ticker is not traded on MOEX", err)
 		return
 	}
+	if stockInfo.Delisted() {
+		response.RespondWithError(w, r, 400, "Company with this ticker is delisted from MOEX", nil)
+		return
+	}
+	company.Enrich(*stockInfo, time.Now().UTC())
+
+	company.ApplyDefaults()
+	if !company.InstrumentType.IsValid() {
+		response.RespondWithError(w, r, 400, "invalid instrumentType (allowed: share, preferred, etf, bond)", nil)
+		return
+	}
 
-	if stockInfo != nil {
-		company.Name = stockInfo.Name
+	if company.IssuerTicker != "" {
+		if _, err := h.repo.GetByTicker(r.Context(), company.IssuerTicker); err != nil {
+			response.RespondWithError(w, r, 400, "issuer company is not registered", err)
+			return
+		}
 	}
 
 	if err := h.repo.Create(r.Context(), &company); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, "invalid company", err)
+			return
+		}
 		response.RespondWithError(w, r, 500, "failed to create company", err)
 		return
 	}
 
+	// share classes use the issuer's reports and analysis
+	if company.IssuerTicker != "" {
+		response.RespondWithSuccess(w, 201, company, "Company successfully created")
+		return
+	}
+
 	id := uuid.New().String()
 	if err := h.eventPublisher.PublishCompanyCreated(r.Context(), company.Ticker, company.Name, id); err != nil {
 		slog.Error("failed to publish company created event", "ticker", company.Ticker, "error", err)
@@ -142,6 +170,33 @@ func (h *CompanyHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	response.RespondWithSuccess(w, 201, company, "Company successfully created")
 }
 
+// HandleRefresh reloads company profiles from MOEX ISS: right away for one
+// ticker when it is given, otherwise for all companies in the background.
+func (h *CompanyHandler) HandleRefresh(w http.ResponseWriter, r *http.Request) {
+	ticker := r.URL.Query().Get("ticker")
+	if ticker != "" {
+		result, err := h.refresher.Refresh(r.Context(), ticker)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				response.RespondWithError(w, r, 404, "company not found", err)
+				return
+			}
+			response.RespondWithError(w, r, 500, "failed to refresh company", err)
+			return
+		}
+		response.RespondWithSuccess(w, 200, result, "Company refreshed")
+		return
+	}
+
+	go func() {
+		if err := h.refresher.RefreshAll(context.Background()); err != nil {
+			slog.Error("companies refresh failed", "error", err)
+		}
+	}()
+
+	response.RespondWithSuccess(w, 202, nil, "Companies refresh started")
+}
+
 func (h *CompanyHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	ticker := chi.URLParam(r, "ticker")
 	if ticker == "" {
@@ -166,6 +221,10 @@ func (h *CompanyHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 			response.RespondWithError(w, r, 404, "company not found", err)
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, "invalid company", err)
+			return
+		}
 		response.RespondWithError(w, r, 500, "failed to update company", err)
 		return
 	}
@@ -1,33 +1,44 @@
 package routers
 
 import (
+	"context"
 	"encoding/json"
 	"financial_data/internal/application/middleware"
 	"financial_data/internal/application/response"
 	"financial_data/internal/domain"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// calendarDefaultDays is the range of the dividend calendar when till is not given.
+const calendarDefaultDays = 60
+
 type DividendsHandler struct {
-	repo DividendsRepository
+	repo      DividendsRepository
+	analytics DividendAnalytics
+	importer  DividendImporter
 }
 
-func NewDividendsHandler(repo DividendsRepository) *DividendsHandler {
-	return &DividendsHandler{repo: repo}
+func NewDividendsHandler(repo DividendsRepository, analytics DividendAnalytics, importer DividendImporter) *DividendsHandler {
+	return &DividendsHandler{repo: repo, analytics: analytics, importer: importer}
 }
 
-func RegisterDividendsRoutes(r chi.Router, repo DividendsRepository, m *middleware.MiddlewareConfig) {
-	handler := NewDividendsHandler(repo)
+func RegisterDividendsRoutes(r chi.Router, repo DividendsRepository, analytics DividendAnalytics, importer DividendImporter, m *middleware.MiddlewareConfig) {
+	handler := NewDividendsHandler(repo, analytics, importer)
 
+	r.Get("/dividends/calendar", handler.HandleCalendar)
 	r.Get("/dividends/{ticker}", handler.HandleGetByTicker)
+	r.Get("/dividends/{ticker}/stats", handler.HandleStats)
 	r.Get("/dividends/{ticker}/{id}", handler.HandleGetByID)
 
 	r.Group(func(protected chi.Router) {
 		protected.Use(m.AuthMiddleware)
 
+		protected.Post("/dividends/import", handler.HandleImport)
 		protected.Post("/dividends/{ticker}", handler.HandleCreate)
 		protected.Put("/dividends/{ticker}/{id}", handler.HandleUpdate)
 		protected.Delete("/dividends/{ticker}/{id}", handler.HandleDelete)
@@ -88,6 +99,7 @@ func (h *DividendsHandler) HandleCreate(w http.ResponseWriter, r *http.Request)
 	}
 
 	dividend.Ticker = ticker
+	withExDate(&dividend)
 
 	if err := h.repo.Create(r.Context(), &dividend); err != nil {
 		response.RespondWithError(w, r, 500, "failed to create dividend", err)
@@ -119,6 +131,7 @@ func (h *DividendsHandler) HandleUpdate(w http.ResponseWriter, r *http.Request)
 	}
 
 	dividend.Ticker = ticker
+	withExDate(&dividend)
 
 	if err := h.repo.Update(r.Context(), id, &dividend); err != nil {
 		response.RespondWithError(w, r, 500, "failed to update dividend", err)
@@ -150,3 +163,86 @@ func (h *DividendsHandler) HandleDelete(w http.ResponseWriter, r *http.Request)
 
 	response.RespondWithSuccess(w, 204, nil, "Dividend successfully deleted")
 }
+
+// HandleCalendar lists dividends of all companies by the last day to buy,
+// from today for 60 days unless from and till are given.
+func (h *DividendsHandler) HandleCalendar(w http.ResponseWriter, r *http.Request) {
+	from := truncateToDate(time.Now())
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid from date format (expected YYYY-MM-DD)", err)
+			return
+		}
+		from = parsed
+	}
+
+	till := from.AddDate(0, 0, calendarDefaultDays)
+	if tillStr := r.URL.Query().Get("till"); tillStr != "" {
+		parsed, err := time.Parse("2006-01-02", tillStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid till date format (expected YYYY-MM-DD)", err)
+			return
+		}
+		till = parsed
+	}
+
+	if from.After(till) {
+		response.RespondWithError(w, r, 400, "from must not be after till", nil)
+		return
+	}
+
+	calendar, err := h.analytics.Calendar(r.Context(), from, till)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to load dividend calendar", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, calendar, "")
+}
+
+func (h *DividendsHandler) HandleStats(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	stats, err := h.analytics.Stats(r.Context(), ticker)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to calculate dividend stats", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, stats, "")
+}
+
+// HandleImport imports dividends from MOEX ISS: right away for one ticker when
+// it is given, otherwise for all companies in the background.
+func (h *DividendsHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	ticker := r.URL.Query().Get("ticker")
+	if ticker != "" {
+		result, err := h.importer.Import(r.Context(), ticker)
+		if err != nil {
+			response.RespondWithError(w, r, 500, "failed to import dividends", err)
+			return
+		}
+		response.RespondWithSuccess(w, 200, result, "Dividends imported")
+		return
+	}
+
+	go func() {
+		if err := h.importer.ImportAll(context.Background()); err != nil {
+			slog.Error("dividends import failed", "error", err)
+		}
+	}()
+
+	response.RespondWithSuccess(w, 202, nil, "Dividends import started")
+}
+
+// withExDate derives the ex-date from the record date when only the latter is given.
+func withExDate(dividend *domain.Dividends) {
+	if dividend.ExDividendDate.IsZero() && dividend.RecordDate != nil {
+		dividend.ExDividendDate = domain.ExDateFromRecordDate(*dividend.RecordDate)
+	}
+}
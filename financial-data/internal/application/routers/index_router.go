@@ -0,0 +1,185 @@
+package routers
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/application/middleware"
+	"financial_data/internal/application/response"
+	"financial_data/internal/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// performanceDefaultDays is the window of candles and returns when from is not given.
+const performanceDefaultDays = 365
+
+type IndexHandler struct {
+	indices IndexHistory
+}
+
+func NewIndexHandler(indices IndexHistory) *IndexHandler {
+	return &IndexHandler{indices: indices}
+}
+
+func RegisterIndexRoutes(r chi.Router, indices IndexHistory, m *middleware.MiddlewareConfig) {
+	handler := NewIndexHandler(indices)
+
+	r.Get("/indices", handler.HandleGetCatalog)
+	r.Get("/indices/{index}/candles", handler.HandleGetCandles)
+	r.Get("/indices/{index}/performance", handler.HandleGetPerformance)
+	r.Get("/performance/{ticker}", handler.HandleCompare)
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(m.AuthMiddleware)
+
+		protected.Post("/indices/sync", handler.HandleSync)
+	})
+}
+
+func (h *IndexHandler) HandleGetCatalog(w http.ResponseWriter, r *http.Request) {
+	response.RespondWithSuccess(w, 200, h.indices.Catalog(), "")
+}
+
+func (h *IndexHandler) HandleGetCandles(w http.ResponseWriter, r *http.Request) {
+	index := strings.ToUpper(chi.URLParam(r, "index"))
+
+	interval := domain.Period1D
+	if intervalStr := r.URL.Query().Get("interval"); intervalStr != "" {
+		parsed, err := strconv.Atoi(intervalStr)
+		if err != nil || !domain.Period(parsed).IsValid() {
+			response.RespondWithError(w, r, 400, "invalid interval in query params. Must be 60, 24 or 7", err)
+			return
+		}
+		interval = domain.Period(parsed)
+	}
+
+	from, till, ok := parseWindowParams(w, r)
+	if !ok {
+		return
+	}
+
+	candles, err := h.indices.GetCandles(r.Context(), index, interval, from, till)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "unknown index", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to load index candles", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, candles, "")
+}
+
+func (h *IndexHandler) HandleGetPerformance(w http.ResponseWriter, r *http.Request) {
+	index := strings.ToUpper(chi.URLParam(r, "index"))
+
+	from, till, ok := parseWindowParams(w, r)
+	if !ok {
+		return
+	}
+
+	performance, err := h.indices.Performance(r.Context(), index, from, till)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "unknown index or no candles in the window", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to calculate index performance", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, performance, "")
+}
+
+// HandleCompare compares the return of the ticker with its sector index and
+// IMOEX over the window.
+func (h *IndexHandler) HandleCompare(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	from, till, ok := parseWindowParams(w, r)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.indices.Compare(r.Context(), ticker, from, till)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "unknown company or no candles in the window", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to compare performance", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, comparison, "")
+}
+
+// HandleSync starts an index candle import in the background: for one index
+// when it is given, otherwise for the whole catalog.
+func (h *IndexHandler) HandleSync(w http.ResponseWriter, r *http.Request) {
+	index := strings.ToUpper(r.URL.Query().Get("index"))
+	full := r.URL.Query().Get("full") == "true"
+
+	if index != "" {
+		if _, ok := domain.LookupIndex(index); !ok {
+			response.RespondWithError(w, r, 404, "unknown index", nil)
+			return
+		}
+	}
+
+	go func() {
+		ctx := context.Background()
+		var err error
+		if index != "" {
+			err = h.indices.Sync(ctx, index, full)
+		} else {
+			err = h.indices.SyncAll(ctx, full)
+		}
+		if err != nil {
+			slog.Error("index sync failed", "index", index, "full", full, "error", err)
+		}
+	}()
+
+	response.RespondWithSuccess(w, 202, map[string]any{"index": index, "full": full}, "Index sync started")
+}
+
+// parseWindowParams reads the from and till query parameters, by default the
+// last year up to today, writing a 400 response when they are invalid.
+func parseWindowParams(w http.ResponseWriter, r *http.Request) (from, till time.Time, ok bool) {
+	till = truncateToDate(time.Now())
+	if tillStr := r.URL.Query().Get("till"); tillStr != "" {
+		parsed, err := time.Parse("2006-01-02", tillStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid till date format (expected YYYY-MM-DD)", err)
+			return time.Time{}, time.Time{}, false
+		}
+		till = parsed
+	}
+
+	from = till.AddDate(0, 0, -performanceDefaultDays)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid from date format (expected YYYY-MM-DD)", err)
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	if from.After(till) {
+		response.RespondWithError(w, r, 400, "from must not be after till", nil)
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, till, true
+}
@@ -3,26 +3,59 @@ package routers
 import (
 	"context"
 	"financial_data/internal/domain"
+	"io"
 	"time"
 )
 
 type DividendsRepository interface {
 	GetByTicker(ctx context.Context, ticker string) ([]domain.Dividends, error)
+	GetByExDateRange(ctx context.Context, from, till time.Time) ([]domain.Dividends, error)
 	GetByID(ctx context.Context, id int) (*domain.Dividends, error)
 	Create(ctx context.Context, dividend *domain.Dividends) error
 	Update(ctx context.Context, id int, dividend *domain.Dividends) error
+	Upsert(ctx context.Context, dividend *domain.Dividends) (bool, error)
 	Delete(ctx context.Context, id int) error
 }
 
+type DividendAnalytics interface {
+	Calendar(ctx context.Context, from, till time.Time) ([]domain.DividendCalendarEntry, error)
+	Stats(ctx context.Context, ticker string) (*domain.DividendStats, error)
+}
+
+type DividendImporter interface {
+	Import(ctx context.Context, ticker string) (*domain.DividendImportResult, error)
+	ImportAll(ctx context.Context) error
+}
+
 type MacroDataRepository interface {
 	GetCurrent(ctx context.Context) (*domain.CBRate, error)
 	GetByDate(ctx context.Context, date time.Time) (*domain.CBRate, error)
 	GetHistory(ctx context.Context, from, to time.Time) ([]domain.CBRate, error)
+	GetAsOf(ctx context.Context, date time.Time) (*domain.CBRate, error)
 	Create(ctx context.Context, rate *domain.CBRate) error
+	Upsert(ctx context.Context, rate *domain.CBRate) error
 	Update(ctx context.Context, date time.Time, rate float64) error
 	Delete(ctx context.Context, date time.Time) error
 }
 
+type MacroSeriesRepository interface {
+	GetRange(ctx context.Context, series domain.MacroSeries, from, till time.Time) ([]domain.MacroPoint, error)
+	GetAsOf(ctx context.Context, series domain.MacroSeries, date time.Time) (*domain.MacroPoint, error)
+	GetYieldCurve(ctx context.Context, date time.Time) ([]domain.MacroPoint, error)
+	GetLastDate(ctx context.Context, series domain.MacroSeries) (time.Time, error)
+	Upsert(ctx context.Context, points []domain.MacroPoint) error
+}
+
+type MacroHistory interface {
+	Catalog() []domain.MacroSeriesInfo
+	History(ctx context.Context, series domain.MacroSeries, from, till time.Time) ([]domain.MacroPoint, error)
+	Latest(ctx context.Context, series domain.MacroSeries) (*domain.MacroPoint, error)
+	YieldCurve(ctx context.Context, date time.Time) (*domain.YieldCurve, error)
+	Import(ctx context.Context, series domain.MacroSeries) (int, error)
+	ImportAll(ctx context.Context) error
+	BackfillYieldCurve(ctx context.Context, from, till time.Time) (int, error)
+}
+
 type NewsRepository interface {
 	GetByID(ctx context.Context, id int) (*domain.News, error)
 	GetByTicker(ctx context.Context, ticker string) ([]domain.News, error)
@@ -44,15 +77,72 @@ type RawDataRepository interface {
 	Delete(ctx context.Context, ticker string, year int, period domain.ReportPeriod) error
 }
 
+type RawDataVersionsRepository interface {
+	Append(ctx context.Context, rawData *domain.RawData, action domain.RawDataAction, actor string) (*domain.RawDataVersion, error)
+	GetVersions(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.RawDataVersion, error)
+	GetVersion(ctx context.Context, ticker string, year int, period domain.ReportPeriod, version int) (*domain.RawDataVersion, error)
+	LockReport(ctx context.Context, ticker string, year int, period domain.ReportPeriod) error
+}
+
+// RawDataHistory records versions of reports on every change.
+type RawDataHistory interface {
+	RawDataVersionsRepository
+	Change(ctx context.Context, ticker string, year int, period domain.ReportPeriod, action domain.RawDataAction, actor string,
+		change func(ctx context.Context) (*domain.RawData, error)) (*domain.RawDataVersion, error)
+}
+
+// Transactor runs fn in a transaction the repositories called with its
+// context take part in.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// JobsLease is a lock held by at most one replica at a time. Acquire takes it
+// or extends it when this replica already holds it.
+type JobsLease interface {
+	Acquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+type RawDataSourcesRepository interface {
+	Save(ctx context.Context, source *domain.RawDataSource) error
+	GetByID(ctx context.Context, id int) (*domain.RawDataSource, error)
+	GetByPeriod(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.RawDataSource, error)
+	GetByTicker(ctx context.Context, ticker string) ([]domain.RawDataSource, error)
+	SetCanonical(ctx context.Context, id int) error
+}
+
+type RawDataSources interface {
+	AddSource(ctx context.Context, source *domain.RawDataSource, makeCanonical bool, actor string) (*domain.ValidationReport, error)
+	SetCanonical(ctx context.Context, ticker string, id int, actor string) (*domain.ValidationReport, error)
+	GetSources(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.RawDataSource, error)
+	GetRestatements(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.Restatement, error)
+}
+
+type RawDataFileCodec interface {
+	Decode(format domain.RawDataFileFormat, r io.Reader) ([]domain.RawDataFileRow, error)
+	Encode(format domain.RawDataFileFormat, w io.Writer, reports []domain.RawData) error
+}
+
+type RawDataImporter interface {
+	Import(ctx context.Context, rows []domain.RawDataFileRow, dryRun bool, actor string) (*domain.RawDataImportResult, error)
+}
+
 type CompanyRepository interface {
 	GetByTicker(ctx context.Context, ticker string) (*domain.Company, error)
 	GetAll(ctx context.Context) ([]domain.Company, error)
 	GetBySector(ctx context.Context, sectorID int) ([]domain.Company, error)
 	Create(ctx context.Context, company *domain.Company) error
 	Update(ctx context.Context, ticker string, company *domain.Company) error
+	UpdateProfile(ctx context.Context, company *domain.Company) error
 	Delete(ctx context.Context, ticker string) error
 }
 
+type CompanyRefresher interface {
+	Refresh(ctx context.Context, ticker string) (*domain.CompanyRefreshResult, error)
+	RefreshAll(ctx context.Context) error
+}
+
 type SectorRepository interface {
 	GetByID(ctx context.Context, id int) (*domain.SectorModel, error)
 	GetAll(ctx context.Context) ([]domain.SectorModel, error)
@@ -65,22 +155,86 @@ type PriceRepository interface {
 	GetStockPrice(ticker string, daysBackwards int, interval domain.Period) ([]domain.Candle, error)
 }
 
+type CandlesRepository interface {
+	GetRange(ctx context.Context, ticker string, interval domain.Period, from, till time.Time) ([]domain.Candle, error)
+	GetLastBegin(ctx context.Context, ticker string, interval domain.Period) (time.Time, error)
+	Upsert(ctx context.Context, ticker string, interval domain.Period, candles []domain.Candle) error
+}
+
+type PriceHistory interface {
+	GetCandles(ctx context.Context, ticker string, interval domain.Period, from, till time.Time) ([]domain.Candle, error)
+	GetAdjustedCandles(ctx context.Context, ticker string, interval domain.Period, from, till time.Time) ([]domain.AdjustedCandle, error)
+	GetIndexCandles(ctx context.Context, index string, interval domain.Period, from, till time.Time) ([]domain.Candle, error)
+	Sync(ctx context.Context, ticker string, full bool) error
+	SyncAll(ctx context.Context, full bool) error
+	SyncIndex(ctx context.Context, index string, full bool) error
+	SyncIndices(ctx context.Context, full bool) error
+}
+
+type IndexHistory interface {
+	Catalog() []domain.IndexInfo
+	GetCandles(ctx context.Context, index string, interval domain.Period, from, till time.Time) ([]domain.Candle, error)
+	Performance(ctx context.Context, index string, from, till time.Time) (*domain.Performance, error)
+	Compare(ctx context.Context, ticker string, from, till time.Time) (*domain.PerformanceComparison, error)
+	Sync(ctx context.Context, index string, full bool) error
+	SyncAll(ctx context.Context, full bool) error
+}
+
+type BetaEstimator interface {
+	Estimate(ctx context.Context, ticker, benchmark string, frequency domain.BetaFrequency, windowMonths int) (*domain.Beta, error)
+}
+
+type ShareEventsRepository interface {
+	GetByTicker(ctx context.Context, ticker string) ([]domain.ShareEvent, error)
+	GetSharesAt(ctx context.Context, ticker string, date time.Time) (int64, error)
+	Create(ctx context.Context, event *domain.ShareEvent) error
+	Update(ctx context.Context, id int, event *domain.ShareEvent) error
+	Delete(ctx context.Context, ticker string, id int) error
+}
+
+type MarketCapHistory interface {
+	GetMarketCapAt(ctx context.Context, ticker string, date time.Time) (float64, error)
+}
+
 type EventPublisher interface {
 	PublishCompanyCreated(ctx context.Context, ticker, name, id string) error
 	PublishBusinessResearchTask(ctx context.Context, ticker, id string) error
 	PublishExpectRiskAndGrowthAnalysis(ctx context.Context, ticker, id string) error
+	PublishDividendAnnounced(ctx context.Context, dividend domain.Dividends) error
+	PublishKeyRateChanged(ctx context.Context, rate, previous domain.CBRate) error
+	PublishCompanyDelisted(ctx context.Context, company domain.Company) error
 }
 
 type RatiosRepository interface {
 	GetByTickerAndPeriod(ctx context.Context, ticker string, year int, period domain.ReportPeriod) (*domain.Ratios, error)
 	GetLatestByTicker(ctx context.Context, ticker string) (*domain.Ratios, error)
+	GetLatestTTM(ctx context.Context, ticker string) (*domain.Ratios, error)
 	GetHistoryByTicker(ctx context.Context, ticker string) ([]domain.Ratios, error)
 	GetBySector(ctx context.Context, sector domain.Sector) (*domain.Ratios, error)
+	GetLatestBySector(ctx context.Context, sector domain.Sector, ttm bool) ([]domain.Ratios, error)
+	Screen(ctx context.Context, req domain.ScreenerRequest) (*domain.ScreenerResult, error)
 	Create(ctx context.Context, sector domain.Sector, ratios *domain.Ratios) error
 	Update(ctx context.Context, ratios *domain.Ratios) error
 	Delete(ctx context.Context, ticker string, year int, period domain.ReportPeriod) error
 }
 
+type CurrentRatiosProvider interface {
+	GetCurrent(ctx context.Context, ticker string) (*domain.CurrentRatios, error)
+}
+
+type SectorBenchmarks interface {
+	GetSectorStats(ctx context.Context, sector domain.Sector, ttm bool) (*domain.SectorStats, error)
+	GetRank(ctx context.Context, ticker string, ttm bool) (*domain.CompanyRank, error)
+}
+
+type Screener interface {
+	Screen(ctx context.Context, req domain.ScreenerRequest) (*domain.ScreenerResult, error)
+}
+
+type RawDataValidator interface {
+	Validate(ctx context.Context, rawData *domain.RawData) (*domain.ValidationReport, error)
+}
+
 type RatiosCalculator interface {
 	CalculateAndSave(ctx context.Context, rawData *domain.RawData) error
 	RecalculateAll(ctx context.Context, ticker string) error
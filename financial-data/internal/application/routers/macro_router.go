@@ -1,34 +1,47 @@
 package routers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"financial_data/internal/application/middleware"
 	"financial_data/internal/application/response"
 	"financial_data/internal/domain"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// macroDefaultDays is the range of a series history when from is not given.
+const macroDefaultDays = 365
+
 type MacroHandler struct {
-	repo MacroDataRepository
+	repo   MacroDataRepository
+	series MacroHistory
 }
 
-func NewMacroHandler(repo MacroDataRepository) *MacroHandler {
-	return &MacroHandler{repo: repo}
+func NewMacroHandler(repo MacroDataRepository, series MacroHistory) *MacroHandler {
+	return &MacroHandler{repo: repo, series: series}
 }
 
-func RegisterMacroRoutes(r chi.Router, repo MacroDataRepository, m *middleware.MiddlewareConfig) {
-	handler := NewMacroHandler(repo)
+func RegisterMacroRoutes(r chi.Router, repo MacroDataRepository, series MacroHistory, m *middleware.MiddlewareConfig) {
+	handler := NewMacroHandler(repo, series)
 
 	r.Get("/macro/cb-rate/current", handler.HandleGetCurrent)
 	r.Get("/macro/cb-rate/history", handler.HandleGetHistory)
+	r.Get("/macro", handler.HandleGetCatalog)
+	r.Get("/macro/yield-curve", handler.HandleGetYieldCurve)
+	r.Get("/macro/{series}", handler.HandleGetSeries)
+	r.Get("/macro/{series}/latest", handler.HandleGetSeriesLatest)
 
 	r.Group(func(protected chi.Router) {
 		protected.Use(m.AuthMiddleware)
 
+		protected.Post("/macro/import", handler.HandleImport)
+		protected.Post("/macro/yield-curve/import", handler.HandleBackfillYieldCurve)
 		protected.Post("/macro/cb-rate", handler.HandleCreate)
 		protected.Put("/macro/cb-rate", handler.HandleUpdate)
 		protected.Delete("/macro/cb-rate", handler.HandleDelete)
@@ -171,3 +184,177 @@ func (h *MacroHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 
 	response.RespondWithSuccess(w, 204, nil, "CB rate successfully deleted")
 }
+
+func (h *MacroHandler) HandleGetCatalog(w http.ResponseWriter, r *http.Request) {
+	response.RespondWithSuccess(w, 200, h.series.Catalog(), "")
+}
+
+// HandleGetSeries returns the history of a series for the last year unless from
+// and to are given.
+func (h *MacroHandler) HandleGetSeries(w http.ResponseWriter, r *http.Request) {
+	series := domain.MacroSeries(chi.URLParam(r, "series"))
+
+	to := time.Now()
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid to date format (expected YYYY-MM-DD)", err)
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -macroDefaultDays)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid from date format (expected YYYY-MM-DD)", err)
+			return
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		response.RespondWithError(w, r, 400, "from must not be after to", nil)
+		return
+	}
+
+	points, err := h.series.History(r.Context(), series, from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "unknown macro series", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to load macro series", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, points, "")
+}
+
+func (h *MacroHandler) HandleGetSeriesLatest(w http.ResponseWriter, r *http.Request) {
+	series := domain.MacroSeries(chi.URLParam(r, "series"))
+
+	point, err := h.series.Latest(r.Context(), series)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "no values of the macro series", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to load macro series", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, point, "")
+}
+
+// HandleGetYieldCurve returns the zero-coupon OFZ curve as of date (today by
+// default). With tenor in years the yield of that term is interpolated as well.
+func (h *MacroHandler) HandleGetYieldCurve(w http.ResponseWriter, r *http.Request) {
+	date := time.Now()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid date format (expected YYYY-MM-DD)", err)
+			return
+		}
+		date = parsed
+	}
+
+	var tenor *float64
+	if tenorStr := r.URL.Query().Get("tenor"); tenorStr != "" {
+		parsed, err := strconv.ParseFloat(tenorStr, 64)
+		if err != nil || parsed <= 0 {
+			response.RespondWithError(w, r, 400, "tenor must be a positive number of years", err)
+			return
+		}
+		tenor = &parsed
+	}
+
+	curve, err := h.series.YieldCurve(r.Context(), date)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, "date must not be in the future", err)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "no yield curve for the date", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to load yield curve", err)
+		return
+	}
+
+	if tenor != nil {
+		if y, ok := curve.YieldAt(*tenor); ok {
+			curve.Tenor, curve.Yield = tenor, &y
+		}
+	}
+
+	response.RespondWithSuccess(w, 200, curve, "")
+}
+
+// HandleImport imports one series right away when it is given, otherwise every
+// series in the background.
+func (h *MacroHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	series := domain.MacroSeries(r.URL.Query().Get("series"))
+	if series != "" {
+		count, err := h.series.Import(r.Context(), series)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				response.RespondWithError(w, r, 404, "unknown macro series", err)
+				return
+			}
+			response.RespondWithError(w, r, 500, "failed to import macro series", err)
+			return
+		}
+		response.RespondWithSuccess(w, 200, map[string]any{"series": series, "imported": count}, "Macro series imported")
+		return
+	}
+
+	go func() {
+		if err := h.series.ImportAll(context.Background()); err != nil {
+			slog.Error("macro import failed", "error", err)
+		}
+	}()
+
+	response.RespondWithSuccess(w, 202, nil, "Macro import started")
+}
+
+// HandleBackfillYieldCurve loads yield curves of [from, to] (today by default)
+// in the background: ISS serves one trade date per request.
+func (h *MacroHandler) HandleBackfillYieldCurve(w http.ResponseWriter, r *http.Request) {
+	fromStr := r.URL.Query().Get("from")
+	if fromStr == "" {
+		response.RespondWithError(w, r, 400, "from query parameter is required (format: YYYY-MM-DD)", nil)
+		return
+	}
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid from date format (expected YYYY-MM-DD)", err)
+		return
+	}
+
+	to := time.Now()
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid to date format (expected YYYY-MM-DD)", err)
+			return
+		}
+		to = parsed
+	}
+
+	if from.After(to) {
+		response.RespondWithError(w, r, 400, "from must not be after to", nil)
+		return
+	}
+
+	go func() {
+		if _, err := h.series.BackfillYieldCurve(context.Background(), from, to); err != nil {
+			slog.Error("yield curve backfill failed", "from", from.Format("2006-01-02"), "to", to.Format("2006-01-02"), "error", err)
+		}
+	}()
+
+	response.RespondWithSuccess(w, 202, nil, "Yield curve backfill started")
+}
@@ -1,9 +1,12 @@
 package routers
 
 import (
+	"context"
+	"errors"
 	"financial_data/internal/application/middleware"
 	"financial_data/internal/application/response"
 	"financial_data/internal/domain"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,27 +16,41 @@ import (
 
 type PriceHandler struct {
 	priceProvider domain.MarketService
+	history       PriceHistory
+	marketCaps    MarketCapHistory
 }
 
-func NewPriceHandler(priceProvider domain.MarketService) *PriceHandler {
-	return &PriceHandler{priceProvider: priceProvider}
+func NewPriceHandler(priceProvider domain.MarketService, history PriceHistory, marketCaps MarketCapHistory) *PriceHandler {
+	return &PriceHandler{priceProvider: priceProvider, history: history, marketCaps: marketCaps}
 }
 
-func RegisterPriceRoutes(r chi.Router, priceProvider domain.MarketService, m *middleware.MiddlewareConfig) {
-	handler := NewPriceHandler(priceProvider)
+func RegisterPriceRoutes(r chi.Router, priceProvider domain.MarketService, history PriceHistory, marketCaps MarketCapHistory, m *middleware.MiddlewareConfig) {
+	handler := NewPriceHandler(priceProvider, history, marketCaps)
 	r.Get("/price", handler.HandleGetPriceByTicker)
 	r.Get("/price/latest", handler.HandleGetLatestPrice)
 	r.Get("/price/at", handler.HandleGetPriceAt)
 	r.Get("/market-cap", handler.HandleGetMarketCap)
+	r.Get("/market-cap/at", handler.HandleGetMarketCapAt)
 	r.Get("/stock-info", handler.HandleGetStockInfo)
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(m.AuthMiddleware)
+
+		protected.Post("/price/sync", handler.HandleSync)
+	})
 }
 
+// HandleGetPriceByTicker serves candles from the local store. The range is set
+// either by from/till (YYYY-MM-DD, till defaults to today) or by days back from today.
+// With adjusted=true the candles are split- and dividend-adjusted.
 func (h *PriceHandler) HandleGetPriceByTicker(w http.ResponseWriter, r *http.Request) {
 	ticker := r.URL.Query().Get("ticker")
 	days := r.URL.Query().Get("days")
+	fromStr := r.URL.Query().Get("from")
+	tillStr := r.URL.Query().Get("till")
 	interval := r.URL.Query().Get("interval")
-	if ticker == "" || days == "" || interval == "" {
-		response.RespondWithError(w, r, http.StatusBadRequest, "ticker, days and interval are required in query params", nil)
+	if ticker == "" || interval == "" || (days == "" && fromStr == "") {
+		response.RespondWithError(w, r, http.StatusBadRequest, "ticker, interval and either days or from are required in query params", nil)
 		return
 	}
 
@@ -47,12 +64,57 @@ func (h *PriceHandler) HandleGetPriceByTicker(w http.ResponseWriter, r *http.Req
 		response.RespondWithError(w, r, http.StatusBadRequest, "invalid interval in query params", err)
 		return
 	}
-	daysInt, err := strconv.Atoi(days)
-	if err != nil {
-		response.RespondWithError(w, r, http.StatusBadRequest, "invalid days in query params", err)
+
+	till := time.Now()
+	if tillStr != "" {
+		till, err = time.Parse("2006-01-02", tillStr)
+		if err != nil {
+			response.RespondWithError(w, r, http.StatusBadRequest, "invalid till date format (expected YYYY-MM-DD)", err)
+			return
+		}
+	}
+	till = truncateToDate(till)
+
+	var from time.Time
+	if fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			response.RespondWithError(w, r, http.StatusBadRequest, "invalid from date format (expected YYYY-MM-DD)", err)
+			return
+		}
+	} else {
+		daysInt, err := strconv.Atoi(days)
+		if err != nil || daysInt < 0 {
+			response.RespondWithError(w, r, http.StatusBadRequest, "invalid days in query params", err)
+			return
+		}
+		from = till.AddDate(0, 0, -daysInt)
+	}
+
+	if from.After(till) {
+		response.RespondWithError(w, r, http.StatusBadRequest, "from must not be after till", nil)
+		return
+	}
+
+	if r.URL.Query().Get("adjusted") == "true" {
+		adjusted, err := h.history.GetAdjustedCandles(r.Context(), ticker, domain.Period(intervalInt), from, till)
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, http.StatusNotFound, "company not found", err)
+			return
+		}
+		if err != nil {
+			response.RespondWithError(w, r, http.StatusInternalServerError, "failed to get adjusted price", err)
+			return
+		}
+		response.RespondWithSuccess(w, http.StatusOK, adjusted, "Success")
+		return
+	}
+
+	price, err := h.history.GetCandles(r.Context(), ticker, domain.Period(intervalInt), from, till)
+	if errors.Is(err, domain.ErrNotFound) {
+		response.RespondWithError(w, r, http.StatusNotFound, "company not found", err)
 		return
 	}
-	price, err := h.priceProvider.GetStockPrice(ticker, daysInt, domain.Period(intervalInt))
 	if err != nil {
 		response.RespondWithError(w, r, http.StatusInternalServerError, "failed to get price", err)
 		return
@@ -61,6 +123,28 @@ func (h *PriceHandler) HandleGetPriceByTicker(w http.ResponseWriter, r *http.Req
 	response.RespondWithSuccess(w, http.StatusOK, price, "Success")
 }
 
+// HandleSync starts a candle import in the background: for one ticker when it is
+// given, otherwise for all companies. full=true re-imports the whole history.
+func (h *PriceHandler) HandleSync(w http.ResponseWriter, r *http.Request) {
+	ticker := r.URL.Query().Get("ticker")
+	full := r.URL.Query().Get("full") == "true"
+
+	go func() {
+		ctx := context.Background()
+		var err error
+		if ticker != "" {
+			err = h.history.Sync(ctx, ticker, full)
+		} else {
+			err = h.history.SyncAll(ctx, full)
+		}
+		if err != nil {
+			slog.Error("price sync failed", "ticker", ticker, "full", full, "error", err)
+		}
+	}()
+
+	response.RespondWithSuccess(w, http.StatusAccepted, map[string]any{"ticker": ticker, "full": full}, "Price sync started")
+}
+
 func (h *PriceHandler) HandleGetLatestPrice(w http.ResponseWriter, r *http.Request) {
 	ticker := r.URL.Query().Get("ticker")
 	if ticker == "" {
@@ -107,7 +191,7 @@ func (h *PriceHandler) HandleGetMarketCap(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	marketCap, err := h.priceProvider.GetMarketCap(ticker)
+	marketCap, err := h.priceProvider.GetMarketCap(r.Context(), ticker)
 	if err != nil {
 		response.RespondWithError(w, r, http.StatusInternalServerError, "failed to get market cap", err)
 		return
@@ -116,6 +200,29 @@ func (h *PriceHandler) HandleGetMarketCap(w http.ResponseWriter, r *http.Request
 	response.RespondWithSuccess(w, http.StatusOK, marketCap, "Successfully got market cap")
 }
 
+func (h *PriceHandler) HandleGetMarketCapAt(w http.ResponseWriter, r *http.Request) {
+	ticker := r.URL.Query().Get("ticker")
+	dateStr := r.URL.Query().Get("date")
+	if ticker == "" || dateStr == "" {
+		response.RespondWithError(w, r, http.StatusBadRequest, "ticker and date are required", nil)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		response.RespondWithError(w, r, http.StatusBadRequest, "date must be in YYYY-MM-DD format", err)
+		return
+	}
+
+	marketCap, err := h.marketCaps.GetMarketCapAt(r.Context(), ticker, date)
+	if err != nil {
+		response.RespondWithError(w, r, http.StatusInternalServerError, "failed to get market cap at date", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, marketCap, "Successfully got market cap")
+}
+
 func (h *PriceHandler) HandleGetStockInfo(w http.ResponseWriter, r *http.Request) {
 	ticker := r.URL.Query().Get("ticker")
 	if ticker == "" {
@@ -140,3 +247,7 @@ func isValidInterval(i string) bool {
 		return false
 	}
 }
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -6,6 +6,7 @@ import (
 	"financial_data/internal/application/middleware"
 	"financial_data/internal/application/response"
 	"financial_data/internal/domain"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -15,18 +16,24 @@ import (
 type RatiosHandler struct {
 	repo           RatiosRepository
 	ratiosService  RatiosCalculator
+	current        CurrentRatiosProvider
+	benchmarks     SectorBenchmarks
 }
 
-func NewRatiosHandler(repo RatiosRepository, ratiosService RatiosCalculator) *RatiosHandler {
-	return &RatiosHandler{repo: repo, ratiosService: ratiosService}
+func NewRatiosHandler(repo RatiosRepository, ratiosService RatiosCalculator, current CurrentRatiosProvider, benchmarks SectorBenchmarks) *RatiosHandler {
+	return &RatiosHandler{repo: repo, ratiosService: ratiosService, current: current, benchmarks: benchmarks}
 }
 
-func RegisterRatiosRoutes(r chi.Router, repo RatiosRepository, ratiosService RatiosCalculator, m *middleware.MiddlewareConfig) {
-	handler := NewRatiosHandler(repo, ratiosService)
+func RegisterRatiosRoutes(r chi.Router, repo RatiosRepository, ratiosService RatiosCalculator, current CurrentRatiosProvider, benchmarks SectorBenchmarks, m *middleware.MiddlewareConfig) {
+	handler := NewRatiosHandler(repo, ratiosService, current, benchmarks)
 
 	r.Get("/ratios/sector/{sector_id}", handler.HandleGetBySector)
+	r.Get("/ratios/sector/{sector_id}/stats", handler.HandleGetSectorStats)
 	r.Get("/ratios/{ticker}", handler.HandleGetByPeriod)
 	r.Get("/ratios/{ticker}/latest", handler.HandleGetLatest)
+	r.Get("/ratios/{ticker}/ttm", handler.HandleGetLatestTTM)
+	r.Get("/ratios/{ticker}/current", handler.HandleGetCurrent)
+	r.Get("/ratios/{ticker}/rank", handler.HandleGetRank)
 	r.Get("/ratios/{ticker}/history", handler.HandleGetHistory)
 
 	r.Group(func(protected chi.Router) {
@@ -61,8 +68,8 @@ func (h *RatiosHandler) HandleGetByPeriod(w http.ResponseWriter, r *http.Request
 	}
 
 	period := domain.ReportPeriod(periodStr)
-	if !period.IsValid() {
-		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
+	if !period.IsValidForRatios() {
+		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR, TTM)", nil)
 		return
 	}
 
@@ -99,6 +106,50 @@ func (h *RatiosHandler) HandleGetLatest(w http.ResponseWriter, r *http.Request)
 	response.RespondWithSuccess(w, 200, ratios, "")
 }
 
+func (h *RatiosHandler) HandleGetLatestTTM(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	ratios, err := h.repo.GetLatestTTM(r.Context(), ticker)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "TTM ratios not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to load TTM ratios", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, ratios, "")
+}
+
+func (h *RatiosHandler) HandleGetCurrent(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	ratios, err := h.current.GetCurrent(r.Context(), ticker)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "no confirmed reports for this ticker", err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 422, "latest report has no valid reportUnits", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to calculate current ratios", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, ratios, "")
+}
+
 func (h *RatiosHandler) HandleGetHistory(w http.ResponseWriter, r *http.Request) {
 	ticker := chi.URLParam(r, "ticker")
 	if ticker == "" {
@@ -147,6 +198,74 @@ func (h *RatiosHandler) HandleGetBySector(w http.ResponseWriter, r *http.Request
 	response.RespondWithSuccess(w, 200, ratios, "")
 }
 
+func (h *RatiosHandler) HandleGetSectorStats(w http.ResponseWriter, r *http.Request) {
+	parsed, err := strconv.Atoi(chi.URLParam(r, "sector_id"))
+	if err != nil {
+		response.RespondWithError(w, r, 400, "sector_id must be int", err)
+		return
+	}
+
+	sector := domain.Sector(parsed)
+	if !sector.IsValid() {
+		response.RespondWithError(w, r, 400, "Sector is not valid (allowed values from 1 to 19)", nil)
+		return
+	}
+
+	ttm, err := parseTTMParam(r)
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid period (allowed: TTM, YEAR)", err)
+		return
+	}
+
+	stats, err := h.benchmarks.GetSectorStats(r.Context(), sector, ttm)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to calculate sector statistics", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, stats, "")
+}
+
+func (h *RatiosHandler) HandleGetRank(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	ttm, err := parseTTMParam(r)
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid period (allowed: TTM, YEAR)", err)
+		return
+	}
+
+	rank, err := h.benchmarks.GetRank(r.Context(), ticker, ttm)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "ratios not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to rank company in sector", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, rank, "")
+}
+
+// parseTTMParam reads the optional period query parameter: TTM (default) or
+// YEAR. Latest reports are not compared across companies as they mix quarters
+// with years.
+func parseTTMParam(r *http.Request) (bool, error) {
+	switch period := r.URL.Query().Get("period"); period {
+	case "", string(domain.TTM):
+		return true, nil
+	case string(domain.YEAR):
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown period %q", period)
+	}
+}
+
 func (h *RatiosHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	ticker := chi.URLParam(r, "ticker")
 	if ticker == "" {
@@ -225,8 +344,8 @@ func (h *RatiosHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	period := domain.ReportPeriod(periodStr)
-	if !period.IsValid() {
-		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
+	if !period.IsValidForRatios() {
+		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR, TTM)", nil)
 		return
 	}
 
@@ -0,0 +1,181 @@
+package routers
+
+import (
+	"bytes"
+	"errors"
+	"financial_data/internal/application/middleware"
+	"financial_data/internal/application/response"
+	"financial_data/internal/domain"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxImportFileSize = 20 << 20
+	maxImportRows     = 10000
+)
+
+type RawDataFilesHandler struct {
+	repo     RawDataRepository
+	codec    RawDataFileCodec
+	importer RawDataImporter
+}
+
+func NewRawDataFilesHandler(repo RawDataRepository, codec RawDataFileCodec, importer RawDataImporter) *RawDataFilesHandler {
+	return &RawDataFilesHandler{repo: repo, codec: codec, importer: importer}
+}
+
+func RegisterRawDataFileRoutes(r chi.Router, repo RawDataRepository, codec RawDataFileCodec, importer RawDataImporter, m *middleware.MiddlewareConfig) {
+	handler := NewRawDataFilesHandler(repo, codec, importer)
+
+	r.Get("/raw-data/{ticker}/export", handler.HandleExport)
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(m.AuthMiddleware)
+
+		protected.Post("/raw-data/import", handler.HandleImport)
+	})
+}
+
+// HandleImport loads many reports from a CSV, XLSX or JSON file, sent either as
+// the "file" field of a multipart form or as the request body. The format comes
+// from the format parameter, the file extension or the content type.
+func (h *RawDataFilesHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid dryRun parameter", err)
+			return
+		}
+		dryRun = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+
+	body, filename, err := readImportFile(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.RespondWithError(w, r, 413, fmt.Sprintf("file is larger than %d MB", maxImportFileSize>>20), err)
+			return
+		}
+		response.RespondWithError(w, r, 400, "failed to read file", err)
+		return
+	}
+
+	format := detectFileFormat(r.URL.Query().Get("format"), filename, r.Header.Get("Content-Type"))
+	if !format.IsValid() {
+		response.RespondWithError(w, r, 400, "unknown file format (allowed: csv, xlsx, json)", nil)
+		return
+	}
+
+	rows, err := h.codec.Decode(format, bytes.NewReader(body))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, err.Error(), err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to read file", err)
+		return
+	}
+	if len(rows) == 0 {
+		response.RespondWithError(w, r, 400, "file has no rows", nil)
+		return
+	}
+	if len(rows) > maxImportRows {
+		response.RespondWithError(w, r, 400, fmt.Sprintf("file has %d rows, at most %d are allowed", len(rows), maxImportRows), nil)
+		return
+	}
+
+	result, err := h.importer.Import(r.Context(), rows, dryRun, middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to import raw data", err)
+		return
+	}
+
+	message := "Raw data imported"
+	if dryRun {
+		message = "Dry run: nothing was saved"
+	}
+	response.RespondWithSuccess(w, 200, result, message)
+}
+
+// HandleExport returns the confirmed history of a ticker in the format of imported files.
+func (h *RawDataFilesHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	format := domain.FormatCSV
+	if v := r.URL.Query().Get("format"); v != "" {
+		format = domain.RawDataFileFormat(strings.ToLower(v))
+	}
+	if !format.IsValid() {
+		response.RespondWithError(w, r, 400, "invalid format (allowed: csv, xlsx, json)", nil)
+		return
+	}
+
+	history, err := h.repo.GetHistoryByTicker(r.Context(), ticker)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to get metrics history", err)
+		return
+	}
+	if history == nil {
+		history = []domain.RawData{}
+	}
+
+	// encode first so that a failure can still be reported as an error response
+	var buf bytes.Buffer
+	if err := h.codec.Encode(format, &buf, history); err != nil {
+		response.RespondWithError(w, r, 500, "failed to export metrics", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s_raw_data.%s"`, ticker, format))
+	w.WriteHeader(200)
+	w.Write(buf.Bytes())
+}
+
+func readImportFile(r *http.Request) (body []byte, filename string, err error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		body, err = io.ReadAll(r.Body)
+		return body, "", err
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	body, err = io.ReadAll(file)
+	return body, header.Filename, err
+}
+
+func detectFileFormat(param, filename, contentType string) domain.RawDataFileFormat {
+	if param != "" {
+		return domain.RawDataFileFormat(strings.ToLower(param))
+	}
+	if ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), "."); ext != "" {
+		return domain.RawDataFileFormat(ext)
+	}
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return domain.FormatCSV
+	case strings.Contains(contentType, "spreadsheetml"):
+		return domain.FormatXLSX
+	case strings.Contains(contentType, "json"):
+		return domain.FormatJSON
+	}
+	return ""
+}
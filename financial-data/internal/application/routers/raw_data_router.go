@@ -7,9 +7,11 @@ import (
 	"financial_data/internal/application/middleware"
 	"financial_data/internal/application/response"
 	"financial_data/internal/domain"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -17,25 +19,31 @@ import (
 type RawDataHandler struct {
 	repo          RawDataRepository
 	ratiosService RatiosCalculator
+	validator     RawDataValidator
+	versions      RawDataHistory
 }
 
-func NewRawDataHandler(repo RawDataRepository, ratiosService RatiosCalculator) *RawDataHandler {
-	return &RawDataHandler{repo: repo, ratiosService: ratiosService}
+func NewRawDataHandler(repo RawDataRepository, ratiosService RatiosCalculator, validator RawDataValidator, versions RawDataHistory) *RawDataHandler {
+	return &RawDataHandler{repo: repo, ratiosService: ratiosService, validator: validator, versions: versions}
 }
 
-func RegisterRawDataRoutes(r chi.Router, repo RawDataRepository, ratiosService RatiosCalculator, m *middleware.MiddlewareConfig) {
-	handler := NewRawDataHandler(repo, ratiosService)
+func RegisterRawDataRoutes(r chi.Router, repo RawDataRepository, ratiosService RatiosCalculator, validator RawDataValidator, versions RawDataHistory, m *middleware.MiddlewareConfig) {
+	handler := NewRawDataHandler(repo, ratiosService, validator, versions)
 
 	r.Get("/raw-data/{ticker}", handler.HandleGetByPeriod)
 	r.Get("/raw-data/{ticker}/latest", handler.HandleGetLatest)
 	r.Get("/raw-data/{ticker}/history", handler.HandleGetHistory)
 	r.Get("/raw-data/{ticker}/drafts", handler.HandleGetDrafts)
 	r.Get("/raw-data/{ticker}/draft", handler.HandleGetDraft)
+	r.Get("/raw-data/{ticker}/validate", handler.HandleValidate)
+	r.Get("/raw-data/{ticker}/versions", handler.HandleGetVersions)
+	r.Get("/raw-data/{ticker}/versions/diff", handler.HandleDiffVersions)
 
 	r.Group(func(protected chi.Router) {
 		protected.Use(m.AuthMiddleware)
 
 		protected.Put("/raw-data/{ticker}/confirm", handler.HandleConfirmDraft)
+		protected.Post("/raw-data/{ticker}/versions/{version}/rollback", handler.HandleRollback)
 		protected.Post("/raw-data/{ticker}", handler.HandleCreate)
 		protected.Put("/raw-data/{ticker}", handler.HandleUpdate)
 		protected.Delete("/raw-data/{ticker}", handler.HandleDelete)
@@ -43,29 +51,8 @@ func RegisterRawDataRoutes(r chi.Router, repo RawDataRepository, ratiosService R
 }
 
 func (h *RawDataHandler) HandleGetByPeriod(w http.ResponseWriter, r *http.Request) {
-	ticker := chi.URLParam(r, "ticker")
-	if ticker == "" {
-		response.RespondWithError(w, r, 400, "ticker is required", nil)
-		return
-	}
-
-	yearStr := r.URL.Query().Get("year")
-	periodStr := r.URL.Query().Get("period")
-
-	if yearStr == "" || periodStr == "" {
-		response.RespondWithError(w, r, 400, "year and period query parameters are required", nil)
-		return
-	}
-
-	year, err := strconv.Atoi(yearStr)
-	if err != nil {
-		response.RespondWithError(w, r, 400, "invalid year parameter", err)
-		return
-	}
-
-	period := domain.ReportPeriod(periodStr)
-	if !period.IsValid() {
-		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
 		return
 	}
 
@@ -135,29 +122,8 @@ func (h *RawDataHandler) HandleGetDrafts(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *RawDataHandler) HandleGetDraft(w http.ResponseWriter, r *http.Request) {
-	ticker := chi.URLParam(r, "ticker")
-	if ticker == "" {
-		response.RespondWithError(w, r, 400, "ticker is required", nil)
-		return
-	}
-
-	yearStr := r.URL.Query().Get("year")
-	periodStr := r.URL.Query().Get("period")
-
-	if yearStr == "" || periodStr == "" {
-		response.RespondWithError(w, r, 400, "year and period query parameters are required", nil)
-		return
-	}
-
-	year, err := strconv.Atoi(yearStr)
-	if err != nil {
-		response.RespondWithError(w, r, 400, "invalid year parameter", err)
-		return
-	}
-
-	period := domain.ReportPeriod(periodStr)
-	if !period.IsValid() {
-		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
 		return
 	}
 
@@ -177,33 +143,40 @@ func (h *RawDataHandler) HandleGetDraft(w http.ResponseWriter, r *http.Request)
 
 func (h *RawDataHandler) HandleConfirmDraft(w http.ResponseWriter, r *http.Request) {
 
-	ticker := chi.URLParam(r, "ticker")
-	if ticker == "" {
-		response.RespondWithError(w, r, 400, "ticker is required", nil)
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
 		return
 	}
 
-	yearStr := r.URL.Query().Get("year")
-	periodStr := r.URL.Query().Get("period")
-
-	if yearStr == "" || periodStr == "" {
-		response.RespondWithError(w, r, 400, "year and period query parameters are required", nil)
+	draft, err := h.repo.GetDraftByTickerAndPeriod(r.Context(), ticker, year, period)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to load draft", err)
+		return
+	}
+	if draft == nil {
+		response.RespondWithError(w, r, 404, "draft not found", nil)
 		return
 	}
 
-	year, err := strconv.Atoi(yearStr)
+	validation, err := h.validator.Validate(r.Context(), draft)
 	if err != nil {
-		response.RespondWithError(w, r, 400, "invalid year parameter", err)
+		response.RespondWithError(w, r, 500, "failed to validate draft", err)
 		return
 	}
-
-	period := domain.ReportPeriod(periodStr)
-	if !period.IsValid() {
-		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
+	if validation.HasErrors() {
+		response.RespondWithErrorDetails(w, r, 422, "draft has validation errors, fix them before confirming", validation)
 		return
 	}
 
-	if err := h.repo.ConfirmDraft(r.Context(), ticker, year, period); err != nil {
+	_, err = h.versions.Change(r.Context(), ticker, year, period, domain.RawDataActionConfirm, middleware.ActorFromContext(r.Context()),
+		func(ctx context.Context) (*domain.RawData, error) {
+			if err := h.repo.ConfirmDraft(ctx, ticker, year, period); err != nil {
+				return nil, err
+			}
+			draft.Status = domain.StatusConfirmed
+			return draft, nil
+		})
+	if err != nil {
 		response.RespondWithError(w, r, 500, "failed to confirm draft", err)
 		return
 	}
@@ -224,7 +197,7 @@ func (h *RawDataHandler) HandleConfirmDraft(w http.ResponseWriter, r *http.Reque
 		}
 	}()
 
-	response.RespondWithSuccess(w, 200, map[string]string{"status": "confirmed"}, "Draft confirmed successfully")
+	response.RespondWithSuccess(w, 200, map[string]any{"status": "confirmed", "validation": validation}, "Draft confirmed successfully")
 }
 
 func (h *RawDataHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
@@ -248,7 +221,19 @@ func (h *RawDataHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.repo.Create(r.Context(), &rawData); err != nil {
+	validation, ok := h.validate(w, r, &rawData)
+	if !ok {
+		return
+	}
+
+	_, err := h.versions.Change(r.Context(), ticker, rawData.Year, rawData.Period, domain.RawDataActionCreate, middleware.ActorFromContext(r.Context()),
+		func(ctx context.Context) (*domain.RawData, error) {
+			if err := h.repo.Create(ctx, &rawData); err != nil {
+				return nil, err
+			}
+			return &rawData, nil
+		})
+	if err != nil {
 		response.RespondWithError(w, r, 500, "failed to create metrics", err)
 		return
 	}
@@ -266,33 +251,16 @@ func (h *RawDataHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
-	response.RespondWithSuccess(w, 201, map[string]string{"status": "created"}, "Metrics successfully created")
+	message := "Metrics successfully created"
+	if validation.HasErrors() {
+		message = "Metrics saved as draft: validation found errors"
+	}
+	response.RespondWithSuccess(w, 201, map[string]any{"status": "created", "reportStatus": rawData.Status, "validation": validation}, message)
 }
 
 func (h *RawDataHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
-	ticker := chi.URLParam(r, "ticker")
-	if ticker == "" {
-		response.RespondWithError(w, r, 400, "ticker is required", nil)
-		return
-	}
-
-	yearStr := r.URL.Query().Get("year")
-	periodStr := r.URL.Query().Get("period")
-
-	if yearStr == "" || periodStr == "" {
-		response.RespondWithError(w, r, 400, "year and period query parameters are required", nil)
-		return
-	}
-
-	year, err := strconv.Atoi(yearStr)
-	if err != nil {
-		response.RespondWithError(w, r, 400, "invalid year parameter", err)
-		return
-	}
-
-	period := domain.ReportPeriod(periodStr)
-	if !period.IsValid() {
-		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
 		return
 	}
 
@@ -306,7 +274,19 @@ func (h *RawDataHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	rawData.Year = year
 	rawData.Period = period
 
-	if err := h.repo.Update(r.Context(), &rawData); err != nil {
+	validation, ok := h.validate(w, r, &rawData)
+	if !ok {
+		return
+	}
+
+	_, err := h.versions.Change(r.Context(), ticker, year, period, domain.RawDataActionUpdate, middleware.ActorFromContext(r.Context()),
+		func(ctx context.Context) (*domain.RawData, error) {
+			if err := h.repo.Update(ctx, &rawData); err != nil {
+				return nil, err
+			}
+			return &rawData, nil
+		})
+	if err != nil {
 		response.RespondWithError(w, r, 500, "failed to update metrics", err)
 		return
 	}
@@ -330,40 +310,292 @@ func (h *RawDataHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	response.RespondWithSuccess(w, 200, nil, "Metrics successfully updated")
+	message := "Metrics successfully updated"
+	if validation.HasErrors() {
+		message = "Metrics saved as draft: validation found errors"
+	}
+	response.RespondWithSuccess(w, 200, map[string]any{"reportStatus": rawData.Status, "validation": validation}, message)
 }
 
 func (h *RawDataHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
-	ticker := chi.URLParam(r, "ticker")
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := h.versions.Change(r.Context(), ticker, year, period, domain.RawDataActionDelete, middleware.ActorFromContext(r.Context()),
+		func(ctx context.Context) (*domain.RawData, error) {
+			deleted, err := h.loadStored(ctx, ticker, year, period)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return nil, fmt.Errorf("failed to load metrics: %w", err)
+			}
+			if err := h.repo.Delete(ctx, ticker, year, period); err != nil {
+				return nil, err
+			}
+			return deleted, nil
+		})
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to delete metrics", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 204, nil, "Metrics successfully deleted")
+}
+
+func (h *RawDataHandler) HandleValidate(w http.ResponseWriter, r *http.Request) {
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	rawData, err := h.loadStored(r.Context(), ticker, year, period)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "metrics not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to load metrics", err)
+		return
+	}
+
+	validation, err := h.validator.Validate(r.Context(), rawData)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to validate metrics", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, validation, "")
+}
+
+// validate runs validation before saving. A report with errors cannot be stored
+// as confirmed, so it is downgraded to a draft for manual review.
+func (h *RawDataHandler) validate(w http.ResponseWriter, r *http.Request, rawData *domain.RawData) (*domain.ValidationReport, bool) {
+	validation, err := h.validator.Validate(r.Context(), rawData)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to validate metrics", err)
+		return nil, false
+	}
+
+	if validation.ApplyStatus(rawData) {
+		slog.Warn("raw data failed validation, saving as draft", "ticker", rawData.Ticker, "year", rawData.Year, "period", rawData.Period)
+	}
+
+	return validation, true
+}
+
+// loadStored returns the stored report: the pending draft if there is one,
+// since it is what would be confirmed next, otherwise the confirmed one.
+func (h *RawDataHandler) loadStored(ctx context.Context, ticker string, year int, period domain.ReportPeriod) (*domain.RawData, error) {
+	draft, err := h.repo.GetDraftByTickerAndPeriod(ctx, ticker, year, period)
+	if err != nil {
+		return nil, err
+	}
+	if draft != nil {
+		return draft, nil
+	}
+	return h.repo.GetByTickerAndPeriod(ctx, ticker, year, period)
+}
+
+type rawDataVersionSummary struct {
+	Version   int                  `json:"version"`
+	Action    domain.RawDataAction `json:"action"`
+	Actor     string               `json:"actor"`
+	Status    domain.MetricsStatus `json:"status"`
+	CreatedAt time.Time            `json:"createdAt"`
+	Changes   []domain.FieldChange `json:"changes"`
+}
+
+// HandleGetVersions returns the change history of a report, oldest first, with
+// the fields each version changed compared to the previous one.
+func (h *RawDataHandler) HandleGetVersions(w http.ResponseWriter, r *http.Request) {
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	versions, err := h.versions.GetVersions(r.Context(), ticker, year, period)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to get versions", err)
+		return
+	}
+
+	summaries := make([]rawDataVersionSummary, 0, len(versions))
+	for i := range versions {
+		var previous *domain.RawData
+		if i > 0 {
+			previous = &versions[i-1].Data
+		}
+		summaries = append(summaries, rawDataVersionSummary{
+			Version:   versions[i].Version,
+			Action:    versions[i].Action,
+			Actor:     versions[i].Actor,
+			Status:    versions[i].Data.Status,
+			CreatedAt: versions[i].CreatedAt,
+			Changes:   domain.DiffRawData(previous, &versions[i].Data),
+		})
+	}
+
+	response.RespondWithSuccess(w, 200, summaries, "")
+}
+
+// HandleDiffVersions compares two versions of a report; to defaults to the latest one.
+func (h *RawDataHandler) HandleDiffVersions(w http.ResponseWriter, r *http.Request) {
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid from parameter", err)
+		return
+	}
+
+	versions, err := h.versions.GetVersions(r.Context(), ticker, year, period)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to get versions", err)
+		return
+	}
+	if len(versions) == 0 {
+		response.RespondWithError(w, r, 404, "no versions found", nil)
+		return
+	}
+
+	to := versions[len(versions)-1].Version
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = strconv.Atoi(toStr)
+		if err != nil {
+			response.RespondWithError(w, r, 400, "invalid to parameter", err)
+			return
+		}
+	}
+
+	fromVersion, toVersion := findVersion(versions, from), findVersion(versions, to)
+	if fromVersion == nil || toVersion == nil {
+		response.RespondWithError(w, r, 404, "version not found", nil)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, map[string]any{
+		"from":    from,
+		"to":      to,
+		"changes": domain.DiffRawData(&fromVersion.Data, &toVersion.Data),
+	}, "")
+}
+
+// HandleRollback restores a report to the state of a previous version. The
+// restored data goes through validation like any other write, and the rollback
+// itself is recorded as a new version.
+func (h *RawDataHandler) HandleRollback(w http.ResponseWriter, r *http.Request) {
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid version", err)
+		return
+	}
+
+	target, err := h.versions.GetVersion(r.Context(), ticker, year, period, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "version not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to get version", err)
+		return
+	}
+	if target.Action == domain.RawDataActionDelete {
+		response.RespondWithError(w, r, 400, "cannot roll back to a deleted state, use DELETE instead", nil)
+		return
+	}
+
+	rawData := target.Data
+	validation, ok := h.validate(w, r, &rawData)
+	if !ok {
+		return
+	}
+
+	rolledBack, err := h.versions.Change(r.Context(), ticker, year, period, domain.RawDataActionRollback, middleware.ActorFromContext(r.Context()),
+		func(ctx context.Context) (*domain.RawData, error) {
+			err := h.repo.Update(ctx, &rawData)
+			if errors.Is(err, domain.ErrNotFound) {
+				// the report was deleted after the version was taken
+				err = h.repo.Create(ctx, &rawData)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &rawData, nil
+		})
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to roll back metrics", err)
+		return
+	}
+
+	if rawData.Status == domain.StatusConfirmed {
+		go func() {
+			defer func() {
+				if rv := recover(); rv != nil {
+					slog.Error("panic in ratios calculation after rollback", "ticker", ticker, "recover", rv)
+				}
+			}()
+			if err := h.ratiosService.CalculateAndSave(context.Background(), &rawData); err != nil {
+				slog.Error("failed to calculate ratios after rollback", "ticker", ticker, "error", err)
+			}
+		}()
+	}
+
+	result := map[string]any{"rolledBackTo": version, "reportStatus": rawData.Status, "validation": validation}
+	if rolledBack != nil {
+		result["version"] = rolledBack.Version
+	}
+
+	message := "Metrics rolled back"
+	if validation.HasErrors() {
+		message = "Metrics rolled back as draft: validation found errors"
+	}
+	response.RespondWithSuccess(w, 200, result, message)
+}
+
+// parseReportParams reads the ticker path parameter and the year and period
+// query parameters, writing a 400 response when any of them is invalid.
+func parseReportParams(w http.ResponseWriter, r *http.Request) (ticker string, year int, period domain.ReportPeriod, ok bool) {
+	ticker = chi.URLParam(r, "ticker")
 	if ticker == "" {
 		response.RespondWithError(w, r, 400, "ticker is required", nil)
-		return
+		return "", 0, "", false
 	}
 
 	yearStr := r.URL.Query().Get("year")
 	periodStr := r.URL.Query().Get("period")
-
 	if yearStr == "" || periodStr == "" {
 		response.RespondWithError(w, r, 400, "year and period query parameters are required", nil)
-		return
+		return "", 0, "", false
 	}
 
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
 		response.RespondWithError(w, r, 400, "invalid year parameter", err)
-		return
+		return "", 0, "", false
 	}
 
-	period := domain.ReportPeriod(periodStr)
+	period = domain.ReportPeriod(periodStr)
 	if !period.IsValid() {
 		response.RespondWithError(w, r, 400, "invalid period (allowed: Q1, Q2, Q3, Q4, YEAR)", nil)
-		return
+		return "", 0, "", false
 	}
 
-	if err := h.repo.Delete(r.Context(), ticker, year, period); err != nil {
-		response.RespondWithError(w, r, 500, "failed to delete metrics", err)
-		return
-	}
+	return ticker, year, period, true
+}
 
-	response.RespondWithSuccess(w, 204, nil, "Metrics successfully deleted")
+func findVersion(versions []domain.RawDataVersion, version int) *domain.RawDataVersion {
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i]
+		}
+	}
+	return nil
 }
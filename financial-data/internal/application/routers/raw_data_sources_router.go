@@ -0,0 +1,152 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"financial_data/internal/application/middleware"
+	"financial_data/internal/application/response"
+	"financial_data/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RawDataSourcesHandler struct {
+	sources RawDataSources
+}
+
+func NewRawDataSourcesHandler(sources RawDataSources) *RawDataSourcesHandler {
+	return &RawDataSourcesHandler{sources: sources}
+}
+
+func RegisterRawDataSourcesRoutes(r chi.Router, sources RawDataSources, m *middleware.MiddlewareConfig) {
+	handler := NewRawDataSourcesHandler(sources)
+
+	r.Get("/raw-data/{ticker}/sources", handler.HandleGetSources)
+	r.Get("/raw-data/{ticker}/restatements", handler.HandleGetRestatements)
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(m.AuthMiddleware)
+
+		protected.Post("/raw-data/{ticker}/sources", handler.HandleAddSource)
+		protected.Put("/raw-data/{ticker}/sources/{id}/canonical", handler.HandleSetCanonical)
+	})
+}
+
+type addSourceRequest struct {
+	SourceYear   int                 `json:"sourceYear"`
+	SourcePeriod domain.ReportPeriod `json:"sourcePeriod"`
+	SourceURL    *string             `json:"sourceUrl"`
+	Canonical    bool                `json:"canonical"`
+	Data         domain.RawData      `json:"data"`
+}
+
+func (h *RawDataSourcesHandler) HandleGetSources(w http.ResponseWriter, r *http.Request) {
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	sources, err := h.sources.GetSources(r.Context(), ticker, year, period)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to get raw data sources", err)
+		return
+	}
+	if sources == nil {
+		sources = []domain.RawDataSource{}
+	}
+
+	response.RespondWithSuccess(w, 200, sources, "")
+}
+
+// HandleGetRestatements returns restatement deltas of every report of the
+// ticker, or of one report when year and period are given.
+func (h *RawDataSourcesHandler) HandleGetRestatements(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	var year int
+	var period domain.ReportPeriod
+	if r.URL.Query().Get("year") != "" || r.URL.Query().Get("period") != "" {
+		var ok bool
+		if ticker, year, period, ok = parseReportParams(w, r); !ok {
+			return
+		}
+	}
+
+	restatements, err := h.sources.GetRestatements(r.Context(), ticker, year, period)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to get restatements", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, restatements, "")
+}
+
+// HandleAddSource saves the figures of a report as published in a source
+// report: the report itself for the original figures, a later one for
+// restated figures.
+func (h *RawDataSourcesHandler) HandleAddSource(w http.ResponseWriter, r *http.Request) {
+	ticker, year, period, ok := parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req addSourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, r, 400, "invalid request body", err)
+		return
+	}
+
+	source := &domain.RawDataSource{
+		Ticker:       ticker,
+		Year:         year,
+		Period:       period,
+		SourceYear:   req.SourceYear,
+		SourcePeriod: req.SourcePeriod,
+		SourceURL:    req.SourceURL,
+		Data:         req.Data,
+	}
+
+	validation, err := h.sources.AddSource(r.Context(), source, req.Canonical, middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, err.Error(), err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to save raw data source", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 201, map[string]any{"source": source, "validation": validation}, "Raw data source saved")
+}
+
+// HandleSetCanonical makes the source the figures used for the report and its ratios.
+func (h *RawDataSourcesHandler) HandleSetCanonical(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid source id", err)
+		return
+	}
+
+	validation, err := h.sources.SetCanonical(r.Context(), ticker, id, middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "raw data source not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to set canonical source", err)
+		return
+	}
+
+	message := "Canonical source updated"
+	if validation.HasErrors() {
+		message = "Canonical source saved as draft: validation found errors"
+	}
+	response.RespondWithSuccess(w, 200, map[string]any{"id": id, "validation": validation}, message)
+}
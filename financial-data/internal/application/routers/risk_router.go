@@ -0,0 +1,76 @@
+package routers
+
+import (
+	"errors"
+	"financial_data/internal/application/response"
+	"financial_data/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// betaDefaultWindow is the regression window in months: two years of weekly returns.
+	betaDefaultWindow = 24
+	betaMaxWindow     = 120
+)
+
+type RiskHandler struct {
+	beta BetaEstimator
+}
+
+func NewRiskHandler(beta BetaEstimator) *RiskHandler {
+	return &RiskHandler{beta: beta}
+}
+
+func RegisterRiskRoutes(r chi.Router, beta BetaEstimator) {
+	handler := NewRiskHandler(beta)
+
+	r.Get("/risk/{ticker}/beta", handler.HandleGetBeta)
+}
+
+// HandleGetBeta estimates the beta of the ticker. Query parameters: benchmark
+// (IMOEX by default, sector for the sector index or a sector index code),
+// frequency of returns (daily, weekly by default, monthly) and window in months.
+func (h *RiskHandler) HandleGetBeta(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	frequency := domain.BetaWeekly
+	if frequencyStr := r.URL.Query().Get("frequency"); frequencyStr != "" {
+		frequency = domain.BetaFrequency(frequencyStr)
+		if !frequency.IsValid() {
+			response.RespondWithError(w, r, 400, "frequency must be daily, weekly or monthly", nil)
+			return
+		}
+	}
+
+	window := betaDefaultWindow
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		parsed, err := strconv.Atoi(windowStr)
+		if err != nil || parsed < 1 || parsed > betaMaxWindow {
+			response.RespondWithError(w, r, 400, "window must be a number of months from 1 to 120", err)
+			return
+		}
+		window = parsed
+	}
+
+	beta, err := h.beta.Estimate(r.Context(), ticker, r.URL.Query().Get("benchmark"), frequency, window)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			response.RespondWithError(w, r, 400, "invalid benchmark", err)
+		case errors.Is(err, domain.ErrNotFound):
+			response.RespondWithError(w, r, 404, "not enough data to estimate beta", err)
+		default:
+			response.RespondWithError(w, r, 500, "failed to estimate beta", err)
+		}
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, beta, "Successfully estimated beta")
+}
@@ -0,0 +1,45 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"financial_data/internal/application/response"
+	"financial_data/internal/domain"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ScreenerHandler struct {
+	screener Screener
+}
+
+func NewScreenerHandler(screener Screener) *ScreenerHandler {
+	return &ScreenerHandler{screener: screener}
+}
+
+func RegisterScreenerRoutes(r chi.Router, screener Screener) {
+	handler := NewScreenerHandler(screener)
+
+	r.Post("/screener", handler.HandleScreen)
+}
+
+func (h *ScreenerHandler) HandleScreen(w http.ResponseWriter, r *http.Request) {
+	var req domain.ScreenerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, r, 400, "invalid request body", err)
+		return
+	}
+
+	result, err := h.screener.Screen(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, "invalid screener request", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to run screener", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, result, "")
+}
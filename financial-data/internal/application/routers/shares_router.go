@@ -0,0 +1,189 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"financial_data/internal/application/middleware"
+	"financial_data/internal/application/response"
+	"financial_data/internal/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SharesHandler struct {
+	repo          ShareEventsRepository
+	ratiosService RatiosCalculator
+}
+
+func NewSharesHandler(repo ShareEventsRepository, ratiosService RatiosCalculator) *SharesHandler {
+	return &SharesHandler{repo: repo, ratiosService: ratiosService}
+}
+
+func RegisterSharesRoutes(r chi.Router, repo ShareEventsRepository, ratiosService RatiosCalculator, m *middleware.MiddlewareConfig) {
+	handler := NewSharesHandler(repo, ratiosService)
+
+	r.Get("/shares/{ticker}", handler.HandleGetByTicker)
+	r.Get("/shares/{ticker}/at", handler.HandleGetSharesAt)
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(m.AuthMiddleware)
+
+		protected.Post("/shares/{ticker}", handler.HandleCreate)
+		protected.Put("/shares/{ticker}/{id}", handler.HandleUpdate)
+		protected.Delete("/shares/{ticker}/{id}", handler.HandleDelete)
+	})
+}
+
+func (h *SharesHandler) HandleGetByTicker(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	events, err := h.repo.GetByTicker(r.Context(), ticker)
+	if err != nil {
+		response.RespondWithError(w, r, 500, "failed to load share events", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, events, "Successfully retrieved share count history")
+}
+
+func (h *SharesHandler) HandleGetSharesAt(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	dateStr := r.URL.Query().Get("date")
+	if ticker == "" || dateStr == "" {
+		response.RespondWithError(w, r, 400, "ticker and date are required", nil)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid date format (expected YYYY-MM-DD)", err)
+		return
+	}
+
+	shares, err := h.repo.GetSharesAt(r.Context(), ticker, date)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "no share count known for this date", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to get share count", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, 200, shares, "Successfully got share count")
+}
+
+func (h *SharesHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	if ticker == "" {
+		response.RespondWithError(w, r, 400, "ticker is required", nil)
+		return
+	}
+
+	var event domain.ShareEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		response.RespondWithError(w, r, 400, "invalid request body", err)
+		return
+	}
+
+	event.Ticker = ticker
+
+	if err := h.repo.Create(r.Context(), &event); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, "invalid share event", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to create share event", err)
+		return
+	}
+
+	h.recalculate(r, ticker)
+
+	response.RespondWithSuccess(w, 201, event, "Share event successfully created")
+}
+
+func (h *SharesHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	idStr := chi.URLParam(r, "id")
+
+	if ticker == "" || idStr == "" {
+		response.RespondWithError(w, r, 400, "ticker and id are required", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid id", err)
+		return
+	}
+
+	var event domain.ShareEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		response.RespondWithError(w, r, 400, "invalid request body", err)
+		return
+	}
+
+	event.Ticker = ticker
+
+	if err := h.repo.Update(r.Context(), id, &event); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.RespondWithError(w, r, 400, "invalid share event", err)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "share event not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to update share event", err)
+		return
+	}
+
+	h.recalculate(r, ticker)
+
+	response.RespondWithSuccess(w, 200, map[string]string{"status": "updated"}, "Share event successfully updated")
+}
+
+func (h *SharesHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	ticker := chi.URLParam(r, "ticker")
+	idStr := chi.URLParam(r, "id")
+
+	if ticker == "" || idStr == "" {
+		response.RespondWithError(w, r, 400, "ticker and id are required", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.RespondWithError(w, r, 400, "invalid id", err)
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), ticker, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.RespondWithError(w, r, 404, "share event not found", err)
+			return
+		}
+		response.RespondWithError(w, r, 500, "failed to delete share event", err)
+		return
+	}
+
+	h.recalculate(r, ticker)
+
+	response.RespondWithSuccess(w, 204, nil, "Share event successfully deleted")
+}
+
+// recalculate refreshes historical ratios, since per-share values and market
+// caps depend on the share count history.
+func (h *SharesHandler) recalculate(r *http.Request, ticker string) {
+	if err := h.ratiosService.RecalculateAll(r.Context(), ticker); err != nil {
+		slog.Warn("failed to recalculate ratios after share count change", "ticker", ticker, "error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"financial_data/internal/application/routers"
+	"financial_data/internal/domain"
+	"fmt"
+)
+
+// SectorStatsService benchmarks ratios within a sector on the latest row of each
+// company. Share classes are left out: they repeat their issuer's ratios.
+type SectorStatsService struct {
+	ratiosRepo  routers.RatiosRepository
+	companyRepo routers.CompanyRepository
+}
+
+func NewSectorStatsService(ratiosRepo routers.RatiosRepository, companyRepo routers.CompanyRepository) *SectorStatsService {
+	return &SectorStatsService{ratiosRepo: ratiosRepo, companyRepo: companyRepo}
+}
+
+func (s *SectorStatsService) GetSectorStats(ctx context.Context, sector domain.Sector, ttm bool) (*domain.SectorStats, error) {
+	rows, err := s.sectorRows(ctx, sector, ttm)
+	if err != nil {
+		return nil, err
+	}
+
+	period := domain.YEAR
+	if ttm {
+		period = domain.TTM
+	}
+
+	return &domain.SectorStats{
+		Sector:    sector,
+		Period:    string(period),
+		Companies: len(rows),
+		Metrics:   domain.CalculateSectorStats(rows),
+	}, nil
+}
+
+func (s *SectorStatsService) GetRank(ctx context.Context, ticker string, ttm bool) (*domain.CompanyRank, error) {
+	company, err := s.companyRepo.GetByTicker(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+	sector := domain.Sector(company.SectorID)
+
+	// a share class is ranked by its issuer's row
+	rankedTicker := ticker
+	if company.IssuerTicker != "" {
+		rankedTicker = company.IssuerTicker
+	}
+
+	rows, err := s.sectorRows(ctx, sector, ttm)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.Ticker != rankedTicker {
+			continue
+		}
+		return &domain.CompanyRank{
+			Ticker:  ticker,
+			Sector:  sector,
+			Year:    row.Year,
+			Period:  row.Period,
+			Metrics: domain.RankInSector(row, rows),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no ratios for %s in sector %d: %w", ticker, sector, domain.ErrNotFound)
+}
+
+func (s *SectorStatsService) sectorRows(ctx context.Context, sector domain.Sector, ttm bool) ([]domain.Ratios, error) {
+	rows, err := s.ratiosRepo.GetLatestBySector(ctx, sector, ttm)
+	if err != nil {
+		return nil, err
+	}
+
+	companies, err := s.companyRepo.GetBySector(ctx, int(sector))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sector companies: %w", err)
+	}
+	shareClasses := make(map[string]bool)
+	for _, c := range companies {
+		if c.IssuerTicker != "" {
+			shareClasses[c.Ticker] = true
+		}
+	}
+
+	result := rows[:0]
+	for _, row := range rows {
+		if !shareClasses[row.Ticker] {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
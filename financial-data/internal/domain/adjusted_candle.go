@@ -0,0 +1,111 @@
+package domain
+
+import "sort"
+
+// AdjustedCandle is a candle with prices back-adjusted for splits and dividends,
+// so the latest prices stay as traded and older ones are comparable to them.
+// TotalReturnIndex starts at 100 on the first candle and assumes dividends are
+// reinvested at the close.
+type AdjustedCandle struct {
+	Candle
+	AdjustedOpen     float64 `json:"adjustedOpen"`
+	AdjustedClose    float64 `json:"adjustedClose"`
+	AdjustedHigh     float64 `json:"adjustedHigh"`
+	AdjustedLow      float64 `json:"adjustedLow"`
+	AdjustmentFactor float64 `json:"adjustmentFactor"`
+	TotalReturnIndex float64 `json:"totalReturnIndex"`
+}
+
+// AdjustCandles adjusts candles (ordered by begin) for splits and consolidations
+// from events and for RUB dividends. A dividend is applied on the first candle
+// trading on or after its ex-date, with the factor 1 - amount / previous close.
+func AdjustCandles(candles []Candle, events []ShareEvent, dividends []Dividends) []AdjustedCandle {
+	n := len(candles)
+	result := make([]AdjustedCandle, n)
+	if n == 0 {
+		return result
+	}
+
+	days := make([]string, n)
+	factors := make([]float64, n)
+	for i, c := range candles {
+		days[i] = candleDay(c)
+		factors[i] = 1
+	}
+
+	// index of the first candle trading on or after date
+	firstOnOrAfter := func(date string) int {
+		return sort.SearchStrings(days, date)
+	}
+
+	splitRatio := make([]float64, n)
+	paid := make([]float64, n)
+	for i := range splitRatio {
+		splitRatio[i] = 1
+	}
+
+	for _, e := range events {
+		if e.Type != ShareEventSplit && e.Type != ShareEventConsolidation {
+			continue
+		}
+		if e.Ratio == nil || *e.Ratio <= 0 {
+			continue
+		}
+		k := firstOnOrAfter(e.Date.Format("2006-01-02"))
+		if k == 0 || k == n {
+			continue
+		}
+		for i := 0; i < k; i++ {
+			factors[i] /= *e.Ratio
+		}
+		splitRatio[k] *= *e.Ratio
+	}
+
+	for _, d := range dividends {
+		// board prices are in roubles, other currencies cannot be netted off
+		if d.Currency != "" && d.Currency != "RUB" {
+			continue
+		}
+		if d.AmountPerShare <= 0 {
+			continue
+		}
+		k := firstOnOrAfter(d.ExDividendDate.Format("2006-01-02"))
+		if k == 0 || k == n {
+			continue
+		}
+		prevClose := candles[k-1].Close
+		if prevClose <= 0 || d.AmountPerShare >= prevClose {
+			continue
+		}
+		f := 1 - d.AmountPerShare/prevClose
+		for i := 0; i < k; i++ {
+			factors[i] *= f
+		}
+		paid[k] += d.AmountPerShare
+	}
+
+	tr := 100.0
+	for i, c := range candles {
+		if i > 0 && candles[i-1].Close > 0 {
+			tr *= (c.Close + paid[i]) * splitRatio[i] / candles[i-1].Close
+		}
+		result[i] = AdjustedCandle{
+			Candle:           c,
+			AdjustedOpen:     c.Open * factors[i],
+			AdjustedClose:    c.Close * factors[i],
+			AdjustedHigh:     c.High * factors[i],
+			AdjustedLow:      c.Low * factors[i],
+			AdjustmentFactor: factors[i],
+			TotalReturnIndex: tr,
+		}
+	}
+
+	return result
+}
+
+func candleDay(c Candle) string {
+	if len(c.Begin) < 10 {
+		return c.Begin
+	}
+	return c.Begin[:10]
+}
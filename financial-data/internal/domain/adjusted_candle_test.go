@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAdjustCandles(t *testing.T) {
+	candle := func(day string, close float64) Candle {
+		return Candle{Begin: day + " 00:00:00", Open: close, High: close, Low: close, Close: close}
+	}
+	ratio := func(r float64) *float64 { return &r }
+
+	tests := []struct {
+		name      string
+		candles   []Candle
+		events    []ShareEvent
+		dividends []Dividends
+		factors   []float64
+		tr        []float64
+	}{
+		{
+			name:    "2:1 split",
+			candles: []Candle{candle("2025-01-06", 100), candle("2025-01-07", 110), candle("2025-01-08", 55), candle("2025-01-09", 60)},
+			events:  []ShareEvent{{Date: date(2025, time.January, 8), Type: ShareEventSplit, Ratio: ratio(2)}},
+			factors: []float64{0.5, 0.5, 1, 1},
+			tr:      []float64{100, 110, 110, 120},
+		},
+		{
+			name:      "dividend on the ex-date",
+			candles:   []Candle{candle("2025-01-06", 100), candle("2025-01-07", 110), candle("2025-01-08", 100), candle("2025-01-09", 105)},
+			dividends: []Dividends{{ExDividendDate: date(2025, time.January, 8), AmountPerShare: 10, Currency: "RUB"}},
+			factors:   []float64{10.0 / 11, 10.0 / 11, 1, 1},
+			tr:        []float64{100, 110, 110, 115.5},
+		},
+		{
+			// ex-date on Saturday: the dividend comes off the Monday candle
+			name:      "dividend on a day without a candle",
+			candles:   []Candle{candle("2025-01-09", 100), candle("2025-01-10", 110), candle("2025-01-13", 100)},
+			dividends: []Dividends{{ExDividendDate: date(2025, time.January, 11), AmountPerShare: 10, Currency: "RUB"}},
+			factors:   []float64{10.0 / 11, 10.0 / 11, 1},
+			tr:        []float64{100, 110, 110},
+		},
+		{
+			name:    "dividend in another currency",
+			candles: []Candle{candle("2025-01-06", 100), candle("2025-01-07", 110), candle("2025-01-08", 100)},
+			dividends: []Dividends{
+				{ExDividendDate: date(2025, time.January, 8), AmountPerShare: 10, Currency: "USD"},
+			},
+			factors: []float64{1, 1, 1},
+			tr:      []float64{100, 110, 100},
+		},
+		{
+			name:      "ex-date before the first candle",
+			candles:   []Candle{candle("2025-01-06", 100), candle("2025-01-07", 110)},
+			dividends: []Dividends{{ExDividendDate: date(2025, time.January, 3), AmountPerShare: 10, Currency: "RUB"}},
+			factors:   []float64{1, 1},
+			tr:        []float64{100, 110},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AdjustCandles(tt.candles, tt.events, tt.dividends)
+			if len(got) != len(tt.candles) {
+				t.Fatalf("Expected %d candles, got %d", len(tt.candles), len(got))
+			}
+			for i, c := range got {
+				if math.Abs(c.AdjustmentFactor-tt.factors[i]) > 1e-9 {
+					t.Errorf("Candle %s: expected factor %v, got %v", c.Begin, tt.factors[i], c.AdjustmentFactor)
+				}
+				if math.Abs(c.AdjustedClose-c.Close*tt.factors[i]) > 1e-9 {
+					t.Errorf("Candle %s: expected adjusted close %v, got %v", c.Begin, c.Close*tt.factors[i], c.AdjustedClose)
+				}
+				if math.Abs(c.TotalReturnIndex-tt.tr[i]) > 1e-9 {
+					t.Errorf("Candle %s: expected total return index %v, got %v", c.Begin, tt.tr[i], c.TotalReturnIndex)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+const (
+	CompanyTypeBank      = "bank"
+	CompanyTypeCorporate = "corporate"
+)
+
+func (r RawData) IsBank() bool {
+	return r.CompanyType != nil && *r.CompanyType == CompanyTypeBank
+}
+
+func percentOf(a, b float64) *float64 {
+	v := safeDiv(a, b)
+	if v == nil {
+		return nil
+	}
+	return ptr(*v * 100)
+}
+
+// applyBankRatios replaces metrics that make no sense for a bank (EV and EBITDA
+// multiples, liquidity, turnover, cash flow based ones) with bank ones. Revenue
+// of a bank is operating income before provisions and loans to customers are
+// stored as receivables. Return ratios are computed on the shareholders' part
+// of profit and equity. Like corporate ones, ratios are over the flows of the
+// period as reported and are not annualized: Q1–Q3 use 3M, 6M and 9M flows,
+// annual figures come from YEAR and TTM rows.
+func applyBankRatios(r *Ratios, current *RawData) {
+	companyType := CompanyTypeBank
+	r.CompanyType = &companyType
+
+	r.EnterpriseValue = nil
+	r.EVToEBITDA = nil
+	r.EVToSales = nil
+	r.EVToFCF = nil
+	r.PriceToCashFlow = nil
+	r.ROIC = nil
+	r.GrossProfitMargin = nil
+	r.OperatingProfitMargin = nil
+	r.CurrentRatio = nil
+	r.QuickRatio = nil
+	r.NetDebtToEBITDA = nil
+	r.DebtToEquity = nil
+	r.InterestCoverageRatio = nil
+	r.IncomeQuality = nil
+	r.AssetTurnover = nil
+	r.InventoryTurnover = nil
+	r.ReceivablesTurnover = nil
+	r.CashFlowPerShare = nil
+	r.FreeCashFlow = nil
+	r.CAPEX = nil
+	r.EBITDA = nil
+	r.NetDebt = nil
+	r.WorkingCapital = nil
+	r.EBITDAGrowth = nil
+	r.FCFGrowth = nil
+
+	r.ROE = nil
+	if current.NetProfitParent != nil && current.EquityParent != nil {
+		r.ROE = percentOf(float64(*current.NetProfitParent), float64(*current.EquityParent))
+	} else if current.NetProfit != nil && current.Equity != nil {
+		r.ROE = percentOf(float64(*current.NetProfit), float64(*current.Equity))
+	}
+
+	r.ROA = nil
+	if current.NetProfit != nil && current.TotalAssets != nil {
+		r.ROA = percentOf(float64(*current.NetProfit), float64(*current.TotalAssets))
+	}
+
+	netInterestIncome := current.NetInterestIncome
+	if netInterestIncome == nil && current.InterestIncome != nil && current.InterestExpense != nil {
+		nii := *current.InterestIncome - *current.InterestExpense
+		netInterestIncome = &nii
+	}
+	if netInterestIncome != nil && current.TotalAssets != nil {
+		r.NetInterestMargin = percentOf(float64(*netInterestIncome), float64(*current.TotalAssets))
+	}
+
+	if current.CreditLossProvision != nil && current.Receivables != nil {
+		r.CostOfRisk = percentOf(float64(*current.CreditLossProvision), float64(*current.Receivables))
+	}
+
+	if current.OperatingExpenses != nil && current.Revenue != nil {
+		r.CostToIncome = percentOf(float64(*current.OperatingExpenses), float64(*current.Revenue))
+	}
+
+	netCommissionIncome := current.NetCommissionIncome
+	if netCommissionIncome == nil && current.CommissionIncome != nil && current.CommissionExpense != nil {
+		nci := *current.CommissionIncome - *current.CommissionExpense
+		netCommissionIncome = &nci
+	}
+	if netCommissionIncome != nil && current.Revenue != nil {
+		r.FeeIncomeShare = percentOf(float64(*netCommissionIncome), float64(*current.Revenue))
+	}
+}
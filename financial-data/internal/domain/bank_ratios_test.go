@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBankRatios(t *testing.T) {
+	bank := func(period ReportPeriod) RawData {
+		companyType := CompanyTypeBank
+		return RawData{
+			Ticker:              "SBER",
+			Year:                2024,
+			Period:              period,
+			CompanyType:         &companyType,
+			Revenue:             i64(400),
+			NetInterestIncome:   i64(300),
+			CreditLossProvision: i64(20),
+			Receivables:         i64(1_000),
+			OperatingExpenses:   i64(120),
+			NetCommissionIncome: i64(80),
+			NetProfit:           i64(150),
+			NetProfitParent:     i64(140),
+			Equity:              i64(1_000),
+			EquityParent:        i64(700),
+			TotalAssets:         i64(10_000),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		data     func() RawData
+		nim      *float64
+		cor      *float64
+		cir      *float64
+		feeShare *float64
+		roe      *float64
+		roa      *float64
+	}{
+		{
+			name:     "annual report",
+			data:     func() RawData { return bank(YEAR) },
+			nim:      ptr(3),
+			cor:      ptr(2),
+			cir:      ptr(30),
+			feeShare: ptr(20),
+			roe:      ptr(20),
+			roa:      ptr(1.5),
+		},
+		{
+			// 3M flows are used as reported, not multiplied by four
+			name:     "Q1 is not annualized",
+			data:     func() RawData { return bank(Q1) },
+			nim:      ptr(3),
+			cor:      ptr(2),
+			cir:      ptr(30),
+			feeShare: ptr(20),
+			roe:      ptr(20),
+			roa:      ptr(1.5),
+		},
+		{
+			name: "net income from gross interest and commissions",
+			data: func() RawData {
+				d := bank(YEAR)
+				d.NetInterestIncome = nil
+				d.InterestIncome = i64(500)
+				d.InterestExpense = i64(250)
+				d.NetCommissionIncome = nil
+				d.CommissionIncome = i64(100)
+				d.CommissionExpense = i64(40)
+				return d
+			},
+			nim:      ptr(2.5),
+			cor:      ptr(2),
+			cir:      ptr(30),
+			feeShare: ptr(15),
+			roe:      ptr(20),
+			roa:      ptr(1.5),
+		},
+		{
+			name: "ROE on total profit and equity without the parent's share",
+			data: func() RawData {
+				d := bank(YEAR)
+				d.NetProfitParent = nil
+				return d
+			},
+			nim:      ptr(3),
+			cor:      ptr(2),
+			cir:      ptr(30),
+			feeShare: ptr(20),
+			roe:      ptr(15),
+			roa:      ptr(1.5),
+		},
+		{
+			name: "missing inputs",
+			data: func() RawData {
+				d := bank(YEAR)
+				d.NetInterestIncome = nil
+				d.InterestExpense = i64(250)
+				d.Receivables = i64(0)
+				d.Revenue = nil
+				return d
+			},
+			roe: ptr(20),
+			roa: ptr(1.5),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data()
+			r := CalculateRatios(&data, nil)
+
+			if r.CompanyType == nil || *r.CompanyType != CompanyTypeBank {
+				t.Errorf("Expected company type bank, got %v", r.CompanyType)
+			}
+			if r.EVToEBITDA != nil || r.CurrentRatio != nil || r.AssetTurnover != nil {
+				t.Error("Expected corporate-only ratios to be dropped for a bank")
+			}
+
+			expected := map[string]struct{ got, want *float64 }{
+				"NIM":          {r.NetInterestMargin, tt.nim},
+				"cost of risk": {r.CostOfRisk, tt.cor},
+				"cost/income":  {r.CostToIncome, tt.cir},
+				"fee share":    {r.FeeIncomeShare, tt.feeShare},
+				"ROE":          {r.ROE, tt.roe},
+				"ROA":          {r.ROA, tt.roa},
+			}
+			for name, e := range expected {
+				switch {
+				case e.want == nil && e.got != nil:
+					t.Errorf("Expected no %s, got %v", name, *e.got)
+				case e.want != nil && (e.got == nil || math.Abs(*e.got-*e.want) > 1e-9):
+					t.Errorf("Expected %s %v, got %v", name, *e.want, e.got)
+				}
+			}
+		})
+	}
+}
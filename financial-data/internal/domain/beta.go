@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type BetaFrequency string
+
+const (
+	BetaDaily   BetaFrequency = "daily"
+	BetaWeekly  BetaFrequency = "weekly"
+	BetaMonthly BetaFrequency = "monthly"
+)
+
+func (f BetaFrequency) IsValid() bool {
+	switch f {
+	case BetaDaily, BetaWeekly, BetaMonthly:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProfitTaxRate is the corporate profit tax rate in Russia since 2025; it is
+// the tax shield of debt when beta is unlevered.
+const ProfitTaxRate = 0.25
+
+// MinBetaObservations is the fewest paired returns a beta is estimated from.
+const MinBetaObservations = 20
+
+// ReturnPair holds returns of the stock and of the market over the period
+// ending on Date.
+type ReturnPair struct {
+	Date   time.Time
+	Stock  float64
+	Market float64
+}
+
+type BetaPoint struct {
+	Date time.Time `json:"date"`
+	Beta float64   `json:"beta"`
+}
+
+// Beta of a stock against a benchmark index. Raw is the regression slope of the
+// stock returns on the index returns, Adjusted pulls it towards 1 by the Blume
+// formula. Both are levered, Unlevered removes the financial risk of the
+// company's debt from Adjusted by the Hamada formula. Rolling holds betas over
+// trailing windows of the same number of observations, one per period.
+type Beta struct {
+	Ticker       string        `json:"ticker"`
+	Benchmark    string        `json:"benchmark"`
+	Frequency    BetaFrequency `json:"frequency"`
+	WindowMonths int           `json:"windowMonths"`
+	From         time.Time     `json:"from"`
+	Till         time.Time     `json:"till"`
+	Observations int           `json:"observations"`
+	Raw          float64       `json:"raw"`
+	Adjusted     float64       `json:"adjusted"`
+	Unlevered    *float64      `json:"unlevered,omitempty"`
+	DebtToEquity *float64      `json:"debtToEquity,omitempty"`
+	TaxRate      float64       `json:"taxRate"`
+	Correlation  float64       `json:"correlation"`
+	RSquared     float64       `json:"rSquared"`
+	Rolling      []BetaPoint   `json:"rolling"`
+}
+
+// PairedReturns matches closes of the stock and the market by trading day, keeps
+// the last close of every period of the frequency and returns period-over-period
+// returns. Candles must be ordered by begin.
+func PairedReturns(stock, market []Candle, frequency BetaFrequency) []ReturnPair {
+	marketCloses := make(map[string]float64, len(market))
+	for _, c := range market {
+		marketCloses[candleDay(c)] = c.Close
+	}
+
+	type sample struct {
+		period        string
+		date          time.Time
+		stock, market float64
+	}
+	var samples []sample
+	for _, c := range stock {
+		day := candleDay(c)
+		marketClose, ok := marketCloses[day]
+		if !ok || marketClose <= 0 || c.Close <= 0 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+
+		s := sample{period: returnPeriod(date, frequency), date: date, stock: c.Close, market: marketClose}
+		if len(samples) > 0 && samples[len(samples)-1].period == s.period {
+			samples[len(samples)-1] = s
+			continue
+		}
+		samples = append(samples, s)
+	}
+
+	pairs := make([]ReturnPair, 0, len(samples))
+	for i := 1; i < len(samples); i++ {
+		pairs = append(pairs, ReturnPair{
+			Date:   samples[i].date,
+			Stock:  samples[i].stock/samples[i-1].stock - 1,
+			Market: samples[i].market/samples[i-1].market - 1,
+		})
+	}
+	return pairs
+}
+
+func returnPeriod(date time.Time, frequency BetaFrequency) string {
+	switch frequency {
+	case BetaWeekly:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case BetaMonthly:
+		return date.Format("2006-01")
+	default:
+		return date.Format("2006-01-02")
+	}
+}
+
+// RegressBeta returns the slope of stock returns on market returns and their
+// correlation. It fails when the market did not move over the pairs.
+func RegressBeta(pairs []ReturnPair) (beta, correlation float64, ok bool) {
+	n := float64(len(pairs))
+	if n < 2 {
+		return 0, 0, false
+	}
+
+	var meanStock, meanMarket float64
+	for _, p := range pairs {
+		meanStock += p.Stock
+		meanMarket += p.Market
+	}
+	meanStock /= n
+	meanMarket /= n
+
+	var cov, varStock, varMarket float64
+	for _, p := range pairs {
+		ds, dm := p.Stock-meanStock, p.Market-meanMarket
+		cov += ds * dm
+		varStock += ds * ds
+		varMarket += dm * dm
+	}
+	if varMarket == 0 {
+		return 0, 0, false
+	}
+
+	beta = cov / varMarket
+	if varStock > 0 {
+		correlation = cov / math.Sqrt(varStock*varMarket)
+	}
+	return beta, correlation, true
+}
+
+// BlumeAdjust pulls a raw beta towards the market beta of 1, since betas tend
+// to revert to it over time.
+func BlumeAdjust(raw float64) float64 {
+	return 0.67*raw + 0.33
+}
+
+// UnleverBeta strips the effect of leverage from a levered beta by the Hamada
+// formula, assuming the debt carries no market risk.
+func UnleverBeta(levered, debtToEquity, taxRate float64) float64 {
+	return levered / (1 + (1-taxRate)*debtToEquity)
+}
+
+// EstimateBeta regresses the pairs dated after since and computes rolling betas
+// over all pairs with windows of the same size. Pairs must be ordered by date.
+func EstimateBeta(pairs []ReturnPair, since time.Time) (*Beta, error) {
+	start := len(pairs)
+	for start > 0 && pairs[start-1].Date.After(since) {
+		start--
+	}
+	window := pairs[start:]
+	if len(window) < MinBetaObservations {
+		return nil, fmt.Errorf("%d paired returns since %s, at least %d needed: %w",
+			len(window), since.Format("2006-01-02"), MinBetaObservations, ErrNotFound)
+	}
+
+	raw, correlation, ok := RegressBeta(window)
+	if !ok {
+		return nil, fmt.Errorf("benchmark did not move since %s: %w", since.Format("2006-01-02"), ErrNotFound)
+	}
+
+	beta := &Beta{
+		From:         window[0].Date,
+		Till:         window[len(window)-1].Date,
+		Observations: len(window),
+		Raw:          raw,
+		Adjusted:     BlumeAdjust(raw),
+		TaxRate:      ProfitTaxRate,
+		Correlation:  correlation,
+		RSquared:     correlation * correlation,
+		Rolling:      []BetaPoint{},
+	}
+
+	for end := len(window); end <= len(pairs); end++ {
+		if b, _, ok := RegressBeta(pairs[end-len(window) : end]); ok {
+			beta.Rolling = append(beta.Rolling, BetaPoint{Date: pairs[end-1].Date, Beta: b})
+		}
+	}
+
+	return beta, nil
+}
+
+// Unlever sets the unlevered beta from the book debt to equity of the report.
+// Reports without debt or with negative equity leave it unset.
+func (b *Beta) Unlever(d RawData) {
+	if d.Debt == nil || d.Equity == nil || *d.Equity <= 0 {
+		return
+	}
+	debtToEquity := float64(*d.Debt) / float64(*d.Equity)
+	unlevered := UnleverBeta(b.Adjusted, debtToEquity, b.TaxRate)
+	b.DebtToEquity = &debtToEquity
+	b.Unlevered = &unlevered
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+const betaTolerance = 1e-9
+
+// betaPairs returns daily pairs where the stock moves by beta times the market.
+func betaPairs(start time.Time, n int, beta float64) []ReturnPair {
+	pairs := make([]ReturnPair, n)
+	for i := range pairs {
+		market := 0.01 * float64(i%5-2)
+		pairs[i] = ReturnPair{Date: start.AddDate(0, 0, i), Stock: beta * market, Market: market}
+	}
+	return pairs
+}
+
+func TestBlumeAdjust(t *testing.T) {
+	tests := []struct {
+		raw, expected float64
+	}{
+		{1, 1},
+		{0, 0.33},
+		{2, 1.67},
+		{0.5, 0.665},
+		{-1, -0.34},
+	}
+
+	for _, tt := range tests {
+		if got := BlumeAdjust(tt.raw); math.Abs(got-tt.expected) > betaTolerance {
+			t.Errorf("BlumeAdjust(%v): expected %v, got %v", tt.raw, tt.expected, got)
+		}
+	}
+}
+
+func TestUnleverBeta(t *testing.T) {
+	tests := []struct {
+		name                           string
+		levered, debtToEquity, taxRate float64
+		expected                       float64
+	}{
+		{name: "no debt", levered: 1.2, debtToEquity: 0, taxRate: ProfitTaxRate, expected: 1.2},
+		{name: "debt equal to equity", levered: 1.4, debtToEquity: 1, taxRate: ProfitTaxRate, expected: 0.8},
+		{name: "no tax shield", levered: 1.2, debtToEquity: 0.5, taxRate: 0, expected: 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnleverBeta(tt.levered, tt.debtToEquity, tt.taxRate); math.Abs(got-tt.expected) > betaTolerance {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBetaUnlever(t *testing.T) {
+	b := &Beta{Adjusted: 1.4, TaxRate: ProfitTaxRate}
+	b.Unlever(RawData{Debt: i64(500), Equity: i64(500)})
+	if b.Unlevered == nil || math.Abs(*b.Unlevered-0.8) > betaTolerance {
+		t.Errorf("Expected unlevered beta 0.8, got %v", b.Unlevered)
+	}
+
+	negative := &Beta{Adjusted: 1.4, TaxRate: ProfitTaxRate}
+	negative.Unlever(RawData{Debt: i64(500), Equity: i64(-100)})
+	if negative.Unlevered != nil {
+		t.Errorf("Expected no unlevered beta with negative equity, got %v", *negative.Unlevered)
+	}
+}
+
+func TestRegressBeta(t *testing.T) {
+	start := date(2025, time.January, 1)
+	flat := betaPairs(start, 10, 1)
+	for i := range flat {
+		flat[i].Market = 0
+	}
+
+	tests := []struct {
+		name        string
+		pairs       []ReturnPair
+		beta        float64
+		correlation float64
+		ok          bool
+	}{
+		{name: "moves with the market", pairs: betaPairs(start, 10, 1.5), beta: 1.5, correlation: 1, ok: true},
+		{name: "moves against the market", pairs: betaPairs(start, 10, -0.5), beta: -0.5, correlation: -1, ok: true},
+		{name: "flat market", pairs: flat},
+		{name: "single pair", pairs: betaPairs(start, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beta, correlation, ok := RegressBeta(tt.pairs)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if math.Abs(beta-tt.beta) > betaTolerance || math.Abs(correlation-tt.correlation) > betaTolerance {
+				t.Errorf("Expected beta %v and correlation %v, got %v and %v", tt.beta, tt.correlation, beta, correlation)
+			}
+		})
+	}
+}
+
+func TestPairedReturns(t *testing.T) {
+	candle := func(day string, close float64) Candle {
+		return Candle{Begin: day + " 00:00:00", Close: close}
+	}
+	// Monday 2025-01-06 to Friday 2025-01-17; the index misses 2025-01-08
+	stock := []Candle{
+		candle("2025-01-06", 100), candle("2025-01-07", 110), candle("2025-01-08", 105),
+		candle("2025-01-09", 99), candle("2025-01-10", 120),
+		candle("2025-01-13", 126), candle("2025-01-17", 132),
+	}
+	market := []Candle{
+		candle("2025-01-06", 1000), candle("2025-01-07", 1050),
+		candle("2025-01-09", 990), candle("2025-01-10", 1100),
+		candle("2025-01-13", 1155), candle("2025-01-17", 1210),
+	}
+
+	tests := []struct {
+		frequency BetaFrequency
+		expected  []ReturnPair
+	}{
+		{
+			frequency: BetaDaily,
+			expected: []ReturnPair{
+				{Date: date(2025, time.January, 7), Stock: 0.1, Market: 0.05},
+				{Date: date(2025, time.January, 9), Stock: 99.0/110 - 1, Market: 990.0/1050 - 1},
+				{Date: date(2025, time.January, 10), Stock: 120.0/99 - 1, Market: 1.1/0.99 - 1},
+				{Date: date(2025, time.January, 13), Stock: 0.05, Market: 0.05},
+				{Date: date(2025, time.January, 17), Stock: 132.0/126 - 1, Market: 1210.0/1155 - 1},
+			},
+		},
+		{
+			// last closes of the weeks: 120/1100 on Friday 10th, 132/1210 on Friday 17th
+			frequency: BetaWeekly,
+			expected:  []ReturnPair{{Date: date(2025, time.January, 17), Stock: 0.1, Market: 0.1}},
+		},
+		{
+			frequency: BetaMonthly,
+			expected:  []ReturnPair{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.frequency), func(t *testing.T) {
+			got := PairedReturns(stock, market, tt.frequency)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %d pairs, got %d: %v", len(tt.expected), len(got), got)
+			}
+			for i, p := range got {
+				e := tt.expected[i]
+				if !p.Date.Equal(e.Date) || math.Abs(p.Stock-e.Stock) > betaTolerance || math.Abs(p.Market-e.Market) > betaTolerance {
+					t.Errorf("Pair %d: expected %+v, got %+v", i, e, p)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateBeta(t *testing.T) {
+	start := date(2025, time.January, 1)
+	// beta 2 for the first 15 days, beta 1 for the last 25
+	pairs := append(betaPairs(start, 15, 2), betaPairs(start.AddDate(0, 0, 15), 25, 1)...)
+
+	beta, err := EstimateBeta(pairs, start.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if beta.Observations != 25 {
+		t.Errorf("Expected 25 observations, got %d", beta.Observations)
+	}
+	if !beta.From.Equal(pairs[15].Date) || !beta.Till.Equal(pairs[39].Date) {
+		t.Errorf("Expected window %s to %s, got %s to %s", pairs[15].Date, pairs[39].Date, beta.From, beta.Till)
+	}
+	if math.Abs(beta.Raw-1) > betaTolerance || math.Abs(beta.Adjusted-1) > betaTolerance {
+		t.Errorf("Expected raw and adjusted beta 1, got %v and %v", beta.Raw, beta.Adjusted)
+	}
+
+	// one rolling beta per end of a 25-pair window: pairs 25 to 40
+	if len(beta.Rolling) != 16 {
+		t.Fatalf("Expected 16 rolling betas, got %d", len(beta.Rolling))
+	}
+	first, last := beta.Rolling[0], beta.Rolling[len(beta.Rolling)-1]
+	if !first.Date.Equal(pairs[24].Date) || first.Beta <= 1 {
+		t.Errorf("Expected the first rolling beta on %s above 1, got %+v", pairs[24].Date, first)
+	}
+	if !last.Date.Equal(pairs[39].Date) || math.Abs(last.Beta-1) > betaTolerance {
+		t.Errorf("Expected the last rolling beta on %s equal to 1, got %+v", pairs[39].Date, last)
+	}
+}
+
+func TestEstimateBetaErrors(t *testing.T) {
+	start := date(2025, time.January, 1)
+	flat := betaPairs(start, 30, 1)
+	for i := range flat {
+		flat[i].Market = 0
+	}
+
+	tests := []struct {
+		name  string
+		pairs []ReturnPair
+		since time.Time
+	}{
+		{name: "too few pairs in the window", pairs: betaPairs(start, 30, 1), since: start.AddDate(0, 0, 10)},
+		{name: "flat benchmark", pairs: flat, since: start.AddDate(0, 0, -1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EstimateBeta(tt.pairs, tt.since)
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected ErrNotFound, got %v", err)
+			}
+		})
+	}
+}
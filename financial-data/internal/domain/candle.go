@@ -1,5 +1,8 @@
 package domain
 
+// CandleTimeLayout is the format MOEX ISS uses for candle begin/end (Moscow time).
+const CandleTimeLayout = "2006-01-02 15:04:05"
+
 type Candle struct {
 	Open   float64 `json:"open"`
 	Close  float64 `json:"close"`
@@ -1,10 +1,132 @@
 package domain
 
+import "time"
+
+const DefaultBoard = "TQBR"
+
+type CompanyStatus string
+
+const (
+	CompanyActive   CompanyStatus = "active"
+	CompanyDelisted CompanyStatus = "delisted"
+)
+
+type InstrumentType string
+
+const (
+	InstrumentShare     InstrumentType = "share"
+	InstrumentPreferred InstrumentType = "preferred"
+	InstrumentETF       InstrumentType = "etf"
+	InstrumentBond      InstrumentType = "bond"
+)
+
+func (t InstrumentType) IsValid() bool {
+	switch t {
+	case InstrumentShare, InstrumentPreferred, InstrumentETF, InstrumentBond:
+		return true
+	default:
+		return false
+	}
+}
+
+// Market returns the ISS market of the stock engine the instrument trades on.
+func (t InstrumentType) Market() string {
+	if t == InstrumentBond {
+		return "bonds"
+	}
+	return "shares"
+}
+
 type Company struct {
-	ID       int    `json:"id,omitempty"`
-	Ticker   string `json:"ticker"`
-	Name     string `json:"name,omitempty"`
-	SectorID int    `json:"sectorId"`
-	LotSize  int    `json:"lotSize,omitempty"`
-	CEO      string `json:"ceo,omitempty"`
+	ID             int            `json:"id,omitempty"`
+	Ticker         string         `json:"ticker"`
+	Name           string         `json:"name,omitempty"`
+	SectorID       int            `json:"sectorId"`
+	LotSize        int            `json:"lotSize,omitempty"`
+	CEO            string         `json:"ceo,omitempty"`
+	Board          string         `json:"board,omitempty"`
+	InstrumentType InstrumentType `json:"instrumentType,omitempty"`
+	// IssuerTicker points a preferred share to the common ticker whose reports it shares.
+	IssuerTicker string `json:"issuerTicker,omitempty"`
+
+	// The profile below is loaded from the ISS description. FreeFloat (percent
+	// of shares in free circulation) comes from the MOEX free-float coefficients
+	// and can be set by hand for securities outside the index base.
+	ISIN        string        `json:"isin,omitempty"`
+	RegNumber   string        `json:"regNumber,omitempty"`
+	ListLevel   int           `json:"listLevel,omitempty"`
+	FreeFloat   *float64      `json:"freeFloat,omitempty"`
+	IssueSize   int64         `json:"issueSize,omitempty"`
+	Currency    string        `json:"currency,omitempty"`
+	Status      CompanyStatus `json:"status,omitempty"`
+	DelistedAt  *time.Time    `json:"delistedAt,omitempty"`
+	RefreshedAt *time.Time    `json:"refreshedAt,omitempty"`
+}
+
+// ApplyDefaults fills board and instrument type for companies created before
+// they were introduced.
+func (c *Company) ApplyDefaults() {
+	if c.Board == "" {
+		c.Board = DefaultBoard
+	}
+	if c.InstrumentType == "" {
+		c.InstrumentType = InstrumentShare
+	}
+	if c.Status == "" {
+		c.Status = CompanyActive
+	}
+}
+
+// Enrich copies the ISS description into the profile and returns the names of
+// the fields that changed. The board is only taken from ISS when none is set,
+// since a company may be registered on a board other than the primary one.
+// A free float set by hand is kept when MOEX publishes none. A security no
+// longer traded on its primary board is marked delisted.
+func (c *Company) Enrich(info StockInfo, now time.Time) []string {
+	var changed []string
+	set := func(field string, differs bool, apply func()) {
+		if differs {
+			apply()
+			changed = append(changed, field)
+		}
+	}
+
+	set("name", info.Name != "" && info.Name != c.Name, func() { c.Name = info.Name })
+	set("isin", info.ISIN != "" && info.ISIN != c.ISIN, func() { c.ISIN = info.ISIN })
+	set("regNumber", info.RegNumber != "" && info.RegNumber != c.RegNumber, func() { c.RegNumber = info.RegNumber })
+	set("listLevel", info.ListLevel != 0 && info.ListLevel != c.ListLevel, func() { c.ListLevel = info.ListLevel })
+	set("issueSize", info.NumberOfShares != 0 && int64(info.NumberOfShares) != c.IssueSize, func() { c.IssueSize = int64(info.NumberOfShares) })
+	set("lotSize", info.LotSize != 0 && info.LotSize != c.LotSize, func() { c.LotSize = info.LotSize })
+	set("currency", info.Currency != "" && info.Currency != c.Currency, func() { c.Currency = info.Currency })
+	set("board", c.Board == "" && info.Board != "", func() { c.Board = info.Board })
+	set("freeFloat", info.FreeFloat != nil && (c.FreeFloat == nil || *c.FreeFloat != *info.FreeFloat), func() {
+		freeFloat := *info.FreeFloat
+		c.FreeFloat = &freeFloat
+	})
+
+	status := CompanyActive
+	if info.Delisted() {
+		status = CompanyDelisted
+	}
+	set("status", status != c.Status, func() {
+		c.Status = status
+		c.DelistedAt = nil
+		if status == CompanyDelisted {
+			delistedAt := now
+			if info.ListedTill != nil && info.ListedTill.Before(now) {
+				delistedAt = *info.ListedTill
+			}
+			c.DelistedAt = &delistedAt
+		}
+	})
+
+	c.RefreshedAt = &now
+	return changed
+}
+
+// CompanyRefreshResult reports the profile fields changed by a refresh from ISS.
+type CompanyRefreshResult struct {
+	Ticker  string        `json:"ticker"`
+	Changed []string      `json:"changed"`
+	Status  CompanyStatus `json:"status"`
 }
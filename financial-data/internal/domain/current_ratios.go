@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// CurrentRatios are ratios of the latest confirmed fundamentals (TTM when
+// available) recombined with the live market cap. Year, Period and TTMThrough
+// of the embedded Ratios tell which reports were used.
+type CurrentRatios struct {
+	Ratios
+	Price float64   `json:"price"`
+	AsOf  time.Time `json:"asOf"`
+}
+
+// NewCurrentRatios recombines fundamentals with the live market cap in roubles.
+// Report values are kept in ReportUnits, so the market cap is converted into
+// them first; a report without known units is rejected rather than taken as
+// roubles. previous is the report the growth rates compare to and may be nil.
+func NewCurrentRatios(fundamentals, previous *RawData, marketCap float64, shares int64, asOf time.Time) (*CurrentRatios, error) {
+	liveCap, err := fundamentals.InReportUnits(marketCap)
+	if err != nil {
+		return nil, fmt.Errorf("market cap of %s: %w", fundamentals.Ticker, err)
+	}
+
+	live := *fundamentals
+	live.MarketCap = &liveCap
+	live.EnterpriseValue = nil
+	live.SharesOutstanding = &shares
+
+	ratios := CalculateRatios(&live, previous)
+	ratios.Ticker = fundamentals.Ticker
+	ratios.Year = fundamentals.Year
+	ratios.Period = fundamentals.Period
+
+	result := &CurrentRatios{Ratios: *ratios, AsOf: asOf}
+	if shares > 0 {
+		result.Price = marketCap / float64(shares)
+	}
+	return result, nil
+}
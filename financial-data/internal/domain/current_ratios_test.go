@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewCurrentRatiosConvertsMarketCapIntoReportUnits(t *testing.T) {
+	tests := []struct {
+		units     string
+		scale     int64
+		marketCap float64
+	}{
+		{units: "millions", scale: 1_000_000, marketCap: 6e12},
+		{units: "thousands", scale: 1_000, marketCap: 6e12},
+		{units: "units", scale: 1, marketCap: 6e12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.units, func(t *testing.T) {
+			// 1.5 trillion of profit and 6 trillion of equity in the units of the report
+			fundamentals := &RawData{
+				Ticker:          "SBER",
+				Year:            2024,
+				Period:          YEAR,
+				ReportUnits:     &tt.units,
+				NetProfitParent: i64(1_500_000_000_000 / tt.scale),
+				EquityParent:    i64(6_000_000_000_000 / tt.scale),
+				Revenue:         i64(3_000_000_000_000 / tt.scale),
+				NetDebt:         i64(-1_000_000_000_000 / tt.scale),
+			}
+
+			ratios, err := NewCurrentRatios(fundamentals, nil, tt.marketCap, 20_000_000_000, time.Now())
+			if err != nil {
+				t.Fatalf("Failed to calculate current ratios: %v", err)
+			}
+
+			expected := map[string]struct {
+				got  *float64
+				want float64
+			}{
+				"P/E":      {ratios.PriceToEarnings, 4},
+				"P/B":      {ratios.PriceToBook, 1},
+				"EV/Sales": {ratios.EVToSales, 5.0 / 3},
+			}
+			for name, e := range expected {
+				if e.got == nil || math.Abs(*e.got-e.want) > 1e-9 {
+					t.Errorf("Expected %s %v, got %v", name, e.want, e.got)
+				}
+			}
+			if math.Abs(ratios.Price-300) > 1e-9 {
+				t.Errorf("Expected price 300 in roubles, got %v", ratios.Price)
+			}
+			if *fundamentals.NetProfitParent != 1_500_000_000_000/tt.scale || fundamentals.MarketCap != nil {
+				t.Error("Expected fundamentals to stay untouched")
+			}
+		})
+	}
+}
+
+func TestNewCurrentRatiosRejectsUnknownUnits(t *testing.T) {
+	unknown := "hundreds"
+	for _, units := range []*string{nil, &unknown} {
+		fundamentals := &RawData{Ticker: "SBER", Year: 2024, Period: YEAR, ReportUnits: units, NetProfitParent: i64(1_500_000)}
+		if _, err := NewCurrentRatios(fundamentals, nil, 6e12, 20_000_000_000, time.Now()); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for units %v, got %v", units, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MoexT1Since is the first trading day of T+1 settlement for shares on MOEX;
+// before it trades settled on T+2.
+var MoexT1Since = time.Date(2023, time.July, 31, 0, 0, 0, 0, time.UTC)
+
+// dividendStatsYears is the window of complete years payout stability is measured over.
+const dividendStatsYears = 10
+
+// SettlementDays returns the settlement lag of a trade that has to settle by date.
+func SettlementDays(date time.Time) int {
+	if date.Before(MoexT1Since) {
+		return 2
+	}
+	return 1
+}
+
+// addTradingDays moves date by n trading days, skipping weekends. Exchange
+// holidays are not known here, so dates around them may be off by a day.
+func addTradingDays(date time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return date
+}
+
+// LastBuyDate is the last trading day a share bought still carries the
+// dividend. With a record date it is the settlement lag before it (T+1 since
+// MoexT1Since, T+2 before), otherwise the trading day before the ex-date.
+func (d Dividends) LastBuyDate() time.Time {
+	if d.RecordDate != nil {
+		return addTradingDays(*d.RecordDate, -SettlementDays(*d.RecordDate))
+	}
+	return addTradingDays(d.ExDividendDate, -1)
+}
+
+// ExDateFromRecordDate is the first trading day without the dividend for a record date.
+func ExDateFromRecordDate(recordDate time.Time) time.Time {
+	return addTradingDays(addTradingDays(recordDate, -SettlementDays(recordDate)), 1)
+}
+
+type DividendCalendarEntry struct {
+	Dividends
+	CompanyName string    `json:"companyName,omitempty"`
+	LastBuyDate time.Time `json:"lastBuyDate"`
+}
+
+type AnnualDividend struct {
+	Year     int     `json:"year"`
+	Amount   float64 `json:"amount"`
+	Payments int     `json:"payments"`
+}
+
+// DividendStats describes the dividend history of a ticker. Years are years of
+// the ex-dates; streaks and stability cover complete years only.
+type DividendStats struct {
+	Ticker   string   `json:"ticker"`
+	Currency string   `json:"currency,omitempty"`
+	Price    *float64 `json:"price,omitempty"`
+	// TTMAmount sums dividends with an ex-date in the last twelve months.
+	TTMAmount float64  `json:"ttmAmount"`
+	TTMYield  *float64 `json:"ttmYield,omitempty"`
+
+	Annual []AnnualDividend `json:"annual"`
+	// GrowthStreak counts consecutive years the annual dividend grew, ending with the last complete year.
+	GrowthStreak int `json:"growthStreak"`
+	// NoCutStreak counts consecutive years paid without a cut, ending with the last complete year.
+	NoCutStreak int `json:"noCutStreak"`
+
+	// Stability over the last complete years since the first payment, at most dividendStatsYears.
+	YearsInWindow   int      `json:"yearsInWindow"`
+	YearsPaid       int      `json:"yearsPaid"`
+	Cuts            int      `json:"cuts"`
+	PaymentsPerYear float64  `json:"paymentsPerYear"`
+	AmountCV        *float64 `json:"amountCv,omitempty"`
+	AvgPayoutRatio  *float64 `json:"avgPayoutRatio,omitempty"`
+
+	Next *DividendCalendarEntry `json:"next,omitempty"`
+}
+
+// CalculateDividendStats summarizes dividends of a ticker as of asOf. Only
+// dividends in the currency of the latest one are counted. price is the live
+// price used for the TTM yield and may be nil.
+func CalculateDividendStats(ticker string, dividends []Dividends, price *float64, asOf time.Time) *DividendStats {
+	stats := &DividendStats{Ticker: ticker, Price: price, Annual: []AnnualDividend{}}
+	if len(dividends) == 0 {
+		return stats
+	}
+
+	sorted := make([]Dividends, len(dividends))
+	copy(sorted, dividends)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ExDividendDate.Before(sorted[j].ExDividendDate) })
+	stats.Currency = sorted[len(sorted)-1].Currency
+
+	byYear := make(map[int]*AnnualDividend)
+	var payoutSum float64
+	var payoutCount int
+	yearAgo := asOf.AddDate(-1, 0, 0)
+	for _, d := range sorted {
+		if d.Currency != stats.Currency {
+			continue
+		}
+		if !d.ExDividendDate.After(asOf) {
+			year := d.ExDividendDate.Year()
+			if byYear[year] == nil {
+				byYear[year] = &AnnualDividend{Year: year}
+			}
+			byYear[year].Amount += d.AmountPerShare
+			byYear[year].Payments++
+			if d.ExDividendDate.After(yearAgo) {
+				stats.TTMAmount += d.AmountPerShare
+			}
+			if d.PayoutRatio != nil {
+				payoutSum += *d.PayoutRatio
+				payoutCount++
+			}
+		} else if stats.Next == nil {
+			stats.Next = &DividendCalendarEntry{Dividends: d, LastBuyDate: d.LastBuyDate()}
+		}
+	}
+
+	if price != nil && *price > 0 && stats.TTMAmount > 0 {
+		stats.TTMYield = ptr(stats.TTMAmount / *price * 100)
+	}
+	if payoutCount > 0 {
+		stats.AvgPayoutRatio = ptr(payoutSum / float64(payoutCount))
+	}
+	if len(byYear) == 0 {
+		return stats
+	}
+
+	firstYear, lastYear := math.MaxInt, asOf.Year()
+	for year := range byYear {
+		firstYear = min(firstYear, year)
+	}
+	amount := func(year int) float64 {
+		if a := byYear[year]; a != nil {
+			return a.Amount
+		}
+		return 0
+	}
+	for year := firstYear; year <= lastYear; year++ {
+		a := AnnualDividend{Year: year}
+		if byYear[year] != nil {
+			a = *byYear[year]
+		}
+		stats.Annual = append(stats.Annual, a)
+	}
+
+	lastComplete := lastYear - 1
+	for year := lastComplete; year > firstYear && amount(year) > amount(year-1) && amount(year-1) > 0; year-- {
+		stats.GrowthStreak++
+	}
+	for year := lastComplete; year >= firstYear && amount(year) > 0 && amount(year) >= amount(year-1); year-- {
+		stats.NoCutStreak++
+	}
+
+	windowStart := max(firstYear, lastComplete-dividendStatsYears+1)
+	var amounts []float64
+	var payments int
+	for year := windowStart; year <= lastComplete; year++ {
+		a := amount(year)
+		amounts = append(amounts, a)
+		if a > 0 {
+			stats.YearsPaid++
+			payments += byYear[year].Payments
+		}
+		if year > windowStart && a < amount(year-1) {
+			stats.Cuts++
+		}
+	}
+	stats.YearsInWindow = len(amounts)
+	if stats.YearsInWindow > 0 {
+		stats.PaymentsPerYear = float64(payments) / float64(stats.YearsInWindow)
+	}
+	if len(amounts) > 1 {
+		var mean float64
+		for _, a := range amounts {
+			mean += a
+		}
+		mean /= float64(len(amounts))
+		if mean > 0 {
+			var variance float64
+			for _, a := range amounts {
+				variance += (a - mean) * (a - mean)
+			}
+			variance /= float64(len(amounts))
+			stats.AmountCV = ptr(math.Sqrt(variance) / mean)
+		}
+	}
+
+	return stats
+}
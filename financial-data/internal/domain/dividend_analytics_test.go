@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSettlementDays(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected int
+	}{
+		{date(2023, time.July, 28), 2},
+		{date(2023, time.July, 30), 2},
+		{date(2023, time.July, 31), 1},
+		{date(2024, time.July, 17), 1},
+	}
+
+	for _, tt := range tests {
+		if got := SettlementDays(tt.date); got != tt.expected {
+			t.Errorf("SettlementDays(%s): expected %d, got %d", tt.date.Format(time.DateOnly), tt.expected, got)
+		}
+	}
+}
+
+func TestLastBuyDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordDate *time.Time
+		exDate     time.Time
+		expected   time.Time
+	}{
+		{
+			name:       "T+2 midweek",
+			recordDate: ptrTime(date(2023, time.July, 27)), // Thursday
+			expected:   date(2023, time.July, 25),
+		},
+		{
+			name:       "T+2 across a weekend",
+			recordDate: ptrTime(date(2023, time.July, 24)), // Monday
+			expected:   date(2023, time.July, 20),
+		},
+		{
+			name:       "last T+2 record date",
+			recordDate: ptrTime(date(2023, time.July, 28)), // Friday
+			expected:   date(2023, time.July, 26),
+		},
+		{
+			name:       "first T+1 record date falls back over the weekend",
+			recordDate: ptrTime(date(2023, time.July, 31)), // Monday
+			expected:   date(2023, time.July, 28),
+		},
+		{
+			name:       "T+1 lands on the switch day",
+			recordDate: ptrTime(date(2023, time.August, 1)), // Tuesday
+			expected:   date(2023, time.July, 31),
+		},
+		{
+			name:       "T+1 midweek",
+			recordDate: ptrTime(date(2024, time.July, 17)), // Wednesday
+			expected:   date(2024, time.July, 16),
+		},
+		{
+			name:       "record date on a weekend",
+			recordDate: ptrTime(date(2024, time.July, 21)), // Sunday
+			expected:   date(2024, time.July, 19),
+		},
+		{
+			name:     "without record date the day before the ex-date",
+			exDate:   date(2024, time.July, 22), // Monday
+			expected: date(2024, time.July, 19),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Dividends{RecordDate: tt.recordDate, ExDividendDate: tt.exDate}
+			if got := d.LastBuyDate(); !got.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected.Format(time.DateOnly), got.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestExDateFromRecordDate(t *testing.T) {
+	tests := []struct {
+		recordDate time.Time
+		expected   time.Time
+	}{
+		// T+2: the ex-date is the trading day before the record date
+		{date(2023, time.July, 24), date(2023, time.July, 21)},
+		{date(2023, time.July, 28), date(2023, time.July, 27)},
+		// T+1: the ex-date is the record date itself
+		{date(2023, time.July, 31), date(2023, time.July, 31)},
+		{date(2024, time.July, 16), date(2024, time.July, 16)},
+	}
+
+	for _, tt := range tests {
+		if got := ExDateFromRecordDate(tt.recordDate); !got.Equal(tt.expected) {
+			t.Errorf("ExDateFromRecordDate(%s): expected %s, got %s",
+				tt.recordDate.Format(time.DateOnly), tt.expected.Format(time.DateOnly), got.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestCalculateDividendStatsNext(t *testing.T) {
+	dividends := []Dividends{
+		{ExDividendDate: date(2023, time.July, 11), AmountPerShare: 25, Currency: "RUB"},
+		{ExDividendDate: date(2024, time.July, 11), AmountPerShare: 33.3, Currency: "RUB"},
+		{ExDividendDate: date(2025, time.July, 17), RecordDate: ptrTime(date(2025, time.July, 18)), AmountPerShare: 34.84, Currency: "RUB"},
+	}
+
+	stats := CalculateDividendStats("SBER", dividends, nil, date(2025, time.June, 1))
+
+	if stats.Next == nil {
+		t.Fatal("Expected the next dividend")
+	}
+	if expected := date(2025, time.July, 17); !stats.Next.LastBuyDate.Equal(expected) {
+		t.Errorf("Expected last buy date %s, got %s", expected.Format(time.DateOnly), stats.Next.LastBuyDate.Format(time.DateOnly))
+	}
+	if stats.TTMAmount != 33.3 {
+		t.Errorf("Expected TTM amount 33.3, got %v", stats.TTMAmount)
+	}
+	if stats.GrowthStreak != 1 {
+		t.Errorf("Expected growth streak 1, got %d", stats.GrowthStreak)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
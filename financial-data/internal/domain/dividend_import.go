@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// dividendPaymentWorkingDays is the deadline for paying dividends to nominee
+// holders after the record date. ISS does not publish payment dates, so the
+// deadline stands in until the actual date is entered.
+const dividendPaymentWorkingDays = 10
+
+// ParseMoexDividends converts the ISS dividends table of a security. Columns
+// are looked up by name; rows without a registry close date or amount are skipped.
+func ParseMoexDividends(table IssTable) []Dividends {
+	result := make([]Dividends, 0, len(table.Data))
+	for _, record := range table.Records() {
+		ticker, _ := record["secid"].(string)
+		closeDate, _ := record["registryclosedate"].(string)
+		amount, ok := record["value"].(float64)
+		if !ok || amount <= 0 {
+			continue
+		}
+		recordDate, err := time.Parse("2006-01-02", closeDate)
+		if err != nil {
+			continue
+		}
+
+		currency, _ := record["currencyid"].(string)
+		switch currency {
+		case "", "SUR":
+			currency = "RUB"
+		}
+
+		result = append(result, Dividends{
+			Ticker:         ticker,
+			ExDividendDate: ExDateFromRecordDate(recordDate),
+			PaymentDate:    addTradingDays(recordDate, dividendPaymentWorkingDays),
+			AmountPerShare: amount,
+			Currency:       currency,
+			RecordDate:     &recordDate,
+		})
+	}
+	return result
+}
+
+// SetYield sets the dividend yield in percent of the price before the ex-date.
+func (d *Dividends) SetYield(price float64) {
+	if price <= 0 {
+		return
+	}
+	d.DividendYield = ptr(d.AmountPerShare / price * 100)
+}
+
+// DividendImportResult counts dividends of one ticker taken from ISS.
+type DividendImportResult struct {
+	Ticker    string `json:"ticker"`
+	Listed    int    `json:"listed"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Unchanged int    `json:"unchanged"`
+	Announced int    `json:"announced"`
+}
@@ -11,4 +11,6 @@ type Dividends struct {
 	DividendYield   *float64  `json:"dividendYield,omitempty"`
 	PayoutRatio     *float64  `json:"payoutRatio,omitempty"`
 	Currency        string    `json:"currency"`
+	// RecordDate is the registry close date; the last day to buy is derived from it.
+	RecordDate *time.Time `json:"recordDate,omitempty"`
 }
@@ -0,0 +1,64 @@
+package domain
+
+const (
+	// IndexIMOEX is the MOEX Russia index, the benchmark of the broad market.
+	IndexIMOEX = "IMOEX"
+	// IndexRTSI is the same basket as IMOEX valued in dollars.
+	IndexRTSI = "RTSI"
+	// IndexMOEXBC is the blue chip index of the fifteen most liquid shares.
+	IndexMOEXBC = "MOEXBC"
+)
+
+// IndexInfo describes an index ingested from the ISS index market. Sectors
+// lists the sectors the index is the benchmark of.
+type IndexInfo struct {
+	Code     string   `json:"code"`
+	Name     string   `json:"name"`
+	Currency string   `json:"currency"`
+	Sectors  []Sector `json:"sectors,omitempty"`
+}
+
+var indices = []IndexInfo{
+	{Code: IndexIMOEX, Name: "Индекс МосБиржи", Currency: "RUB"},
+	{Code: IndexRTSI, Name: "Индекс РТС", Currency: "USD"},
+	{Code: IndexMOEXBC, Name: "Индекс голубых фишек", Currency: "RUB"},
+	{Code: "MOEXOG", Name: "Индекс нефти и газа", Currency: "RUB", Sectors: []Sector{Oils}},
+	{Code: "MOEXFN", Name: "Индекс финансов", Currency: "RUB", Sectors: []Sector{Finance}},
+	{Code: "MOEXIT", Name: "Индекс информационных технологий", Currency: "RUB", Sectors: []Sector{Technology}},
+	{Code: "MOEXTL", Name: "Индекс телекоммуникаций", Currency: "RUB", Sectors: []Sector{Telecom}},
+	{Code: "MOEXMM", Name: "Индекс металлов и добычи", Currency: "RUB", Sectors: []Sector{Metals, Mining}},
+	{Code: "MOEXEU", Name: "Индекс электроэнергетики", Currency: "RUB", Sectors: []Sector{Utilities, Energy}},
+	{Code: "MOEXRE", Name: "Индекс недвижимости", Currency: "RUB", Sectors: []Sector{RealEstate, Construction}},
+	{Code: "MOEXCN", Name: "Индекс потребительского сектора", Currency: "RUB", Sectors: []Sector{ConsumerStaples, ConsumerDiscretionary, Retail, Healthcare, Agriculture}},
+	{Code: "MOEXCH", Name: "Индекс химии и нефтехимии", Currency: "RUB", Sectors: []Sector{Chemicals}},
+	{Code: "MOEXTN", Name: "Индекс транспорта", Currency: "RUB", Sectors: []Sector{Transportation}},
+}
+
+// Indices lists the indices imported by the service: the broad market ones
+// first, then the sector indices.
+func Indices() []IndexInfo {
+	return append([]IndexInfo(nil), indices...)
+}
+
+// LookupIndex returns the catalog entry of an index.
+func LookupIndex(code string) (IndexInfo, bool) {
+	for _, info := range indices {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return IndexInfo{}, false
+}
+
+// SectorIndex returns the MOEX index of the sector. Sectors without an index
+// of their own, such as industrials, are compared with the broad market.
+func SectorIndex(sector Sector) (string, bool) {
+	for _, info := range indices {
+		for _, s := range info.Sectors {
+			if s == sector {
+				return info.Code, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type MacroSeries string
+
+const (
+	// SeriesKeyRate is the Bank of Russia key rate, stored in cb_rates as the dates it changed.
+	SeriesKeyRate MacroSeries = "key-rate"
+	// SeriesCPI is consumer price inflation, % year on year, dated by the last day of the month.
+	SeriesCPI    MacroSeries = "cpi"
+	SeriesUSDRUB MacroSeries = "usd-rub"
+	SeriesCNYRUB MacroSeries = "cny-rub"
+	SeriesRUONIA MacroSeries = "ruonia"
+)
+
+// OFZSeriesPrefix starts the names of the OFZ yield curve series.
+const OFZSeriesPrefix = "ofz-"
+
+// OFZTenors are the terms in years of the zero-coupon OFZ yield curve published by MOEX.
+var OFZTenors = []float64{0.25, 0.5, 0.75, 1, 2, 3, 5, 7, 10, 15, 20, 30}
+
+// OFZSeries names the series of the zero-coupon OFZ yield for a tenor in years:
+// ofz-3m for a quarter of a year, ofz-10y for ten years.
+func OFZSeries(tenor float64) MacroSeries {
+	if tenor < 1 {
+		return MacroSeries(fmt.Sprintf("%s%dm", OFZSeriesPrefix, int(tenor*12+0.5)))
+	}
+	return MacroSeries(OFZSeriesPrefix + strconv.FormatFloat(tenor, 'f', -1, 64) + "y")
+}
+
+type MacroSeriesInfo struct {
+	Series    MacroSeries `json:"series"`
+	Name      string      `json:"name"`
+	Unit      string      `json:"unit"`
+	Frequency string      `json:"frequency"`
+	Source    string      `json:"source"`
+}
+
+// MacroSeriesCatalog lists the series imported by the service.
+func MacroSeriesCatalog() []MacroSeriesInfo {
+	catalog := []MacroSeriesInfo{
+		{Series: SeriesKeyRate, Name: "Ключевая ставка Банка России", Unit: "%", Frequency: "on change", Source: "cbr"},
+		{Series: SeriesCPI, Name: "Инфляция, г/г", Unit: "%", Frequency: "monthly", Source: "cbr"},
+		{Series: SeriesUSDRUB, Name: "Официальный курс USD/RUB", Unit: "RUB", Frequency: "daily", Source: "cbr"},
+		{Series: SeriesCNYRUB, Name: "Официальный курс CNY/RUB", Unit: "RUB", Frequency: "daily", Source: "cbr"},
+		{Series: SeriesRUONIA, Name: "RUONIA", Unit: "%", Frequency: "daily", Source: "cbr"},
+	}
+	for _, tenor := range OFZTenors {
+		catalog = append(catalog, MacroSeriesInfo{
+			Series:    OFZSeries(tenor),
+			Name:      fmt.Sprintf("Бескупонная доходность ОФЗ, %s", OFZSeries(tenor)[len(OFZSeriesPrefix):]),
+			Unit:      "%",
+			Frequency: "daily",
+			Source:    "moex",
+		})
+	}
+	return catalog
+}
+
+// LookupMacroSeries returns the catalog entry of a series.
+func LookupMacroSeries(series MacroSeries) (MacroSeriesInfo, bool) {
+	for _, info := range MacroSeriesCatalog() {
+		if info.Series == series {
+			return info, true
+		}
+	}
+	return MacroSeriesInfo{}, false
+}
+
+type MacroPoint struct {
+	Series MacroSeries `json:"series"`
+	Date   time.Time   `json:"date"`
+	Value  float64     `json:"value"`
+}
+
+// KeyRateChanges keeps the days the rate differs from the day before, starting
+// from previous, the rate in force before the first day. Rates must be ordered by date.
+func KeyRateChanges(rates []CBRate, previous *CBRate) []CBRate {
+	var changes []CBRate
+	for _, rate := range rates {
+		if previous != nil && (!rate.Date.After(previous.Date) || rate.Rate == previous.Rate) {
+			continue
+		}
+		changes = append(changes, rate)
+		previous = &rate
+	}
+	return changes
+}
+
+// ParseYieldCurve converts the ISS zero-coupon yield table into points of the
+// OFZ series, dated by the trade date of the curve.
+func ParseYieldCurve(table IssTable) []MacroPoint {
+	points := make([]MacroPoint, 0, len(table.Data))
+	for _, record := range table.Records() {
+		tradeDate, _ := record["tradedate"].(string)
+		tenor, okTenor := record["period"].(float64)
+		value, okValue := record["value"].(float64)
+		if !okTenor || !okValue {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", tradeDate)
+		if err != nil {
+			continue
+		}
+		points = append(points, MacroPoint{Series: OFZSeries(tenor), Date: date, Value: value})
+	}
+	return points
+}
+
+// OFZTenor returns the tenor in years of an OFZ series.
+func OFZTenor(series MacroSeries) (float64, bool) {
+	for _, tenor := range OFZTenors {
+		if OFZSeries(tenor) == series {
+			return tenor, true
+		}
+	}
+	return 0, false
+}
+
+type YieldCurvePoint struct {
+	Tenor float64 `json:"tenor"`
+	Yield float64 `json:"yield"`
+}
+
+// YieldCurve is the zero-coupon OFZ curve of a trade date, yields in percent.
+// Tenor and Yield are set when a single tenor was asked for.
+type YieldCurve struct {
+	Date   time.Time         `json:"date"`
+	Points []YieldCurvePoint `json:"points"`
+	Tenor  *float64          `json:"tenor,omitempty"`
+	Yield  *float64          `json:"yield,omitempty"`
+}
+
+// NewYieldCurve builds the curve from points of the OFZ series of one date.
+func NewYieldCurve(points []MacroPoint) *YieldCurve {
+	curve := &YieldCurve{Points: []YieldCurvePoint{}}
+	for _, p := range points {
+		tenor, ok := OFZTenor(p.Series)
+		if !ok {
+			continue
+		}
+		curve.Date = p.Date
+		curve.Points = append(curve.Points, YieldCurvePoint{Tenor: tenor, Yield: p.Value})
+	}
+	sort.Slice(curve.Points, func(i, j int) bool { return curve.Points[i].Tenor < curve.Points[j].Tenor })
+	return curve
+}
+
+// YieldAt interpolates the yield linearly between the published tenors and
+// keeps it flat beyond the shortest and the longest one.
+func (c *YieldCurve) YieldAt(tenor float64) (float64, bool) {
+	if len(c.Points) == 0 {
+		return 0, false
+	}
+	if tenor <= c.Points[0].Tenor {
+		return c.Points[0].Yield, true
+	}
+	for i := 1; i < len(c.Points); i++ {
+		left, right := c.Points[i-1], c.Points[i]
+		if tenor <= right.Tenor {
+			return left.Yield + (right.Yield-left.Yield)*(tenor-left.Tenor)/(right.Tenor-left.Tenor), true
+		}
+	}
+	return c.Points[len(c.Points)-1].Yield, true
+}
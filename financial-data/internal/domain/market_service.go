@@ -1,10 +1,25 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type MarketService interface {
-	GetMarketCap(ticker string) (float64, error)
+	GetMarketCap(ctx context.Context, ticker string) (float64, error)
 	GetStockInfo(ticker string) (*StockInfo, error)
 	GetStockPrice(ticker string, daysBackwards int, interval Period) ([]Candle, error)
 	GetPriceAt(ticker string, date time.Time) (float64, error)
+	GetStockPriceRange(ticker string, from, till time.Time, interval Period) ([]Candle, error)
+	GetIndexRange(index string, from, till time.Time, interval Period) ([]Candle, error)
+	GetDividends(ticker string) ([]Dividends, error)
+}
+
+// MacroProvider loads macroeconomic series from their publishers.
+type MacroProvider interface {
+	// GetKeyRates returns the key rate of every day in [from, till], earliest first.
+	GetKeyRates(from, till time.Time) ([]CBRate, error)
+	GetSeries(series MacroSeries, from, till time.Time) ([]MacroPoint, error)
+	// GetYieldCurve returns the OFZ curve of the last trade date on or before date.
+	GetYieldCurve(date time.Time) ([]MacroPoint, error)
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "strings"
+
 type CandlesApiResponse struct {
 	Candles Candles `json:"candles"`
 }
@@ -18,9 +20,47 @@ type ColumnMetadata struct {
 
 type StockInfoApiResponse struct {
 	Description Description `json:"description"`
+	Boards      IssTable    `json:"boards"`
+}
+
+type BoardSecuritiesApiResponse struct {
+	Securities IssTable `json:"securities"`
+}
+
+type FreeFloatApiResponse struct {
+	FreeFloat IssTable `json:"freefloat"`
 }
 
 type Description struct {
 	Columns []string   `json:"columns"`
 	Data    [][]string `json:"data"`
 }
+
+type DividendsApiResponse struct {
+	Dividends IssTable `json:"dividends"`
+}
+
+type YieldCurveApiResponse struct {
+	YearYields IssTable `json:"yearyields"`
+}
+
+// IssTable is an ISS block with rows of mixed types, read by column name.
+type IssTable struct {
+	Columns []string `json:"columns"`
+	Data    [][]any  `json:"data"`
+}
+
+// Records returns the rows keyed by lower-case column name.
+func (t IssTable) Records() []map[string]any {
+	records := make([]map[string]any, 0, len(t.Data))
+	for _, row := range t.Data {
+		record := make(map[string]any, len(t.Columns))
+		for i, column := range t.Columns {
+			if i < len(row) {
+				record[strings.ToLower(column)] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
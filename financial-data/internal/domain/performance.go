@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Performance is the price return of a security or an index, in percent, from
+// the first close within a window to the last one.
+type Performance struct {
+	Code       string    `json:"code"`
+	From       time.Time `json:"from"`
+	Till       time.Time `json:"till"`
+	StartClose float64   `json:"startClose"`
+	EndClose   float64   `json:"endClose"`
+	Return     float64   `json:"return"`
+}
+
+// NewPerformance measures the return over candles ordered by begin.
+func NewPerformance(code string, candles []Candle) (*Performance, error) {
+	var first, last *Candle
+	for i := range candles {
+		if candles[i].Close <= 0 {
+			continue
+		}
+		if first == nil {
+			first = &candles[i]
+		}
+		last = &candles[i]
+	}
+	if first == nil {
+		return nil, fmt.Errorf("no closes of %s in the window: %w", code, ErrNotFound)
+	}
+
+	from, err := time.Parse("2006-01-02", candleDay(*first))
+	if err != nil {
+		return nil, fmt.Errorf("invalid candle begin %q: %w", first.Begin, err)
+	}
+	till, err := time.Parse("2006-01-02", candleDay(*last))
+	if err != nil {
+		return nil, fmt.Errorf("invalid candle begin %q: %w", last.Begin, err)
+	}
+
+	return &Performance{
+		Code:       code,
+		From:       from,
+		Till:       till,
+		StartClose: first.Close,
+		EndClose:   last.Close,
+		Return:     (last.Close/first.Close - 1) * 100,
+	}, nil
+}
+
+// PerformancePoint holds values rebased to 100 on the first day of the window.
+type PerformancePoint struct {
+	Date   time.Time `json:"date"`
+	Stock  float64   `json:"stock"`
+	Sector *float64  `json:"sector,omitempty"`
+	Market *float64  `json:"market,omitempty"`
+}
+
+// PerformanceComparison sets the price return of a share against its sector
+// index and the broad market over the same window. The share's return is
+// adjusted for splits; TotalReturn adds reinvested dividends. Excess returns
+// are differences of price returns in percentage points, as the indices are
+// price indices as well.
+type PerformanceComparison struct {
+	Ticker         string             `json:"ticker"`
+	From           time.Time          `json:"from"`
+	Till           time.Time          `json:"till"`
+	Stock          Performance        `json:"stock"`
+	TotalReturn    float64            `json:"totalReturn"`
+	Sector         *Performance       `json:"sector,omitempty"`
+	Market         *Performance       `json:"market,omitempty"`
+	ExcessVsSector *float64           `json:"excessVsSector,omitempty"`
+	ExcessVsMarket *float64           `json:"excessVsMarket,omitempty"`
+	Series         []PerformancePoint `json:"series"`
+}
+
+// ComparePerformance compares share closes adjusted for splits to the candles
+// of the sector index and the market, all cut to the window the share traded
+// in. A benchmark without candles in the window is left out. TotalReturn is up
+// to the caller.
+func ComparePerformance(ticker string, stock []Candle, sectorCode string, sector []Candle, marketCode string, market []Candle) (*PerformanceComparison, error) {
+	stockPerformance, err := NewPerformance(ticker, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &PerformanceComparison{
+		Ticker: ticker,
+		From:   stockPerformance.From,
+		Till:   stockPerformance.Till,
+		Stock:  *stockPerformance,
+		Series: []PerformancePoint{},
+	}
+
+	// a share listed after the start of the window is compared from its first close
+	sector = candlesWithin(sector, stockPerformance.From, stockPerformance.Till)
+	market = candlesWithin(market, stockPerformance.From, stockPerformance.Till)
+	if p, err := NewPerformance(sectorCode, sector); err == nil {
+		excess := stockPerformance.Return - p.Return
+		comparison.Sector, comparison.ExcessVsSector = p, &excess
+	}
+	if p, err := NewPerformance(marketCode, market); err == nil {
+		excess := stockPerformance.Return - p.Return
+		comparison.Market, comparison.ExcessVsMarket = p, &excess
+	}
+
+	sectorCloses, marketCloses := closesByDay(sector), closesByDay(market)
+	var sectorBase, marketBase, sectorLast, marketLast float64
+	for _, c := range stock {
+		if c.Close <= 0 {
+			continue
+		}
+		day := candleDay(c)
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+
+		// a benchmark that did not trade that day keeps its last close
+		if v, ok := sectorCloses[day]; ok {
+			sectorLast = v
+		}
+		if v, ok := marketCloses[day]; ok {
+			marketLast = v
+		}
+
+		point := PerformancePoint{Date: date, Stock: c.Close / stockPerformance.StartClose * 100}
+		if sectorLast > 0 {
+			if sectorBase == 0 {
+				sectorBase = sectorLast
+			}
+			v := sectorLast / sectorBase * 100
+			point.Sector = &v
+		}
+		if marketLast > 0 {
+			if marketBase == 0 {
+				marketBase = marketLast
+			}
+			v := marketLast / marketBase * 100
+			point.Market = &v
+		}
+		comparison.Series = append(comparison.Series, point)
+	}
+
+	return comparison, nil
+}
+
+func closesByDay(candles []Candle) map[string]float64 {
+	closes := make(map[string]float64, len(candles))
+	for _, c := range candles {
+		if c.Close > 0 {
+			closes[candleDay(c)] = c.Close
+		}
+	}
+	return closes
+}
+
+// candlesWithin returns the candles whose day falls in [from, till].
+func candlesWithin(candles []Candle, from, till time.Time) []Candle {
+	first, last := from.Format("2006-01-02"), till.Format("2006-01-02")
+	within := make([]Candle, 0, len(candles))
+	for _, c := range candles {
+		if day := candleDay(c); day >= first && day <= last {
+			within = append(within, c)
+		}
+	}
+	return within
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComparePerformance(t *testing.T) {
+	candle := func(day string, close float64) Candle {
+		return Candle{Begin: day + " 00:00:00", Open: close, High: close, Low: close, Close: close}
+	}
+	index := []Candle{
+		candle("2025-01-06", 100), candle("2025-01-07", 200), candle("2025-01-08", 220),
+		candle("2025-01-09", 242), candle("2025-01-10", 300),
+	}
+
+	tests := []struct {
+		name           string
+		stock          []Candle
+		expectedStock  float64
+		expectedSector float64
+		expectedExcess float64
+	}{
+		{
+			name:           "same window",
+			stock:          []Candle{candle("2025-01-06", 50), candle("2025-01-08", 60), candle("2025-01-10", 75)},
+			expectedStock:  50,
+			expectedSector: 200,
+			expectedExcess: -150,
+		},
+		{
+			name:           "stock listed after the index",
+			stock:          []Candle{candle("2025-01-08", 50), candle("2025-01-09", 55)},
+			expectedStock:  10,
+			expectedSector: 10,
+			expectedExcess: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComparePerformance("TEST", tt.stock, "SECT", index, "MKT", index)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(got.Stock.Return-tt.expectedStock) > 1e-9 {
+				t.Errorf("Expected stock return %v, got %v", tt.expectedStock, got.Stock.Return)
+			}
+			for _, benchmark := range []*Performance{got.Sector, got.Market} {
+				if benchmark == nil {
+					t.Fatal("Expected benchmark performance, got nil")
+				}
+				if !benchmark.From.Equal(got.From) || !benchmark.Till.Equal(got.Till) {
+					t.Errorf("Expected %s window %s..%s, got %s..%s", benchmark.Code,
+						got.From.Format("2006-01-02"), got.Till.Format("2006-01-02"),
+						benchmark.From.Format("2006-01-02"), benchmark.Till.Format("2006-01-02"))
+				}
+				if math.Abs(benchmark.Return-tt.expectedSector) > 1e-9 {
+					t.Errorf("Expected %s return %v, got %v", benchmark.Code, tt.expectedSector, benchmark.Return)
+				}
+			}
+			if got.ExcessVsSector == nil || math.Abs(*got.ExcessVsSector-tt.expectedExcess) > 1e-9 {
+				t.Errorf("Expected excess vs sector %v, got %v", tt.expectedExcess, got.ExcessVsSector)
+			}
+			if first := got.Series[0]; first.Sector == nil || *first.Sector != 100 {
+				t.Errorf("Expected sector rebased to 100 on the first day, got %v", first.Sector)
+			}
+		})
+	}
+}
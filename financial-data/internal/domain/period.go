@@ -7,3 +7,12 @@ const (
 	Period1D Period = 24 // 1 день
 	Period1W Period = 7  // 1 неделя
 )
+
+func (p Period) IsValid() bool {
+	switch p {
+	case Period1H, Period1D, Period1W:
+		return true
+	default:
+		return false
+	}
+}
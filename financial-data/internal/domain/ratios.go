@@ -5,6 +5,9 @@ type Ratios struct {
 	Year   int          `json:"year,omitempty"`
 	Period ReportPeriod `json:"period,omitempty"`
 
+	// Для периода TTM - последний отчёт, вошедший в расчёт (Q1, Q2, Q3 или YEAR)
+	TTMThrough *ReportPeriod `json:"ttmThrough,omitempty"`
+
 	PriceToEarnings *float64 `json:"priceToEarnings,omitempty"`
 
 	// P/BV - Отношение цены к балансовой стоимости (Price to Book Value)
@@ -164,4 +167,26 @@ type Ratios struct {
 
 	// FCF Growth - Рост свободного денежного потока
 	FCFGrowth *float64 `json:"fcfGrowth,omitempty"`
+
+	// Тип компании из отчётности ("bank" или "corporate"). Для банков вместо
+	// показателей на основе EV, EBITDA, оборотного капитала и денежных потоков
+	// считаются банковские показатели ниже
+	CompanyType *string `json:"companyType,omitempty"`
+
+	// Банковские показатели (Bank Ratios) - в процентах, годовые
+	// NIM - Чистая процентная маржа (Net Interest Margin)
+	// Формула: Чистые процентные доходы / Активы × 100%
+	NetInterestMargin *float64 `json:"netInterestMargin,omitempty"`
+
+	// Cost of Risk - Стоимость риска
+	// Формула: Резерв под кредитные убытки / Кредиты клиентам × 100%
+	CostOfRisk *float64 `json:"costOfRisk,omitempty"`
+
+	// CIR - Отношение расходов к доходам (Cost to Income)
+	// Формула: Операционные расходы / Операционные доходы до резервов × 100%
+	CostToIncome *float64 `json:"costToIncome,omitempty"`
+
+	// Доля комиссионных доходов (Fee Income Share)
+	// Формула: Чистые комиссионные доходы / Операционные доходы до резервов × 100%
+	FeeIncomeShare *float64 `json:"feeIncomeShare,omitempty"`
 }
@@ -222,5 +222,12 @@ func CalculateRatios(current *RawData, previous *RawData) *Ratios {
 		r.PEG = safeDiv(*r.PriceToEarnings, *r.EarningsGrowth)
 	}
 
+	if current.IsBank() {
+		applyBankRatios(r, current)
+	} else if current.CompanyType != nil {
+		companyType := *current.CompanyType
+		r.CompanyType = &companyType
+	}
+
 	return r
 }
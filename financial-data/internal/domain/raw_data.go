@@ -1,5 +1,7 @@
 package domain
 
+import "math"
+
 type ReportPeriod string
 
 const (
@@ -120,3 +122,20 @@ type RawData struct {
 	NetCommissionIncome  *int64  `json:"netCommissionIncome,omitempty"`
 	CreditLossProvision  *int64  `json:"creditLossProvision,omitempty"`
 }
+
+// WithSharesOutstanding returns a copy with the given share count. MarketCap and
+// EnterpriseValue are rescaled in proportion, as they were derived from the
+// previously stored count and the price at period end.
+func (r RawData) WithSharesOutstanding(shares int64) *RawData {
+	if r.SharesOutstanding != nil && *r.SharesOutstanding != 0 && r.MarketCap != nil {
+		oldCap := *r.MarketCap
+		newCap := int64(math.Round(float64(oldCap) * float64(shares) / float64(*r.SharesOutstanding)))
+		r.MarketCap = &newCap
+		if r.EnterpriseValue != nil {
+			ev := *r.EnterpriseValue + newCap - oldCap
+			r.EnterpriseValue = &ev
+		}
+	}
+	r.SharesOutstanding = &shares
+	return &r
+}
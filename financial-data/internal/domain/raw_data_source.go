@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type SourceKind string
+
+const (
+	// SourceOriginal figures come from the report of the period itself.
+	SourceOriginal SourceKind = "original"
+	// SourceRestated figures come from a later report, usually as comparatives
+	// of the next annual report.
+	SourceRestated SourceKind = "restated"
+)
+
+// RawDataSource is a report of a period as published in one source report.
+// The canonical source of a period is the one stored in metrics and used for
+// ratios, including growth of the following period.
+type RawDataSource struct {
+	ID           int          `json:"id"`
+	Ticker       string       `json:"ticker"`
+	Year         int          `json:"year"`
+	Period       ReportPeriod `json:"period"`
+	Kind         SourceKind   `json:"kind"`
+	SourceYear   int          `json:"sourceYear"`
+	SourcePeriod ReportPeriod `json:"sourcePeriod"`
+	SourceURL    *string      `json:"sourceUrl,omitempty"`
+	Canonical    bool         `json:"canonical"`
+	Data         RawData      `json:"data"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
+
+// SetKind derives Kind from the source report: figures published in the report
+// of the period itself are original, anything published later is restated.
+func (s *RawDataSource) SetKind() {
+	s.Kind = SourceRestated
+	if s.SourceYear == s.Year && s.SourcePeriod == s.Period {
+		s.Kind = SourceOriginal
+	}
+}
+
+// Validate checks that the source report can contain figures of the period.
+func (s *RawDataSource) Validate() error {
+	if !s.SourcePeriod.IsValid() {
+		return fmt.Errorf("invalid source period: %s: %w", s.SourcePeriod, ErrInvalidInput)
+	}
+	if PeriodEndDate(s.SourceYear, s.SourcePeriod).Before(PeriodEndDate(s.Year, s.Period)) {
+		return fmt.Errorf("source report %d %s is earlier than the period %d %s: %w",
+			s.SourceYear, s.SourcePeriod, s.Year, s.Period, ErrInvalidInput)
+	}
+	return nil
+}
+
+// RestatementDelta is the change of a numeric field between the original and
+// a restated report, in the units of the original one.
+type RestatementDelta struct {
+	Field    string   `json:"field"`
+	Original *float64 `json:"original"`
+	Restated *float64 `json:"restated"`
+	Delta    *float64 `json:"delta,omitempty"`
+	DeltaPct *float64 `json:"deltaPct,omitempty"`
+}
+
+type Restatement struct {
+	Ticker       string             `json:"ticker"`
+	Year         int                `json:"year"`
+	Period       ReportPeriod       `json:"period"`
+	SourceID     int                `json:"sourceId"`
+	SourceYear   int                `json:"sourceYear"`
+	SourcePeriod ReportPeriod       `json:"sourcePeriod"`
+	SourceURL    *string            `json:"sourceUrl,omitempty"`
+	Canonical    bool               `json:"canonical"`
+	Deltas       []RestatementDelta `json:"deltas"`
+}
+
+// Restatements compares every restated source with the original one of the
+// same period. Periods without an original source are skipped.
+func Restatements(sources []RawDataSource) []Restatement {
+	originals := make(map[string]*RawDataSource)
+	for i := range sources {
+		if sources[i].Kind == SourceOriginal {
+			originals[fmt.Sprintf("%d-%s", sources[i].Year, sources[i].Period)] = &sources[i]
+		}
+	}
+
+	result := []Restatement{}
+	for _, s := range sources {
+		if s.Kind != SourceRestated {
+			continue
+		}
+		original, ok := originals[fmt.Sprintf("%d-%s", s.Year, s.Period)]
+		if !ok {
+			continue
+		}
+		result = append(result, Restatement{
+			Ticker:       s.Ticker,
+			Year:         s.Year,
+			Period:       s.Period,
+			SourceID:     s.ID,
+			SourceYear:   s.SourceYear,
+			SourcePeriod: s.SourcePeriod,
+			SourceURL:    s.SourceURL,
+			Canonical:    s.Canonical,
+			Deltas:       RestatementDeltas(&original.Data, &s.Data),
+		})
+	}
+	return result
+}
+
+// RestatementDeltas lists numeric fields that differ between two reports of
+// the same period. Restated values are converted to the units of the original
+// report when both are known.
+func RestatementDeltas(original, restated *RawData) []RestatementDelta {
+	scale := 1.0
+	from, okFrom := reportUnitsMultiplier(restated.ReportUnits)
+	to, okTo := reportUnitsMultiplier(original.ReportUnits)
+	if okFrom && okTo {
+		scale = from / to
+	}
+
+	deltas := []RestatementDelta{}
+	for _, change := range DiffRawData(original, restated) {
+		oldValue, oldNumeric := numericValue(change.OldValue)
+		newValue, newNumeric := numericValue(change.NewValue)
+		if !oldNumeric || !newNumeric {
+			continue
+		}
+		if newValue != nil && change.Field != "basicEps" && change.Field != "sharesOutstanding" {
+			newValue = ptr(*newValue * scale)
+		}
+		if oldValue != nil && newValue != nil && *oldValue == *newValue {
+			continue
+		}
+
+		d := RestatementDelta{Field: change.Field, Original: oldValue, Restated: newValue}
+		if oldValue != nil && newValue != nil {
+			d.Delta = ptr(*newValue - *oldValue)
+			if *oldValue != 0 {
+				d.DeltaPct = ptr((*newValue - *oldValue) / math.Abs(*oldValue) * 100)
+			}
+		}
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// numericValue converts a FieldChange value; ok is false for non-numeric fields.
+func numericValue(v any) (value *float64, ok bool) {
+	switch n := v.(type) {
+	case nil:
+		return nil, true
+	case int64:
+		return ptr(float64(n)), true
+	case float64:
+		return ptr(n), true
+	default:
+		return nil, false
+	}
+}
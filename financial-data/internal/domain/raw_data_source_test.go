@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRestatementDeltas(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		original RawData
+		restated RawData
+		expected []RestatementDelta
+	}{
+		{
+			name:     "changed field",
+			original: RawData{ReportUnits: units("millions"), Revenue: i64(100), NetProfit: i64(20)},
+			restated: RawData{ReportUnits: units("millions"), Revenue: i64(110), NetProfit: i64(20)},
+			expected: []RestatementDelta{{Field: "revenue", Original: f(100), Restated: f(110), Delta: f(10), DeltaPct: f(10)}},
+		},
+		{
+			name:     "restated in other units",
+			original: RawData{ReportUnits: units("millions"), Revenue: i64(100), NetProfit: i64(-20)},
+			restated: RawData{ReportUnits: units("thousands"), Revenue: i64(100_000), NetProfit: i64(-25_000)},
+			expected: []RestatementDelta{{Field: "netProfit", Original: f(-20), Restated: f(-25), Delta: f(-5), DeltaPct: f(-25)}},
+		},
+		{
+			name:     "per share fields are not scaled",
+			original: RawData{ReportUnits: units("millions"), BasicEPS: f(10)},
+			restated: RawData{ReportUnits: units("thousands"), BasicEPS: f(10)},
+			expected: []RestatementDelta{},
+		},
+		{
+			name:     "field added and removed",
+			original: RawData{Revenue: i64(100)},
+			restated: RawData{NetProfit: i64(20)},
+			expected: []RestatementDelta{
+				{Field: "revenue", Original: f(100)},
+				{Field: "netProfit", Restated: f(20)},
+			},
+		},
+		{
+			name:     "change from zero",
+			original: RawData{Revenue: i64(0)},
+			restated: RawData{Revenue: i64(50)},
+			expected: []RestatementDelta{{Field: "revenue", Original: f(0), Restated: f(50), Delta: f(50)}},
+		},
+		{
+			name:     "non-numeric fields are skipped",
+			original: RawData{Status: StatusDraft, ReportUnits: units("millions"), Revenue: i64(100)},
+			restated: RawData{Status: StatusConfirmed, ReportUnits: units("millions"), Revenue: i64(100)},
+			expected: []RestatementDelta{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RestatementDeltas(&tt.original, &tt.restated)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %d deltas, got %d: %+v", len(tt.expected), len(got), got)
+			}
+			for i, d := range got {
+				e := tt.expected[i]
+				if d.Field != e.Field || !sameValue(d.Original, e.Original) || !sameValue(d.Restated, e.Restated) ||
+					!sameValue(d.Delta, e.Delta) || !sameValue(d.DeltaPct, e.DeltaPct) {
+					t.Errorf("Delta %d: expected %s, got %s", i, formatDelta(e), formatDelta(d))
+				}
+			}
+		})
+	}
+}
+
+func TestRestatements(t *testing.T) {
+	source := func(id, year int, period ReportPeriod, sourceYear int, revenue int64) RawDataSource {
+		s := RawDataSource{
+			ID: id, Ticker: "SBER", Year: year, Period: period, SourceYear: sourceYear, SourcePeriod: YEAR,
+			Data: RawData{ReportUnits: units("millions"), Revenue: i64(revenue)},
+		}
+		s.SetKind()
+		return s
+	}
+
+	sources := []RawDataSource{
+		source(1, 2023, YEAR, 2023, 100),
+		source(2, 2023, YEAR, 2024, 120),
+		source(3, 2023, YEAR, 2025, 100),
+		// restated without an original
+		source(4, 2022, YEAR, 2023, 90),
+		// original only
+		source(5, 2024, YEAR, 2024, 130),
+	}
+	sources[1].Canonical = true
+
+	got := Restatements(sources)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 restatements, got %d: %+v", len(got), got)
+	}
+
+	first, second := got[0], got[1]
+	if first.SourceID != 2 || first.Year != 2023 || first.SourceYear != 2024 || !first.Canonical {
+		t.Errorf("Unexpected first restatement: %+v", first)
+	}
+	if len(first.Deltas) != 1 || first.Deltas[0].Field != "revenue" || !sameValue(first.Deltas[0].Delta, ptr(20)) {
+		t.Errorf("Expected revenue restated by 20, got %+v", first.Deltas)
+	}
+	if second.SourceID != 3 || second.Canonical || len(second.Deltas) != 0 {
+		t.Errorf("Expected a restatement without deltas from source 3, got %+v", second)
+	}
+}
+
+func sameValue(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return math.Abs(*a-*b) < 1e-9
+}
+
+func formatDelta(d RestatementDelta) string {
+	value := func(v *float64) any {
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return fmt.Sprintf("%s %v -> %v (%v, %v%%)", d.Field, value(d.Original), value(d.Restated), value(d.Delta), value(d.DeltaPct))
+}
@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type RawDataFileFormat string
+
+const (
+	FormatCSV  RawDataFileFormat = "csv"
+	FormatXLSX RawDataFileFormat = "xlsx"
+	FormatJSON RawDataFileFormat = "json"
+)
+
+func (f RawDataFileFormat) IsValid() bool {
+	switch f {
+	case FormatCSV, FormatXLSX, FormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f RawDataFileFormat) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/json"
+	}
+}
+
+// RawDataColumns are the columns of raw data files: JSON names of RawData
+// fields in declaration order, so a file header matches the API field names.
+var RawDataColumns = rawDataColumns()
+
+func rawDataColumns() []string {
+	t := reflect.TypeOf(RawData{})
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+// rawDataField returns the field of r stored under a column, matched case-insensitively.
+func rawDataField(r *RawData, column string) (reflect.Value, bool) {
+	v := reflect.ValueOf(r).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if strings.EqualFold(name, column) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// RawDataRecord formats r as a row of RawDataColumns; empty values are empty strings.
+func RawDataRecord(r *RawData) []string {
+	record := make([]string, len(RawDataColumns))
+	for i, column := range RawDataColumns {
+		field, _ := rawDataField(r, column)
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		switch field.Kind() {
+		case reflect.Int, reflect.Int64:
+			record[i] = strconv.FormatInt(field.Int(), 10)
+		case reflect.Float64:
+			record[i] = strconv.FormatFloat(field.Float(), 'f', -1, 64)
+		default:
+			record[i] = field.String()
+		}
+	}
+	return record
+}
+
+// CheckRawDataHeader makes sure every column of a file header is known and the
+// columns identifying a report are present.
+func CheckRawDataHeader(header []string) error {
+	var unknown []string
+	seen := make(map[string]bool, len(header))
+	for _, column := range header {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		if _, ok := rawDataField(&RawData{}, column); !ok {
+			unknown = append(unknown, column)
+			continue
+		}
+		seen[strings.ToLower(column)] = true
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown columns: %s: %w", strings.Join(unknown, ", "), ErrInvalidInput)
+	}
+	for _, required := range []string{"ticker", "year", "period"} {
+		if !seen[required] {
+			return fmt.Errorf("column %s is required: %w", required, ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// ParseRawDataRecord reads a row of a file with the given header. Empty cells
+// are left unset. Numbers may use spaces between digit groups; integer fields
+// accept whole numbers written in float notation. With decimalComma a comma is
+// the decimal separator, otherwise a number with a comma is rejected, since
+// 1,234 may mean either a thousand or a fraction.
+func ParseRawDataRecord(header, record []string, decimalComma bool) (*RawData, error) {
+	var r RawData
+	var errs []error
+	for i, column := range header {
+		if i >= len(record) {
+			break
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+		field, ok := rawDataField(&r, strings.TrimSpace(column))
+		if !ok {
+			continue
+		}
+		if err := setRawDataField(field, value, decimalComma); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", column, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	r.Ticker = strings.ToUpper(r.Ticker)
+	return &r, nil
+}
+
+func setRawDataField(field reflect.Value, value string, decimalComma bool) error {
+	target := field
+	if field.Kind() == reflect.Pointer {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int64:
+		n, err := parseWholeNumber(value, decimalComma)
+		if err != nil {
+			return err
+		}
+		target.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(normalizeNumber(value, decimalComma), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		target.SetFloat(f)
+	default:
+		target.SetString(value)
+	}
+
+	if field.Kind() == reflect.Pointer {
+		field.Set(target.Addr())
+	}
+	return nil
+}
+
+func parseWholeNumber(value string, decimalComma bool) (int64, error) {
+	s := normalizeNumber(value, decimalComma)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	// spreadsheets may store large numbers as 1.5E+10
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid whole number %q", value)
+	}
+	return int64(f), nil
+}
+
+// normalizeNumber drops digit group spaces and, with decimalComma, turns the
+// decimal comma into a point. A number with both a comma and a point is left
+// as is to fail parsing: the point may be a group separator.
+func normalizeNumber(value string, decimalComma bool) string {
+	s := strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(value)
+	if !decimalComma || strings.Contains(s, ".") {
+		return s
+	}
+	return strings.Replace(s, ",", ".", 1)
+}
+
+// RawDataFileRow is a decoded row of an imported file: either the report or
+// the reason it could not be read. Row counts data rows from 1.
+type RawDataFileRow struct {
+	Row  int
+	Data *RawData
+	Err  error
+}
+
+type ImportOutcome string
+
+const (
+	ImportCreated  ImportOutcome = "created"
+	ImportUpdated  ImportOutcome = "updated"
+	ImportRejected ImportOutcome = "rejected"
+)
+
+type RawDataImportRow struct {
+	Row          int               `json:"row"`
+	Ticker       string            `json:"ticker,omitempty"`
+	Year         int               `json:"year,omitempty"`
+	Period       ReportPeriod      `json:"period,omitempty"`
+	Outcome      ImportOutcome     `json:"outcome"`
+	ReportStatus MetricsStatus     `json:"reportStatus,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	Validation   *ValidationReport `json:"validation,omitempty"`
+}
+
+// RawDataImportResult reports what happened to every row. In a dry run
+// nothing is saved and outcomes describe what an import would do.
+type RawDataImportResult struct {
+	DryRun   bool               `json:"dryRun"`
+	Total    int                `json:"total"`
+	Created  int                `json:"created"`
+	Updated  int                `json:"updated"`
+	Rejected int                `json:"rejected"`
+	Rows     []RawDataImportRow `json:"rows"`
+}
+
+func (r *RawDataImportResult) Add(row RawDataImportRow) {
+	r.Total++
+	switch row.Outcome {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportRejected:
+		r.Rejected++
+	}
+	r.Rows = append(r.Rows, row)
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRawDataRecordRoundTrip(t *testing.T) {
+	eps := 3.25
+	report := RawData{
+		Ticker:      "SBER",
+		Year:        2024,
+		Period:      Q3,
+		Status:      StatusConfirmed,
+		ReportUnits: units("millions"),
+		Revenue:     i64(1_500_000),
+		NetProfit:   i64(-20_000),
+		BasicEPS:    &eps,
+	}
+
+	parsed, err := ParseRawDataRecord(RawDataColumns, RawDataRecord(&report), false)
+	if err != nil {
+		t.Fatalf("Failed to parse record: %v", err)
+	}
+	if !reflect.DeepEqual(*parsed, report) {
+		t.Errorf("Expected %+v, got %+v", report, *parsed)
+	}
+}
+
+func TestParseRawDataRecordNumbers(t *testing.T) {
+	header := []string{"ticker", "year", "period", "revenue", "basicEps"}
+
+	tests := []struct {
+		name         string
+		revenue      string
+		eps          string
+		decimalComma bool
+		expectedRev  int64
+		expectedEPS  float64
+		wantErr      bool
+	}{
+		{name: "plain numbers", revenue: "1234", eps: "1.5", expectedRev: 1234, expectedEPS: 1.5},
+		{name: "group spaces", revenue: "1 234 567", eps: "0.25", expectedRev: 1_234_567, expectedEPS: 0.25},
+		{name: "non-breaking group spaces", revenue: "1\u00a0234", eps: "2", expectedRev: 1234, expectedEPS: 2},
+		{name: "float notation of a whole number", revenue: "1.5E+10", eps: "1", expectedRev: 15_000_000_000, expectedEPS: 1},
+		{name: "decimal comma", revenue: "1 234", eps: "1,5", decimalComma: true, expectedRev: 1234, expectedEPS: 1.5},
+		{name: "comma without decimal comma", revenue: "1,234", eps: "1", wantErr: true},
+		{name: "comma in a fraction without decimal comma", revenue: "1", eps: "1,5", wantErr: true},
+		{name: "comma and point", revenue: "1", eps: "1.234,5", decimalComma: true, wantErr: true},
+		{name: "fraction in a whole number", revenue: "1,5", eps: "1", decimalComma: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRawDataRecord(header, []string{"sber", "2024", "YEAR", tt.revenue, tt.eps}, tt.decimalComma)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got revenue %v and EPS %v", r.Revenue, r.BasicEPS)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if r.Ticker != "SBER" {
+				t.Errorf("Expected ticker SBER, got %s", r.Ticker)
+			}
+			if r.Revenue == nil || *r.Revenue != tt.expectedRev {
+				t.Errorf("Expected revenue %d, got %v", tt.expectedRev, r.Revenue)
+			}
+			if r.BasicEPS == nil || *r.BasicEPS != tt.expectedEPS {
+				t.Errorf("Expected EPS %v, got %v", tt.expectedEPS, r.BasicEPS)
+			}
+		})
+	}
+}
+
+func TestCheckRawDataHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []string
+		wantErr bool
+	}{
+		{name: "all columns", header: RawDataColumns},
+		{name: "required columns in another case", header: []string{" Ticker ", "YEAR", "period", "revenue"}},
+		{name: "empty columns are ignored", header: []string{"ticker", "", "year", "period"}},
+		{name: "unknown column", header: []string{"ticker", "year", "period", "turnover"}, wantErr: true},
+		{name: "missing period", header: []string{"ticker", "year", "revenue"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRawDataHeader(tt.header)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("Expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
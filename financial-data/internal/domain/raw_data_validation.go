@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+type ValidationSeverity string
+
+const (
+	// SeverityError blocks confirmation of the report.
+	SeverityError   ValidationSeverity = "error"
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+type ValidationIssue struct {
+	Code     string             `json:"code"`
+	Severity ValidationSeverity `json:"severity"`
+	Field    string             `json:"field,omitempty"`
+	Message  string             `json:"message"`
+	Expected *float64           `json:"expected,omitempty"`
+	Actual   *float64           `json:"actual,omitempty"`
+}
+
+type ValidationReport struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+func (r *ValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyStatus sets the status a checked report is saved with: confirmed unless
+// given otherwise, and a draft when it has errors, so that it goes to manual
+// review instead of the ratios. It reports whether the report was downgraded.
+func (r *ValidationReport) ApplyStatus(rawData *RawData) (downgraded bool) {
+	if !rawData.Status.IsValid() {
+		rawData.Status = StatusConfirmed
+	}
+	if r.HasErrors() && rawData.Status == StatusConfirmed {
+		rawData.Status = StatusDraft
+		return true
+	}
+	return false
+}
+
+func (r *ValidationReport) add(code string, severity ValidationSeverity, field, message string, expected, actual *float64) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Code: code, Severity: severity, Field: field, Message: message, Expected: expected, Actual: actual,
+	})
+}
+
+// Relative deviations of accounting identities: up to identityWarnLevel the data
+// is fine (rounding, minor items), above identityErrorLevel it is most likely
+// mis-extracted.
+const (
+	identityWarnLevel  = 0.01
+	identityErrorLevel = 0.05
+)
+
+// unitMultipliers converts values stored in ReportUnits into roubles.
+var unitMultipliers = map[string]float64{
+	"units":     1,
+	"thousands": 1e3,
+	"millions":  1e6,
+	"billions":  1e9,
+}
+
+// ValidateRawData checks a report for accounting identities, sign conventions
+// and unit consistency with history (other reports of the same company, any order).
+func ValidateRawData(current *RawData, history []RawData) *ValidationReport {
+	report := &ValidationReport{Issues: []ValidationIssue{}}
+
+	checkIdentity(report, "balance_identity", "totalAssets", "total assets = total liabilities + equity",
+		current.TotalAssets, sumOf(current.TotalLiabilities, current.Equity))
+
+	if !current.IsBank() {
+		checkIdentity(report, "gross_profit", "grossProfit", "gross profit = revenue - cost of revenue",
+			current.GrossProfit, diffOf(current.Revenue, current.CostOfRevenue))
+	}
+
+	checkIdentity(report, "free_cash_flow", "freeCashFlow", "free cash flow = operating cash flow + capex",
+		current.FreeCashFlow, sumOf(current.OperatingCashFlow, current.CAPEX))
+
+	checkSigns(report, current)
+	checkUnits(report, current, history)
+
+	return report
+}
+
+func checkIdentity(report *ValidationReport, code, field, identity string, actual, expected *int64) {
+	if actual == nil || expected == nil {
+		return
+	}
+	a, e := float64(*actual), float64(*expected)
+	base := math.Max(math.Abs(a), math.Abs(e))
+	if base == 0 {
+		return
+	}
+	deviation := math.Abs(a-e) / base
+
+	severity := SeverityWarning
+	switch {
+	case deviation <= identityWarnLevel:
+		return
+	case deviation > identityErrorLevel:
+		severity = SeverityError
+	}
+	report.add(code, severity, field,
+		fmt.Sprintf("%s does not hold: off by %.1f%%", identity, deviation*100), &e, &a)
+}
+
+// signRule describes the sign a field is stored with (see the raw data extractor prompt).
+type signRule struct {
+	field    string
+	value    *int64
+	negative bool
+	severity ValidationSeverity
+}
+
+func checkSigns(report *ValidationReport, r *RawData) {
+	rules := []signRule{
+		// impossible values
+		{"revenue", r.Revenue, false, SeverityError},
+		{"totalAssets", r.TotalAssets, false, SeverityError},
+		{"totalLiabilities", r.TotalLiabilities, false, SeverityError},
+		{"cashAndEquivalents", r.CashAndEquivalents, false, SeverityError},
+		{"inventories", r.Inventories, false, SeverityError},
+		{"receivables", r.Receivables, false, SeverityError},
+		// conventions: expenses stored positive, cash outflows negative
+		{"costOfRevenue", r.CostOfRevenue, false, SeverityWarning},
+		{"operatingExpenses", r.OperatingExpenses, false, SeverityWarning},
+		{"interestExpense", r.InterestExpense, false, SeverityWarning},
+		{"creditLossProvision", r.CreditLossProvision, false, SeverityWarning},
+		{"capex", r.CAPEX, true, SeverityWarning},
+		{"dividendsPaid", r.DividendsPaid, true, SeverityWarning},
+		{"treasuryShares", r.TreasuryShares, true, SeverityWarning},
+		{"debtRepayments", r.DebtRepayments, true, SeverityWarning},
+		{"leasePayments", r.LeasePayments, true, SeverityWarning},
+		{"interestPaid", r.InterestPaid, true, SeverityWarning},
+	}
+
+	for _, rule := range rules {
+		if rule.value == nil || *rule.value == 0 {
+			continue
+		}
+		if rule.negative == (*rule.value < 0) {
+			continue
+		}
+		expected := "positive"
+		if rule.negative {
+			expected = "negative"
+		}
+		actual := float64(*rule.value)
+		report.add("sign", rule.severity, rule.field, fmt.Sprintf("%s is expected to be %s", rule.field, expected), nil, &actual)
+	}
+}
+
+// checkUnits compares the report with the previous one in roubles. A jump by a
+// factor close to a thousand or a million means the numbers and ReportUnits
+// disagree. Total assets are compared with the latest earlier report, revenue
+// with the same period a year before, as Q1–Q3 revenues are cumulative.
+func checkUnits(report *ValidationReport, current *RawData, history []RawData) {
+	multiplier, ok := reportUnitsMultiplier(current.ReportUnits)
+	if !ok {
+		report.add("report_units", SeverityWarning, "reportUnits",
+			"reportUnits is missing or unknown (allowed: units, thousands, millions, billions), unit consistency not checked", nil, nil)
+		return
+	}
+
+	var previous, sameLastYear *RawData
+	for i := range history {
+		h := &history[i]
+		if h.Year == current.Year-1 && h.Period == current.Period {
+			sameLastYear = h
+		}
+		if !periodBefore(h, current) {
+			continue
+		}
+		if previous == nil || periodBefore(previous, h) {
+			previous = h
+		}
+	}
+
+	compareUnits(report, "totalAssets", current, multiplier, previous, func(r *RawData) *int64 { return r.TotalAssets })
+	compareUnits(report, "revenue", current, multiplier, sameLastYear, func(r *RawData) *int64 { return r.Revenue })
+}
+
+func compareUnits(report *ValidationReport, field string, r *RawData, multiplier float64, previous *RawData, get func(*RawData) *int64) {
+	value := get(r)
+	if value == nil || previous == nil || get(previous) == nil || *get(previous) == 0 {
+		return
+	}
+	prevMultiplier, ok := reportUnitsMultiplier(previous.ReportUnits)
+	if !ok {
+		return
+	}
+
+	current := float64(*value) * multiplier
+	prev := float64(*get(previous)) * prevMultiplier
+	ratio := math.Abs(current / prev)
+	if ratio == 0 {
+		return
+	}
+
+	// distance in orders of magnitude from 1, 1000 and 1000000 in either direction
+	magnitude := math.Log10(ratio)
+	steps := math.Round(magnitude / 3)
+	if steps == 0 || math.Abs(magnitude-steps*3) > 0.5 {
+		return
+	}
+
+	report.add("unit_mismatch", SeverityError, field,
+		fmt.Sprintf("%s is %.4gx the value of %d %s (%s) in roubles: reportUnits (%s) probably does not match the numbers",
+			field, ratio, previous.Year, previous.Period, unitsName(previous.ReportUnits), unitsName(r.ReportUnits)),
+		&prev, &current)
+}
+
+func reportUnitsMultiplier(units *string) (float64, bool) {
+	if units == nil {
+		return 0, false
+	}
+	m, ok := unitMultipliers[*units]
+	return m, ok
+}
+
+// InReportUnits converts an amount in roubles into the units of the report.
+func (r *RawData) InReportUnits(roubles float64) (int64, error) {
+	multiplier, ok := reportUnitsMultiplier(r.ReportUnits)
+	if !ok {
+		return 0, fmt.Errorf("report %d %s has unknown units %q: %w", r.Year, r.Period, unitsName(r.ReportUnits), ErrInvalidInput)
+	}
+	return int64(math.Round(roubles / multiplier)), nil
+}
+
+func unitsName(units *string) string {
+	if units == nil {
+		return "unknown"
+	}
+	return *units
+}
+
+// periodBefore reports whether a ends before b.
+func periodBefore(a, b *RawData) bool {
+	return PeriodEndDate(a.Year, a.Period).Before(PeriodEndDate(b.Year, b.Period))
+}
+
+func sumOf(a, b *int64) *int64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	v := *a + *b
+	return &v
+}
+
+func diffOf(a, b *int64) *int64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	v := *a - *b
+	return &v
+}
@@ -0,0 +1,176 @@
+package domain
+
+import "testing"
+
+func units(u string) *string { return &u }
+
+func issueCodes(report *ValidationReport) map[string]ValidationSeverity {
+	codes := make(map[string]ValidationSeverity, len(report.Issues))
+	for _, issue := range report.Issues {
+		codes[issue.Code+":"+issue.Field] = issue.Severity
+	}
+	return codes
+}
+
+func TestValidateRawDataIdentities(t *testing.T) {
+	tests := []struct {
+		name        string
+		liabilities int64
+		equity      int64
+		severity    ValidationSeverity // empty when no issue is expected
+	}{
+		{name: "exact", liabilities: 600, equity: 400},
+		{name: "within 1%", liabilities: 600, equity: 392},
+		{name: "warning between 1% and 5%", liabilities: 600, equity: 370, severity: SeverityWarning},
+		{name: "exactly 5% is a warning", liabilities: 550, equity: 400, severity: SeverityWarning},
+		{name: "error above 5%", liabilities: 500, equity: 400, severity: SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RawData{
+				Year: 2024, Period: YEAR, ReportUnits: units("millions"),
+				TotalAssets: i64(1000), TotalLiabilities: i64(tt.liabilities), Equity: i64(tt.equity),
+			}
+			got, found := issueCodes(ValidateRawData(r, nil))["balance_identity:totalAssets"]
+			if tt.severity == "" {
+				if found {
+					t.Errorf("Expected no balance identity issue, got %s", got)
+				}
+				return
+			}
+			if got != tt.severity {
+				t.Errorf("Expected %s, got %q", tt.severity, got)
+			}
+		})
+	}
+}
+
+func TestValidateRawDataGrossProfitSkippedForBanks(t *testing.T) {
+	r := &RawData{
+		Year: 2024, Period: YEAR, ReportUnits: units("millions"),
+		Revenue: i64(1000), CostOfRevenue: i64(600), GrossProfit: i64(100),
+	}
+	if _, found := issueCodes(ValidateRawData(r, nil))["gross_profit:grossProfit"]; !found {
+		t.Error("Expected gross profit identity to fail")
+	}
+
+	bank := CompanyTypeBank
+	r.CompanyType = &bank
+	if _, found := issueCodes(ValidateRawData(r, nil))["gross_profit:grossProfit"]; found {
+		t.Error("Expected gross profit identity not to be checked for a bank")
+	}
+}
+
+func TestValidateRawDataSigns(t *testing.T) {
+	r := &RawData{
+		Year: 2024, Period: YEAR, ReportUnits: units("millions"),
+		Revenue: i64(-5), CAPEX: i64(100), DividendsPaid: i64(-30), CostOfRevenue: i64(0),
+	}
+	codes := issueCodes(ValidateRawData(r, nil))
+
+	expected := map[string]ValidationSeverity{
+		"sign:revenue": SeverityError,
+		"sign:capex":   SeverityWarning,
+	}
+	for key, severity := range expected {
+		if codes[key] != severity {
+			t.Errorf("Expected %s to be %s, got %q", key, severity, codes[key])
+		}
+	}
+	for _, key := range []string{"sign:dividendsPaid", "sign:costOfRevenue"} {
+		if _, found := codes[key]; found {
+			t.Errorf("Expected no issue for %s", key)
+		}
+	}
+}
+
+func TestValidateRawDataUnits(t *testing.T) {
+	previousYear := RawData{Year: 2023, Period: YEAR, ReportUnits: units("millions"), TotalAssets: i64(1_000_000), Revenue: i64(400_000)}
+	sameQuarterLastYear := RawData{Year: 2023, Period: Q2, ReportUnits: units("millions"), TotalAssets: i64(900_000), Revenue: i64(180_000)}
+	history := []RawData{sameQuarterLastYear, previousYear}
+
+	tests := []struct {
+		name     string
+		current  RawData
+		expected []string
+		missing  []string
+	}{
+		{
+			name:    "consistent growth",
+			current: RawData{Year: 2024, Period: Q2, ReportUnits: units("millions"), TotalAssets: i64(1_100_000), Revenue: i64(200_000)},
+			missing: []string{"unit_mismatch:totalAssets", "unit_mismatch:revenue"},
+		},
+		{
+			name:     "numbers in thousands labelled as millions",
+			current:  RawData{Year: 2024, Period: Q2, ReportUnits: units("millions"), TotalAssets: i64(1_100_000_000), Revenue: i64(200_000_000)},
+			expected: []string{"unit_mismatch:totalAssets", "unit_mismatch:revenue"},
+		},
+		{
+			name:    "same amounts restated in thousands",
+			current: RawData{Year: 2024, Period: Q2, ReportUnits: units("thousands"), TotalAssets: i64(1_100_000_000), Revenue: i64(200_000_000)},
+			missing: []string{"unit_mismatch:totalAssets", "unit_mismatch:revenue"},
+		},
+		{
+			name:     "numbers in millions labelled as units",
+			current:  RawData{Year: 2024, Period: Q2, ReportUnits: units("units"), TotalAssets: i64(1_100_000), Revenue: i64(200_000)},
+			expected: []string{"unit_mismatch:totalAssets", "unit_mismatch:revenue"},
+		},
+		{
+			// a tenfold change is not a thousand, a business can grow like this
+			name:    "tenfold growth",
+			current: RawData{Year: 2024, Period: Q2, ReportUnits: units("millions"), TotalAssets: i64(10_000_000), Revenue: i64(1_800_000)},
+			missing: []string{"unit_mismatch:totalAssets", "unit_mismatch:revenue"},
+		},
+		{
+			name:     "unknown units",
+			current:  RawData{Year: 2024, Period: Q2, TotalAssets: i64(1_100_000_000)},
+			expected: []string{"report_units:reportUnits"},
+			missing:  []string{"unit_mismatch:totalAssets"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := issueCodes(ValidateRawData(&tt.current, history))
+			for _, key := range tt.expected {
+				if _, found := codes[key]; !found {
+					t.Errorf("Expected issue %s, got %v", key, codes)
+				}
+			}
+			for _, key := range tt.missing {
+				if _, found := codes[key]; found {
+					t.Errorf("Expected no issue %s", key)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationReportApplyStatus(t *testing.T) {
+	withError := &ValidationReport{Issues: []ValidationIssue{{Code: "sign", Severity: SeverityError}}}
+	withWarning := &ValidationReport{Issues: []ValidationIssue{{Code: "sign", Severity: SeverityWarning}}}
+
+	tests := []struct {
+		name       string
+		report     *ValidationReport
+		status     MetricsStatus
+		expected   MetricsStatus
+		downgraded bool
+	}{
+		{name: "errors send a confirmed report to review", report: withError, status: StatusConfirmed, expected: StatusDraft, downgraded: true},
+		{name: "no status means confirmed", report: withError, status: "", expected: StatusDraft, downgraded: true},
+		{name: "warnings do not block", report: withWarning, status: StatusConfirmed, expected: StatusConfirmed},
+		{name: "drafts stay drafts", report: withError, status: StatusDraft, expected: StatusDraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RawData{Status: tt.status}
+			downgraded := tt.report.ApplyStatus(r)
+			if r.Status != tt.expected || downgraded != tt.downgraded {
+				t.Errorf("Expected %s (downgraded %v), got %s (%v)", tt.expected, tt.downgraded, r.Status, downgraded)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+type RawDataAction string
+
+const (
+	RawDataActionBaseline RawDataAction = "baseline"
+	RawDataActionCreate   RawDataAction = "create"
+	RawDataActionUpdate   RawDataAction = "update"
+	RawDataActionConfirm  RawDataAction = "confirm"
+	RawDataActionDelete   RawDataAction = "delete"
+	RawDataActionRollback RawDataAction = "rollback"
+	// RawDataActionCanonical marks a report replaced with its canonical source.
+	RawDataActionCanonical RawDataAction = "canonical"
+)
+
+// RawDataVersion is a snapshot of a report after a change. Baseline versions
+// record the state stored before the history was kept.
+type RawDataVersion struct {
+	Ticker    string        `json:"ticker"`
+	Year      int           `json:"year"`
+	Period    ReportPeriod  `json:"period"`
+	Version   int           `json:"version"`
+	Action    RawDataAction `json:"action"`
+	Actor     string        `json:"actor"`
+	Data      RawData       `json:"data"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// FieldChange is a changed field by its JSON name; nil stands for an empty value.
+type FieldChange struct {
+	Field    string `json:"field"`
+	OldValue any    `json:"oldValue"`
+	NewValue any    `json:"newValue"`
+}
+
+// DiffRawData lists fields that differ between two snapshots of the same report.
+// from may be nil, then every set field of to is reported.
+func DiffRawData(from, to *RawData) []FieldChange {
+	if from == nil {
+		from = &RawData{}
+	}
+
+	changes := []FieldChange{}
+	a, b := reflect.ValueOf(*from), reflect.ValueOf(*to)
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		switch name {
+		case "", "-", "ticker", "year", "period":
+			continue
+		}
+
+		oldValue, newValue := fieldValue(a.Field(i)), fieldValue(b.Field(i))
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, OldValue: oldValue, NewValue: newValue})
+	}
+	return changes
+}
+
+func fieldValue(v reflect.Value) any {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	}
+	if v.IsZero() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,181 @@
+package domain
+
+import "fmt"
+
+const (
+	ScreenerDefaultLimit = 50
+	ScreenerMaxLimit     = 500
+
+	// bounds on the filter tree so that a request cannot produce a huge query
+	screenerMaxDepth = 8
+	screenerMaxNodes = 100
+)
+
+// ScreenerOp is a comparison operator of a screener condition.
+type ScreenerOp string
+
+const (
+	OpLess         ScreenerOp = "lt"
+	OpLessEqual    ScreenerOp = "lte"
+	OpGreater      ScreenerOp = "gt"
+	OpGreaterEqual ScreenerOp = "gte"
+	OpEqual        ScreenerOp = "eq"
+	OpNotEqual     ScreenerOp = "neq"
+)
+
+func (op ScreenerOp) IsValid() bool {
+	switch op {
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpEqual, OpNotEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScreenerFilter is a node of the filter tree. Exactly one form must be used:
+//   - and / or: a group of nested filters;
+//   - field, op, value: a comparison of a Ratios field (by its JSON name, e.g. "priceToEarnings");
+//   - sectors: membership in one of the sectors.
+//
+// A comparison never matches a company whose field is not calculated.
+type ScreenerFilter struct {
+	And     []ScreenerFilter `json:"and,omitempty"`
+	Or      []ScreenerFilter `json:"or,omitempty"`
+	Field   string           `json:"field,omitempty"`
+	Op      ScreenerOp       `json:"op,omitempty"`
+	Value   *float64         `json:"value,omitempty"`
+	Sectors []Sector         `json:"sectors,omitempty"`
+}
+
+type ScreenerSort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// ScreenerRequest selects companies by their ratios. Period is "latest" (the
+// latest reported period of each company, default), "TTM" (the latest TTM row)
+// or a report period, which requires Year.
+type ScreenerRequest struct {
+	Filter *ScreenerFilter `json:"filter,omitempty"`
+	Sort   []ScreenerSort  `json:"sort,omitempty"`
+	Period string          `json:"period,omitempty"`
+	Year   int             `json:"year,omitempty"`
+	Limit  int             `json:"limit,omitempty"`
+	Offset int             `json:"offset,omitempty"`
+}
+
+type ScreenerItem struct {
+	Ratios
+	Sector Sector `json:"sector"`
+}
+
+type ScreenerResult struct {
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	Items  []ScreenerItem `json:"items"`
+}
+
+// Normalize validates the request structure and applies defaults. Field names
+// are checked by the repository, which knows the columns behind them.
+func (r *ScreenerRequest) Normalize() error {
+	switch r.Period {
+	case "":
+		r.Period = "latest"
+	case "latest", string(TTM):
+	default:
+		if !ReportPeriod(r.Period).IsValid() {
+			return fmt.Errorf("invalid period %q (allowed: latest, TTM, Q1, Q2, Q3, Q4, YEAR): %w", r.Period, ErrInvalidInput)
+		}
+		if r.Year == 0 {
+			return fmt.Errorf("year is required for period %s: %w", r.Period, ErrInvalidInput)
+		}
+	}
+
+	if r.Limit == 0 {
+		r.Limit = ScreenerDefaultLimit
+	}
+	if r.Limit < 0 || r.Limit > ScreenerMaxLimit {
+		return fmt.Errorf("limit must be between 1 and %d: %w", ScreenerMaxLimit, ErrInvalidInput)
+	}
+	if r.Offset < 0 {
+		return fmt.Errorf("offset must not be negative: %w", ErrInvalidInput)
+	}
+
+	for _, s := range r.Sort {
+		if s.Field == "" {
+			return fmt.Errorf("sort field is empty: %w", ErrInvalidInput)
+		}
+	}
+
+	if r.Filter != nil {
+		nodes := 0
+		if err := r.Filter.validate(1, &nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *ScreenerFilter) validate(depth int, nodes *int) error {
+	*nodes++
+	if depth > screenerMaxDepth {
+		return fmt.Errorf("filter is nested deeper than %d levels: %w", screenerMaxDepth, ErrInvalidInput)
+	}
+	if *nodes > screenerMaxNodes {
+		return fmt.Errorf("filter has more than %d nodes: %w", screenerMaxNodes, ErrInvalidInput)
+	}
+
+	forms := 0
+	if f.And != nil {
+		forms++
+	}
+	if f.Or != nil {
+		forms++
+	}
+	if f.Field != "" || f.Op != "" || f.Value != nil {
+		forms++
+	}
+	if f.Sectors != nil {
+		forms++
+	}
+	if forms != 1 {
+		return fmt.Errorf("filter must have exactly one of and, or, field or sectors: %w", ErrInvalidInput)
+	}
+
+	switch {
+	case f.And != nil || f.Or != nil:
+		children := f.And
+		if f.Or != nil {
+			children = f.Or
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("and/or group is empty: %w", ErrInvalidInput)
+		}
+		for i := range children {
+			if err := children[i].validate(depth+1, nodes); err != nil {
+				return err
+			}
+		}
+	case f.Sectors != nil:
+		if len(f.Sectors) == 0 {
+			return fmt.Errorf("sectors list is empty: %w", ErrInvalidInput)
+		}
+		for _, s := range f.Sectors {
+			if !s.IsValid() {
+				return fmt.Errorf("invalid sector %d: %w", s, ErrInvalidInput)
+			}
+		}
+	default:
+		if f.Field == "" {
+			return fmt.Errorf("condition field is empty: %w", ErrInvalidInput)
+		}
+		if !f.Op.IsValid() {
+			return fmt.Errorf("invalid op %q (allowed: lt, lte, gt, gte, eq, neq): %w", f.Op, ErrInvalidInput)
+		}
+		if f.Value == nil {
+			return fmt.Errorf("condition on %s has no value: %w", f.Field, ErrInvalidInput)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func value(v float64) *float64 {
+	return &v
+}
+
+func TestScreenerRequestNormalize(t *testing.T) {
+	cond := ScreenerFilter{Field: "roe", Op: OpGreater, Value: value(15)}
+
+	// a chain of and groups depth levels deep ending in a condition
+	nested := func(depth int) *ScreenerFilter {
+		f := cond
+		for i := 1; i < depth; i++ {
+			f = ScreenerFilter{And: []ScreenerFilter{f}}
+		}
+		return &f
+	}
+
+	many := make([]ScreenerFilter, screenerMaxNodes)
+	for i := range many {
+		many[i] = cond
+	}
+
+	tests := []struct {
+		name    string
+		req     ScreenerRequest
+		wantErr bool
+	}{
+		{name: "empty request", req: ScreenerRequest{}},
+		{
+			name: "nested and/or tree",
+			req: ScreenerRequest{Filter: &ScreenerFilter{And: []ScreenerFilter{
+				cond,
+				{Or: []ScreenerFilter{{Sectors: []Sector{1, 2}}, {Field: "priceToEarnings", Op: OpLess, Value: value(8)}}},
+			}}},
+		},
+		{name: "tree at the depth limit", req: ScreenerRequest{Filter: nested(screenerMaxDepth)}},
+		{name: "tree deeper than the limit", req: ScreenerRequest{Filter: nested(screenerMaxDepth + 1)}, wantErr: true},
+		{name: "too many nodes", req: ScreenerRequest{Filter: &ScreenerFilter{Or: many}}, wantErr: true},
+		{name: "empty group", req: ScreenerRequest{Filter: &ScreenerFilter{And: []ScreenerFilter{}}}, wantErr: true},
+		{
+			name:    "two forms in one node",
+			req:     ScreenerRequest{Filter: &ScreenerFilter{Field: "roe", Op: OpGreater, Value: value(15), Sectors: []Sector{1}}},
+			wantErr: true,
+		},
+		{name: "bad operator", req: ScreenerRequest{Filter: &ScreenerFilter{Field: "roe", Op: ">", Value: value(15)}}, wantErr: true},
+		{name: "condition without value", req: ScreenerRequest{Filter: &ScreenerFilter{Field: "roe", Op: OpGreater}}, wantErr: true},
+		{name: "invalid sector", req: ScreenerRequest{Filter: &ScreenerFilter{Sectors: []Sector{0}}}, wantErr: true},
+		{name: "maximum limit", req: ScreenerRequest{Limit: ScreenerMaxLimit}},
+		{name: "limit above maximum", req: ScreenerRequest{Limit: ScreenerMaxLimit + 1}, wantErr: true},
+		{name: "negative limit", req: ScreenerRequest{Limit: -1}, wantErr: true},
+		{name: "negative offset", req: ScreenerRequest{Offset: -1}, wantErr: true},
+		{name: "empty sort field", req: ScreenerRequest{Sort: []ScreenerSort{{Desc: true}}}, wantErr: true},
+		{name: "report period with year", req: ScreenerRequest{Period: "Q1", Year: 2024}},
+		{name: "report period without year", req: ScreenerRequest{Period: "Q1"}, wantErr: true},
+		{name: "unknown period", req: ScreenerRequest{Period: "H1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Normalize()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("Expected ErrInvalidInput, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestScreenerRequestNormalizeDefaults(t *testing.T) {
+	req := ScreenerRequest{}
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.Period != "latest" || req.Limit != ScreenerDefaultLimit || req.Offset != 0 {
+		t.Errorf("Expected period latest, limit %d and offset 0, got %s, %d and %d", ScreenerDefaultLimit, req.Period, req.Limit, req.Offset)
+	}
+}
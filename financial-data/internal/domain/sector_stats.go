@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MetricStats describes the distribution of one ratio across a sector.
+// Quartiles are linearly interpolated, as PostgreSQL percentile_cont does.
+type MetricStats struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Q1     float64 `json:"q1"`
+	Median float64 `json:"median"`
+	Q3     float64 `json:"q3"`
+	Max    float64 `json:"max"`
+}
+
+// SectorStats holds statistics per ratio, keyed by its JSON name (e.g. "priceToEarnings").
+type SectorStats struct {
+	Sector    Sector                 `json:"sector"`
+	Period    string                 `json:"period"`
+	Companies int                    `json:"companies"`
+	Metrics   map[string]MetricStats `json:"metrics"`
+}
+
+// MetricRank places a company's ratio within its sector. Percentile is the share
+// of sector values below the company's one, ties counted as half, from 0 to 100.
+type MetricRank struct {
+	Value      float64 `json:"value"`
+	Percentile float64 `json:"percentile"`
+	Count      int     `json:"count"`
+}
+
+type CompanyRank struct {
+	Ticker  string                `json:"ticker"`
+	Sector  Sector                `json:"sector"`
+	Year    int                   `json:"year"`
+	Period  ReportPeriod          `json:"period"`
+	Metrics map[string]MetricRank `json:"metrics"`
+}
+
+// RatioValues returns the ratios that are set, keyed by JSON name.
+func RatioValues(r Ratios) map[string]float64 {
+	values := make(map[string]float64)
+	v := reflect.ValueOf(r)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Pointer || field.IsNil() || field.Elem().Kind() != reflect.Float64 {
+			continue
+		}
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		values[name] = field.Elem().Float()
+	}
+	return values
+}
+
+// CalculateSectorStats computes statistics over the given ratios, one row per company.
+func CalculateSectorStats(rows []Ratios) map[string]MetricStats {
+	stats := make(map[string]MetricStats)
+	for name, values := range collectValues(rows) {
+		sort.Float64s(values)
+		stats[name] = MetricStats{
+			Count:  len(values),
+			Min:    values[0],
+			Q1:     quantile(values, 0.25),
+			Median: quantile(values, 0.5),
+			Q3:     quantile(values, 0.75),
+			Max:    values[len(values)-1],
+		}
+	}
+	return stats
+}
+
+// RankInSector ranks every set ratio of company among rows, which should include the company itself.
+func RankInSector(company Ratios, rows []Ratios) map[string]MetricRank {
+	sector := collectValues(rows)
+	ranks := make(map[string]MetricRank)
+	for name, value := range RatioValues(company) {
+		values := sector[name]
+		if len(values) == 0 {
+			continue
+		}
+		var below, equal int
+		for _, v := range values {
+			switch {
+			case v < value:
+				below++
+			case v == value:
+				equal++
+			}
+		}
+		ranks[name] = MetricRank{
+			Value:      value,
+			Percentile: (float64(below) + float64(equal)/2) / float64(len(values)) * 100,
+			Count:      len(values),
+		}
+	}
+	return ranks
+}
+
+func collectValues(rows []Ratios) map[string][]float64 {
+	result := make(map[string][]float64)
+	for _, row := range rows {
+		for name, value := range RatioValues(row) {
+			result[name] = append(result[name], value)
+		}
+	}
+	return result
+}
+
+// quantile expects sorted, non-empty values.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
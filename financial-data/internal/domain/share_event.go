@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+type ShareEventType string
+
+const (
+	ShareEventSplit         ShareEventType = "split"
+	ShareEventConsolidation ShareEventType = "consolidation"
+	ShareEventBuyback       ShareEventType = "buyback"
+	ShareEventIssue         ShareEventType = "issue"
+	// ShareEventSnapshot records a known share count without a corporate action,
+	// e.g. the starting point of the history.
+	ShareEventSnapshot ShareEventType = "snapshot"
+)
+
+func (t ShareEventType) IsValid() bool {
+	switch t {
+	case ShareEventSplit, ShareEventConsolidation, ShareEventBuyback, ShareEventIssue, ShareEventSnapshot:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShareEvent changes the number of shares outstanding of a ticker starting from Date.
+// SharesOutstanding is the count after the event. Ratio is the number of new shares
+// per old one and is set for splits (> 1) and consolidations (< 1).
+type ShareEvent struct {
+	ID                int            `json:"id"`
+	Ticker            string         `json:"ticker"`
+	Date              time.Time      `json:"date"`
+	Type              ShareEventType `json:"type"`
+	SharesOutstanding int64          `json:"sharesOutstanding"`
+	Ratio             *float64       `json:"ratio,omitempty"`
+	Note              *string        `json:"note,omitempty"`
+}
+
+// PeriodEndDate returns the last day of the reporting period.
+func PeriodEndDate(year int, period ReportPeriod) time.Time {
+	months := 12
+	switch period {
+	case Q1:
+		months = 3
+	case Q2:
+		months = 6
+	case Q3:
+		months = 9
+	}
+	return time.Date(year, time.Month(months)+1, 0, 0, 0, 0, 0, time.UTC)
+}
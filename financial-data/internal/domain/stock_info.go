@@ -2,18 +2,36 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"strconv"
+	"time"
 )
 
+// StockInfo is the ISS description of a security. Board, Currency, Traded and
+// ListedTill come from its primary board, LotSize from the securities of that board,
+// FreeFloat from the free-float coefficients MOEX sets for index calculation.
 type StockInfo struct {
-	Ticker         string `json:"ticker"`
-	NumberOfShares int    `json:"numberOfShares"`
-	Name           string `json:"name"`
+	Ticker         string     `json:"ticker"`
+	NumberOfShares int        `json:"numberOfShares"`
+	Name           string     `json:"name"`
+	ISIN           string     `json:"isin,omitempty"`
+	RegNumber      string     `json:"regNumber,omitempty"`
+	ListLevel      int        `json:"listLevel,omitempty"`
+	Board          string     `json:"board,omitempty"`
+	Market         string     `json:"market,omitempty"`
+	Currency       string     `json:"currency,omitempty"`
+	LotSize        int        `json:"lotSize,omitempty"`
+	Traded         bool       `json:"traded"`
+	ListedTill     *time.Time `json:"listedTill,omitempty"`
+	// FreeFloat is the percent of shares in free circulation; only securities
+	// in the base of MOEX indices have one.
+	FreeFloat *float64 `json:"freeFloat,omitempty"`
 }
 
 func ParseStockInfo(data [][]string) (*StockInfo, error) {
 	if len(data) == 0 {
-		return nil, errors.New("data is empty")
+		return nil, fmt.Errorf("description is empty: %w", ErrNotFound)
 	}
 	stockInfo := &StockInfo{}
 	for _, row := range data {
@@ -26,13 +44,98 @@ func ParseStockInfo(data [][]string) (*StockInfo, error) {
 			stockInfo.Ticker = row[2]
 		case "NAME":
 			stockInfo.Name = row[2]
+		case "ISIN":
+			stockInfo.ISIN = row[2]
+		case "REGNUMBER":
+			stockInfo.RegNumber = row[2]
+		case "LISTLEVEL":
+			if level, err := strconv.Atoi(row[2]); err == nil {
+				stockInfo.ListLevel = level
+			}
 		case "ISSUESIZE":
 			numberOfShares, err := strconv.Atoi(row[2])
 			if err != nil {
-				return nil, err	
+				return nil, err
 			}
 			stockInfo.NumberOfShares = numberOfShares
 		}
 	}
 	return stockInfo, nil
 }
+
+// SetPrimaryBoard fills the board fields from the ISS boards table: the primary
+// board, or the first traded one when none is marked primary.
+func (s *StockInfo) SetPrimaryBoard(boards IssTable) {
+	records := boards.Records()
+	var board map[string]any
+	for _, record := range records {
+		if primary, _ := record["is_primary"].(float64); primary == 1 {
+			board = record
+			break
+		}
+	}
+	if board == nil {
+		for _, record := range records {
+			if traded, _ := record["is_traded"].(float64); traded == 1 {
+				board = record
+				break
+			}
+		}
+	}
+	if board == nil {
+		return
+	}
+
+	s.Board, _ = board["boardid"].(string)
+	// markets are only addressed on the stock engine
+	if engine, _ := board["engine"].(string); engine == "stock" {
+		s.Market, _ = board["market"].(string)
+	}
+	s.Currency, _ = board["currencyid"].(string)
+	if s.Currency == "SUR" {
+		s.Currency = "RUB"
+	}
+	traded, _ := board["is_traded"].(float64)
+	s.Traded = traded == 1
+	if till, ok := board["listed_till"].(string); ok {
+		if date, err := time.Parse("2006-01-02", till); err == nil {
+			s.ListedTill = &date
+		}
+	}
+}
+
+// SetLotSize reads the lot size from the ISS securities table of the board.
+func (s *StockInfo) SetLotSize(securities IssTable) {
+	for _, record := range securities.Records() {
+		if lotSize, ok := record["lotsize"].(float64); ok && lotSize > 0 {
+			s.LotSize = int(lotSize)
+			return
+		}
+	}
+}
+
+// SetFreeFloat reads the coefficient of the security from the MOEX free-float
+// table. Coefficients are published as fractions of the issue, so 0.48 is 48%;
+// the latest row of the ticker wins.
+func (s *StockInfo) SetFreeFloat(table IssTable) {
+	for _, record := range table.Records() {
+		if secid, _ := record["secid"].(string); secid != s.Ticker {
+			continue
+		}
+		coefficient, ok := record["freefloat"].(float64)
+		if !ok || coefficient <= 0 || coefficient > 1 {
+			continue
+		}
+		percent := math.Round(coefficient*10000) / 100
+		s.FreeFloat = &percent
+	}
+}
+
+// Delisted tells whether the security is no longer traded on its primary board.
+// ListedTill is not used: for boards still traded ISS sets it to the current date.
+func (s *StockInfo) Delisted() bool {
+	if s.Board == "" {
+		return false
+	}
+	return !s.Traded
+}
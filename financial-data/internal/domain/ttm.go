@@ -0,0 +1,134 @@
+package domain
+
+// TTM marks ratios computed over the trailing twelve months. It is a ratios-only
+// period: raw data is always stored per reported period.
+const TTM ReportPeriod = "TTM"
+
+// IsValidForRatios reports whether ratios can be stored under the period.
+func (rp ReportPeriod) IsValidForRatios() bool {
+	return rp == TTM || rp.IsValid()
+}
+
+// flowFields returns the income statement and cash flow fields of r, which cover
+// a span of time and have to be summed over quarters. Everything else is a
+// balance or market value taken as of the period end.
+func flowFields(r *RawData) []**int64 {
+	return []**int64{
+		&r.Revenue, &r.CostOfRevenue, &r.GrossProfit, &r.OperatingExpenses, &r.OtherIncome, &r.OtherExpenses,
+		&r.EBIT, &r.EBITDA, &r.Depreciation, &r.InterestIncome, &r.InterestExpense,
+		&r.ProfitBeforeTax, &r.TaxExpense, &r.NetProfit, &r.NetProfitParent,
+		&r.OperatingCashFlow, &r.InvestingCashFlow, &r.FinancingCashFlow, &r.CAPEX, &r.FreeCashFlow,
+		&r.DividendsPaid, &r.LeasePayments, &r.AcquisitionsNet, &r.InterestPaid, &r.DebtProceeds, &r.DebtRepayments,
+		&r.InterestOnLeases, &r.InterestOnLoans,
+		&r.NetInterestIncome, &r.CommissionIncome, &r.CommissionExpense, &r.NetCommissionIncome, &r.CreditLossProvision,
+	}
+}
+
+// TTMThroughPeriods lists the periods a TTM can end at, latest first.
+var TTMThroughPeriods = []ReportPeriod{YEAR, Q3, Q2, Q1}
+
+// BuildTTM combines reports of history into trailing twelve months ending at
+// the given period of year. Q1–Q3 reports are treated as cumulative since the
+// start of the year (3M, 6M, 9M), as Russian IFRS reports are published, so
+//
+//	TTM = YTD(year, period) + YEAR(year-1) - YTD(year-1, period).
+//
+// A missing YEAR report is replaced by 9M + Q4. Balance sheet and market values
+// come from the latest report. ok is false when a needed report is missing.
+func BuildTTM(history []RawData, year int, through ReportPeriod) (ttm *RawData, ok bool) {
+	current, ok := yearToDate(history, year, through)
+	if !ok {
+		return nil, false
+	}
+
+	result := *current
+	result.Period = TTM
+	if through == YEAR {
+		return &result, true
+	}
+
+	lastYear, ok := yearToDate(history, year-1, YEAR)
+	if !ok {
+		return nil, false
+	}
+	lastYTD, ok := yearToDate(history, year-1, through)
+	if !ok {
+		return nil, false
+	}
+
+	cur, full, prev := flowFields(&result), flowFields(lastYear), flowFields(lastYTD)
+	for i := range cur {
+		*cur[i] = combine(*cur[i], *full[i], *prev[i])
+	}
+
+	result.BasicEPS = nil
+	if current.BasicEPS != nil && lastYear.BasicEPS != nil && lastYTD.BasicEPS != nil {
+		eps := *current.BasicEPS + *lastYear.BasicEPS - *lastYTD.BasicEPS
+		result.BasicEPS = &eps
+	}
+
+	return &result, true
+}
+
+// LatestTTM builds the TTM ending at the latest period of year that has every
+// report it needs.
+func LatestTTM(history []RawData, year int) (ttm *RawData, through ReportPeriod, ok bool) {
+	for _, period := range TTMThroughPeriods {
+		if ttm, ok := BuildTTM(history, year, period); ok {
+			return ttm, period, true
+		}
+	}
+	return nil, "", false
+}
+
+// yearToDate returns the report covering the year from its start to the end of period.
+func yearToDate(history []RawData, year int, period ReportPeriod) (*RawData, bool) {
+	if r := findReport(history, year, period); r != nil {
+		return r, true
+	}
+	if period != YEAR {
+		return nil, false
+	}
+
+	nineMonths := findReport(history, year, Q3)
+	fourthQuarter := findReport(history, year, Q4)
+	if nineMonths == nil || fourthQuarter == nil {
+		return nil, false
+	}
+
+	result := *fourthQuarter
+	result.Period = YEAR
+	cur, prev := flowFields(&result), flowFields(nineMonths)
+	for i := range cur {
+		if *cur[i] == nil || *prev[i] == nil {
+			*cur[i] = nil
+			continue
+		}
+		v := **cur[i] + **prev[i]
+		*cur[i] = &v
+	}
+	result.BasicEPS = nil
+	if fourthQuarter.BasicEPS != nil && nineMonths.BasicEPS != nil {
+		eps := *fourthQuarter.BasicEPS + *nineMonths.BasicEPS
+		result.BasicEPS = &eps
+	}
+
+	return &result, true
+}
+
+func findReport(history []RawData, year int, period ReportPeriod) *RawData {
+	for i := range history {
+		if history[i].Year == year && history[i].Period == period {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+func combine(current, fullYear, previous *int64) *int64 {
+	if current == nil || fullYear == nil || previous == nil {
+		return nil
+	}
+	v := *current + *fullYear - *previous
+	return &v
+}
@@ -0,0 +1,137 @@
+package domain
+
+import "testing"
+
+func i64(v int64) *int64 { return &v }
+
+func report(year int, period ReportPeriod, revenue, netProfit, cash int64) RawData {
+	return RawData{
+		Ticker:             "TEST",
+		Year:               year,
+		Period:             period,
+		Revenue:            i64(revenue),
+		NetProfit:          i64(netProfit),
+		CashAndEquivalents: i64(cash),
+	}
+}
+
+func TestBuildTTM(t *testing.T) {
+	history := []RawData{
+		report(2023, Q1, 100, 10, 1000),
+		report(2023, Q2, 220, 22, 1100),
+		report(2023, Q3, 350, 35, 1200),
+		report(2023, YEAR, 500, 50, 1300),
+		report(2024, Q1, 130, 13, 1400),
+		report(2024, Q2, 270, 27, 1500),
+		report(2024, Q3, 420, 42, 1600),
+	}
+
+	tests := []struct {
+		name      string
+		history   []RawData
+		year      int
+		through   ReportPeriod
+		ok        bool
+		revenue   int64
+		netProfit int64
+		cash      int64
+	}{
+		{name: "3M", history: history, year: 2024, through: Q1, ok: true, revenue: 130 + 500 - 100, netProfit: 13 + 50 - 10, cash: 1400},
+		{name: "6M", history: history, year: 2024, through: Q2, ok: true, revenue: 270 + 500 - 220, netProfit: 27 + 50 - 22, cash: 1500},
+		{name: "9M", history: history, year: 2024, through: Q3, ok: true, revenue: 420 + 500 - 350, netProfit: 42 + 50 - 35, cash: 1600},
+		{name: "YEAR is the TTM itself", history: history, year: 2023, through: YEAR, ok: true, revenue: 500, netProfit: 50, cash: 1300},
+		{name: "no YEAR report of the current year", history: history, year: 2024, through: YEAR, ok: false},
+		{name: "no previous year", history: history, year: 2023, through: Q3, ok: false},
+		{
+			name: "YEAR replaced by 9M + Q4",
+			history: []RawData{
+				report(2023, Q2, 220, 22, 1100),
+				report(2023, Q3, 350, 35, 1200),
+				report(2023, Q4, 150, 15, 1300),
+				report(2024, Q2, 270, 27, 1500),
+			},
+			year: 2024, through: Q2, ok: true, revenue: 270 + (350 + 150) - 220, netProfit: 27 + (35 + 15) - 22, cash: 1500,
+		},
+		{
+			name: "YEAR fallback needs both 9M and Q4",
+			history: []RawData{
+				report(2023, Q2, 220, 22, 1100),
+				report(2023, Q4, 150, 15, 1300),
+				report(2024, Q2, 270, 27, 1500),
+			},
+			year: 2024, through: Q2, ok: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttm, ok := BuildTTM(tt.history, tt.year, tt.through)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if ttm.Period != TTM {
+				t.Errorf("Expected period TTM, got %s", ttm.Period)
+			}
+			if *ttm.Revenue != tt.revenue {
+				t.Errorf("Expected revenue %d, got %d", tt.revenue, *ttm.Revenue)
+			}
+			if *ttm.NetProfit != tt.netProfit {
+				t.Errorf("Expected net profit %d, got %d", tt.netProfit, *ttm.NetProfit)
+			}
+			// balance sheet values are taken as of the latest report
+			if *ttm.CashAndEquivalents != tt.cash {
+				t.Errorf("Expected cash %d, got %d", tt.cash, *ttm.CashAndEquivalents)
+			}
+		})
+	}
+}
+
+func TestBuildTTMMissingFieldAndEPS(t *testing.T) {
+	eps := func(v float64) *float64 { return &v }
+	history := []RawData{
+		report(2023, Q1, 100, 10, 1000),
+		report(2023, YEAR, 500, 50, 1300),
+		report(2024, Q1, 130, 13, 1400),
+	}
+	history[0].BasicEPS, history[1].BasicEPS, history[2].BasicEPS = eps(1), eps(5), eps(1.5)
+	history[1].EBITDA = i64(80)
+
+	ttm, ok := BuildTTM(history, 2024, Q1)
+	if !ok {
+		t.Fatal("Expected TTM to be built")
+	}
+	if ttm.EBITDA != nil {
+		t.Errorf("Expected EBITDA missing in one of the reports to stay nil, got %d", *ttm.EBITDA)
+	}
+	if ttm.BasicEPS == nil || *ttm.BasicEPS != 5.5 {
+		t.Errorf("Expected EPS 5.5, got %v", ttm.BasicEPS)
+	}
+	if history[2].Period != Q1 || *history[2].Revenue != 130 {
+		t.Error("Expected history to stay untouched")
+	}
+}
+
+func TestLatestTTM(t *testing.T) {
+	history := []RawData{
+		report(2023, YEAR, 500, 50, 1300),
+		report(2023, Q2, 220, 22, 1100),
+		report(2024, Q1, 130, 13, 1400),
+		report(2024, Q2, 270, 27, 1500),
+	}
+
+	ttm, through, ok := LatestTTM(history, 2024)
+	if !ok {
+		t.Fatal("Expected TTM to be built")
+	}
+	// Q2 is the latest period with every report needed; Q1 of 2023 is missing anyway
+	if through != Q2 || *ttm.Revenue != 270+500-220 {
+		t.Errorf("Expected TTM through Q2 with revenue 550, got %s, %d", through, *ttm.Revenue)
+	}
+
+	if _, _, ok := LatestTTM(history, 2022); ok {
+		t.Error("Expected no TTM for a year without reports")
+	}
+}
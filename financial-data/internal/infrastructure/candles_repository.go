@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CandlesRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCandlesRepository(pool *pgxpool.Pool) *CandlesRepository {
+	return &CandlesRepository{pool: pool}
+}
+
+// GetRange returns candles whose begin falls within [from, till], both dates inclusive.
+func (r *CandlesRepository) GetRange(ctx context.Context, ticker string, interval domain.Period, from, till time.Time) ([]domain.Candle, error) {
+	if ticker == "" {
+		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+
+	query := `
+		SELECT open, close, high, low, value, volume, begin_at, end_at
+		FROM candles
+		WHERE ticker = $1 AND interval = $2 AND begin_at >= $3 AND begin_at < $4
+		ORDER BY begin_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, ticker, int(interval), from, till.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query candles: %w", err)
+	}
+	defer rows.Close()
+
+	candles := make([]domain.Candle, 0)
+	for rows.Next() {
+		var c domain.Candle
+		var begin, end time.Time
+		err := rows.Scan(&c.Open, &c.Close, &c.High, &c.Low, &c.Value, &c.Volume, &begin, &end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		c.Begin = begin.Format(domain.CandleTimeLayout)
+		c.End = end.Format(domain.CandleTimeLayout)
+		candles = append(candles, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating candles: %w", err)
+	}
+
+	return candles, nil
+}
+
+// GetLastBegin returns the begin of the most recent stored candle.
+func (r *CandlesRepository) GetLastBegin(ctx context.Context, ticker string, interval domain.Period) (time.Time, error) {
+	query := `SELECT begin_at FROM candles WHERE ticker = $1 AND interval = $2 ORDER BY begin_at DESC LIMIT 1`
+
+	var begin time.Time
+	err := r.pool.QueryRow(ctx, query, ticker, int(interval)).Scan(&begin)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, fmt.Errorf("no candles stored for %s (interval %d): %w", ticker, interval, domain.ErrNotFound)
+		}
+		return time.Time{}, fmt.Errorf("failed to get last candle: %w", err)
+	}
+
+	return begin, nil
+}
+
+// Upsert stores candles, overwriting existing ones with the same begin so that
+// a still-forming candle gets its final values on the next import.
+func (r *CandlesRepository) Upsert(ctx context.Context, ticker string, interval domain.Period, candles []domain.Candle) error {
+	if ticker == "" {
+		return fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+	if len(candles) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO candles (ticker, interval, begin_at, end_at, open, close, high, low, value, volume)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (ticker, interval, begin_at) DO UPDATE SET
+			end_at = EXCLUDED.end_at,
+			open = EXCLUDED.open,
+			close = EXCLUDED.close,
+			high = EXCLUDED.high,
+			low = EXCLUDED.low,
+			value = EXCLUDED.value,
+			volume = EXCLUDED.volume,
+			updated_at = NOW()
+	`
+
+	batch := &pgx.Batch{}
+	for _, c := range candles {
+		begin, err := time.Parse(domain.CandleTimeLayout, c.Begin)
+		if err != nil {
+			return fmt.Errorf("invalid candle begin %q: %w", c.Begin, domain.ErrInvalidInput)
+		}
+		end, err := time.Parse(domain.CandleTimeLayout, c.End)
+		if err != nil {
+			return fmt.Errorf("invalid candle end %q: %w", c.End, domain.ErrInvalidInput)
+		}
+		batch.Queue(query, ticker, int(interval), begin, end, c.Open, c.Close, c.High, c.Low, c.Value, c.Volume)
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to upsert candles: %w", err)
+	}
+
+	return nil
+}
@@ -51,6 +51,23 @@ func (r *CBRateRepository) GetByDate(ctx context.Context, date time.Time) (*doma
 	return rate, nil
 }
 
+// GetAsOf returns the rate in force on date, that is the latest change on or before it.
+func (r *CBRateRepository) GetAsOf(ctx context.Context, date time.Time) (*domain.CBRate, error) {
+	query := `SELECT date, rate FROM cb_rates WHERE date <= $1 ORDER BY date DESC LIMIT 1`
+
+	rate := &domain.CBRate{}
+	err := r.pool.QueryRow(ctx, query, date).Scan(&rate.Date, &rate.Rate)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("no CB rate in force on %s: %w", date.Format("2006-01-02"), domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get CB rate: %w", err)
+	}
+
+	return rate, nil
+}
+
 func (r *CBRateRepository) GetHistory(ctx context.Context, from, to time.Time) ([]domain.CBRate, error) {
 	query := `
 		SELECT date, rate
@@ -101,6 +118,22 @@ func (r *CBRateRepository) Create(ctx context.Context, rate *domain.CBRate) erro
 	return nil
 }
 
+// Upsert stores a rate change, replacing the rate of the same date.
+func (r *CBRateRepository) Upsert(ctx context.Context, rate *domain.CBRate) error {
+	if rate == nil {
+		return fmt.Errorf("rate is nil: %w", domain.ErrInvalidInput)
+	}
+
+	query := `INSERT INTO cb_rates (date, rate) VALUES ($1, $2) ON CONFLICT (date) DO UPDATE SET rate = EXCLUDED.rate`
+
+	_, err := r.pool.Exec(ctx, query, rate.Date, rate.Rate)
+	if err != nil {
+		return fmt.Errorf("failed to upsert CB rate: %w", err)
+	}
+
+	return nil
+}
+
 func (r *CBRateRepository) Update(ctx context.Context, date time.Time, rate float64) error {
 	query := `UPDATE cb_rates SET rate = $2 WHERE date = $1`
 
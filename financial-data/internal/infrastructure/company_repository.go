@@ -19,6 +19,62 @@ const (
 	companiesAllKey = "companies:all"
 )
 
+const companySelectColumns = `id, ticker, name, sector_id, lot_size, ceo, board, instrument_type, issuer_ticker,
+	isin, reg_number, list_level, free_float, issue_size, currency, status, delisted_at, refreshed_at`
+
+func scanCompany(row pgx.Row, company *domain.Company) error {
+	var name, issuerTicker, isin, regNumber, currency *string
+	var listLevel *int
+	var issueSize *int64
+	err := row.Scan(
+		&company.ID, &company.Ticker, &name, &company.SectorID, &company.LotSize, &company.CEO,
+		&company.Board, &company.InstrumentType, &issuerTicker,
+		&isin, &regNumber, &listLevel, &company.FreeFloat, &issueSize, &currency,
+		&company.Status, &company.DelistedAt, &company.RefreshedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if name != nil {
+		company.Name = *name
+	}
+	if issuerTicker != nil {
+		company.IssuerTicker = *issuerTicker
+	}
+	if isin != nil {
+		company.ISIN = *isin
+	}
+	if regNumber != nil {
+		company.RegNumber = *regNumber
+	}
+	if listLevel != nil {
+		company.ListLevel = *listLevel
+	}
+	if issueSize != nil {
+		company.IssueSize = *issueSize
+	}
+	if currency != nil {
+		company.Currency = *currency
+	}
+	return nil
+}
+
+// nullIfZero stores unknown numeric profile fields as NULL.
+func nullIfZero[T int | int64](v T) *T {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// nullIfEmpty stores empty optional strings as NULL.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 type CompanyRepository struct {
 	pool  *pgxpool.Pool
 	redis *redis.Client
@@ -62,21 +118,10 @@ func (r *CompanyRepository) GetByTicker(ctx context.Context, ticker string) (*do
 		slog.Warn("redis get failed", "key", key, "error", err)
 	}
 
-	query := `
-		SELECT id, ticker, name, sector_id, lot_size, ceo
-		FROM companies
-		WHERE ticker = $1
-	`
+	query := `SELECT ` + companySelectColumns + ` FROM companies WHERE ticker = $1`
 
-	var name *string
 	company := &domain.Company{}
-	err = r.pool.QueryRow(ctx, query, ticker).Scan(
-		&company.ID, &company.Ticker, &name, &company.SectorID, &company.LotSize, &company.CEO,
-	)
-	if name != nil {
-		company.Name = *name
-	}
-
+	err = scanCompany(r.pool.QueryRow(ctx, query, ticker), company)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("company not found for ticker %s: %w", ticker, domain.ErrNotFound)
@@ -94,11 +139,7 @@ func (r *CompanyRepository) GetByTicker(ctx context.Context, ticker string) (*do
 }
 
 func (r *CompanyRepository) GetAll(ctx context.Context) ([]domain.Company, error) {
-	query := `
-		SELECT id, ticker, name, sector_id, lot_size, ceo
-		FROM companies
-		ORDER BY ticker
-	`
+	query := `SELECT ` + companySelectColumns + ` FROM companies ORDER BY ticker`
 
 	companies := make([]domain.Company, 0)
 	cached, err := r.redis.Get(ctx, companiesAllKey).Result()
@@ -118,16 +159,9 @@ func (r *CompanyRepository) GetAll(ctx context.Context) ([]domain.Company, error
 
 	for rows.Next() {
 		var company domain.Company
-		var name *string
-		err := rows.Scan(
-			&company.ID, &company.Ticker, &name, &company.SectorID, &company.LotSize, &company.CEO,
-		)
-		if err != nil {
+		if err := scanCompany(rows, &company); err != nil {
 			return nil, fmt.Errorf("failed to scan company: %w", err)
 		}
-		if name != nil {
-			company.Name = *name
-		}
 		companies = append(companies, company)
 	}
 
@@ -156,12 +190,7 @@ func (r *CompanyRepository) GetBySector(ctx context.Context, sectorID int) ([]do
 		slog.Warn("redis get failed", "key", key, "error", err)
 	}
 
-	query := `
-		SELECT id, ticker, name, sector_id, lot_size, ceo
-		FROM companies
-		WHERE sector_id = $1
-		ORDER BY ticker
-	`
+	query := `SELECT ` + companySelectColumns + ` FROM companies WHERE sector_id = $1 ORDER BY ticker`
 
 	rows, err := r.pool.Query(ctx, query, sectorID)
 	if err != nil {
@@ -172,16 +201,9 @@ func (r *CompanyRepository) GetBySector(ctx context.Context, sectorID int) ([]do
 	var companies []domain.Company
 	for rows.Next() {
 		var company domain.Company
-		var name *string
-		err := rows.Scan(
-			&company.ID, &company.Ticker, &name, &company.SectorID, &company.LotSize, &company.CEO,
-		)
-		if err != nil {
+		if err := scanCompany(rows, &company); err != nil {
 			return nil, fmt.Errorf("failed to scan company: %w", err)
 		}
-		if name != nil {
-			company.Name = *name
-		}
 		companies = append(companies, company)
 	}
 
@@ -206,14 +228,25 @@ func (r *CompanyRepository) Create(ctx context.Context, company *domain.Company)
 		return fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
 	}
 
+	company.ApplyDefaults()
+	if !company.InstrumentType.IsValid() {
+		return fmt.Errorf("invalid instrument type %q: %w", company.InstrumentType, domain.ErrInvalidInput)
+	}
+
 	query := `
-		INSERT INTO companies (ticker, name, sector_id, lot_size, ceo)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO companies (
+			ticker, name, sector_id, lot_size, ceo, board, instrument_type, issuer_ticker,
+			isin, reg_number, list_level, free_float, issue_size, currency, status, delisted_at, refreshed_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		RETURNING id
 	`
 
 	err := r.pool.QueryRow(ctx, query,
 		company.Ticker, company.Name, company.SectorID, company.LotSize, company.CEO,
+		company.Board, company.InstrumentType, nullIfEmpty(company.IssuerTicker),
+		nullIfEmpty(company.ISIN), nullIfEmpty(company.RegNumber), nullIfZero(company.ListLevel), company.FreeFloat,
+		nullIfZero(company.IssueSize), nullIfEmpty(company.Currency), company.Status, company.DelistedAt, company.RefreshedAt,
 	).Scan(&company.ID)
 
 	if err != nil {
@@ -233,14 +266,21 @@ func (r *CompanyRepository) Update(ctx context.Context, ticker string, company *
 		return fmt.Errorf("company is nil: %w", domain.ErrInvalidInput)
 	}
 
+	company.ApplyDefaults()
+	if !company.InstrumentType.IsValid() {
+		return fmt.Errorf("invalid instrument type %q: %w", company.InstrumentType, domain.ErrInvalidInput)
+	}
+
 	query := `
 		UPDATE companies SET
-			name = $2, sector_id = $3, lot_size = $4, ceo = $5
+			name = $2, sector_id = $3, lot_size = $4, ceo = $5,
+			board = $6, instrument_type = $7, issuer_ticker = $8, free_float = $9
 		WHERE ticker = $1
 	`
 
 	result, err := r.pool.Exec(ctx, query,
 		ticker, company.Name, company.SectorID, company.LotSize, company.CEO,
+		company.Board, company.InstrumentType, nullIfEmpty(company.IssuerTicker), company.FreeFloat,
 	)
 
 	if err != nil {
@@ -259,6 +299,41 @@ func (r *CompanyRepository) Update(ctx context.Context, ticker string, company *
 	return nil
 }
 
+// UpdateProfile stores the fields loaded from ISS by the profile refresh.
+func (r *CompanyRepository) UpdateProfile(ctx context.Context, company *domain.Company) error {
+	if company == nil || company.Ticker == "" {
+		return fmt.Errorf("company or ticker is empty: %w", domain.ErrInvalidInput)
+	}
+
+	query := `
+		UPDATE companies SET
+			name = $2, lot_size = $3, board = $4, isin = $5, reg_number = $6, list_level = $7,
+			issue_size = $8, currency = $9, status = $10, delisted_at = $11, refreshed_at = $12, free_float = $13
+		WHERE ticker = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		company.Ticker, company.Name, company.LotSize, company.Board,
+		nullIfEmpty(company.ISIN), nullIfEmpty(company.RegNumber), nullIfZero(company.ListLevel),
+		nullIfZero(company.IssueSize), nullIfEmpty(company.Currency), company.Status, company.DelistedAt, company.RefreshedAt,
+		company.FreeFloat,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update company profile: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("company not found for ticker %s: %w", company.Ticker, domain.ErrNotFound)
+	}
+
+	if err := r.redis.Del(ctx, r.companyKey(company.Ticker)).Err(); err != nil {
+		slog.Warn("redis del failed on profile update", "ticker", company.Ticker, "error", err)
+	}
+	r.invalidateListCaches(ctx, company.SectorID)
+
+	return nil
+}
+
 func (r *CompanyRepository) Delete(ctx context.Context, ticker string) error {
 	if ticker == "" {
 		return fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
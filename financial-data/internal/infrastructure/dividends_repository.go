@@ -5,6 +5,7 @@ import (
 	"errors"
 	"financial_data/internal/domain"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -25,7 +26,7 @@ func (r *DividendsRepository) GetByTicker(ctx context.Context, ticker string) ([
 
 	query := `
 		SELECT id, ticker, ex_dividend_date, payment_date, amount_per_share,
-		       dividend_yield, payout_ratio, currency
+		       dividend_yield, payout_ratio, currency, record_date
 		FROM dividends
 		WHERE ticker = $1
 		ORDER BY ex_dividend_date DESC
@@ -42,7 +43,43 @@ func (r *DividendsRepository) GetByTicker(ctx context.Context, ticker string) ([
 		var div domain.Dividends
 		err := rows.Scan(
 			&div.ID, &div.Ticker, &div.ExDividendDate, &div.PaymentDate,
-			&div.AmountPerShare, &div.DividendYield, &div.PayoutRatio, &div.Currency,
+			&div.AmountPerShare, &div.DividendYield, &div.PayoutRatio, &div.Currency, &div.RecordDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dividend: %w", err)
+		}
+		dividends = append(dividends, div)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dividends: %w", err)
+	}
+
+	return dividends, nil
+}
+
+// GetByExDateRange returns dividends of all tickers with an ex-date in [from, till], earliest first.
+func (r *DividendsRepository) GetByExDateRange(ctx context.Context, from, till time.Time) ([]domain.Dividends, error) {
+	query := `
+		SELECT id, ticker, ex_dividend_date, payment_date, amount_per_share,
+		       dividend_yield, payout_ratio, currency, record_date
+		FROM dividends
+		WHERE ex_dividend_date BETWEEN $1 AND $2
+		ORDER BY ex_dividend_date, ticker
+	`
+
+	rows, err := r.pool.Query(ctx, query, from, till)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dividends: %w", err)
+	}
+	defer rows.Close()
+
+	var dividends []domain.Dividends
+	for rows.Next() {
+		var div domain.Dividends
+		err := rows.Scan(
+			&div.ID, &div.Ticker, &div.ExDividendDate, &div.PaymentDate,
+			&div.AmountPerShare, &div.DividendYield, &div.PayoutRatio, &div.Currency, &div.RecordDate,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan dividend: %w", err)
@@ -64,7 +101,7 @@ func (r *DividendsRepository) GetByID(ctx context.Context, id int) (*domain.Divi
 
 	query := `
 		SELECT id, ticker, ex_dividend_date, payment_date, amount_per_share,
-		       dividend_yield, payout_ratio, currency
+		       dividend_yield, payout_ratio, currency, record_date
 		FROM dividends
 		WHERE id = $1
 	`
@@ -72,7 +109,7 @@ func (r *DividendsRepository) GetByID(ctx context.Context, id int) (*domain.Divi
 	div := &domain.Dividends{}
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&div.ID, &div.Ticker, &div.ExDividendDate, &div.PaymentDate,
-		&div.AmountPerShare, &div.DividendYield, &div.PayoutRatio, &div.Currency,
+		&div.AmountPerShare, &div.DividendYield, &div.PayoutRatio, &div.Currency, &div.RecordDate,
 	)
 
 	if err != nil {
@@ -95,14 +132,14 @@ func (r *DividendsRepository) Create(ctx context.Context, dividend *domain.Divid
 
 	query := `
 		INSERT INTO dividends (ticker, ex_dividend_date, payment_date, amount_per_share,
-		                       dividend_yield, payout_ratio, currency)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		                       dividend_yield, payout_ratio, currency, record_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 
 	err := r.pool.QueryRow(ctx, query,
 		dividend.Ticker, dividend.ExDividendDate, dividend.PaymentDate,
-		dividend.AmountPerShare, dividend.DividendYield, dividend.PayoutRatio, dividend.Currency,
+		dividend.AmountPerShare, dividend.DividendYield, dividend.PayoutRatio, dividend.Currency, dividend.RecordDate,
 	).Scan(&dividend.ID)
 
 	if err != nil {
@@ -112,6 +149,41 @@ func (r *DividendsRepository) Create(ctx context.Context, dividend *domain.Divid
 	return nil
 }
 
+// Upsert stores a dividend by ticker and ex-date. A stored dividend keeps its
+// payment date and payout ratio, and its yield and record date unless new ones
+// are given. inserted reports whether the dividend was not stored before.
+func (r *DividendsRepository) Upsert(ctx context.Context, dividend *domain.Dividends) (inserted bool, err error) {
+	if dividend == nil {
+		return false, fmt.Errorf("dividend is nil: %w", domain.ErrInvalidInput)
+	}
+	if dividend.Ticker == "" {
+		return false, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+
+	query := `
+		INSERT INTO dividends (ticker, ex_dividend_date, payment_date, amount_per_share,
+		                       dividend_yield, payout_ratio, currency, record_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (ticker, ex_dividend_date) DO UPDATE SET
+			amount_per_share = EXCLUDED.amount_per_share,
+			currency = EXCLUDED.currency,
+			dividend_yield = COALESCE(EXCLUDED.dividend_yield, dividends.dividend_yield),
+			record_date = COALESCE(EXCLUDED.record_date, dividends.record_date)
+		RETURNING id, (xmax = 0)
+	`
+
+	err = r.pool.QueryRow(ctx, query,
+		dividend.Ticker, dividend.ExDividendDate, dividend.PaymentDate,
+		dividend.AmountPerShare, dividend.DividendYield, dividend.PayoutRatio, dividend.Currency, dividend.RecordDate,
+	).Scan(&dividend.ID, &inserted)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to upsert dividend: %w", err)
+	}
+
+	return inserted, nil
+}
+
 func (r *DividendsRepository) Update(ctx context.Context, id int, dividend *domain.Dividends) error {
 	if id < 1 {
 		return fmt.Errorf("invalid dividend ID: %d: %w", id, domain.ErrInvalidInput)
@@ -123,13 +195,13 @@ func (r *DividendsRepository) Update(ctx context.Context, id int, dividend *doma
 	query := `
 		UPDATE dividends SET
 			ex_dividend_date = $2, payment_date = $3, amount_per_share = $4,
-			dividend_yield = $5, payout_ratio = $6, currency = $7
+			dividend_yield = $5, payout_ratio = $6, currency = $7, record_date = $8
 		WHERE id = $1
 	`
 
 	result, err := r.pool.Exec(ctx, query,
 		id, dividend.ExDividendDate, dividend.PaymentDate,
-		dividend.AmountPerShare, dividend.DividendYield, dividend.PayoutRatio, dividend.Currency,
+		dividend.AmountPerShare, dividend.DividendYield, dividend.PayoutRatio, dividend.Currency, dividend.RecordDate,
 	)
 
 	if err != nil {
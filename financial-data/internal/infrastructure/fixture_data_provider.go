@@ -0,0 +1,230 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FixtureDataProvider serves market data from ISS JSON responses recorded on disk,
+// so the service can run without access to iss.moex.com. Layout of the directory:
+//
+//	<dir>/<TICKER>/description.json       - /iss/securities/<TICKER>.json response
+//	<dir>/<TICKER>/candles_<interval>.json - candles.json response with all recorded rows
+//	<dir>/<INDEX>/candles_<interval>.json  - the same for an index
+//	<dir>/<TICKER>/dividends.json         - /iss/securities/<TICKER>/dividends.json response
+//	<dir>/<TICKER>/board_securities.json  - securities of the primary board, for the lot size
+//	<dir>/freefloat.json                  - free-float coefficients of all securities, pages merged
+type FixtureDataProvider struct {
+	dir string
+}
+
+func NewFixtureDataProvider(dir string) *FixtureDataProvider {
+	return &FixtureDataProvider{dir: dir}
+}
+
+func (f *FixtureDataProvider) GetStockPriceRange(ticker string, from, till time.Time, interval domain.Period) ([]domain.Candle, error) {
+	candles, err := f.loadCandles(ticker, interval)
+	if err != nil {
+		return nil, err
+	}
+	return filterCandles(candles, from, till), nil
+}
+
+func (f *FixtureDataProvider) GetIndexRange(index string, from, till time.Time, interval domain.Period) ([]domain.Candle, error) {
+	return f.GetStockPriceRange(index, from, till, interval)
+}
+
+// GetStockPrice counts days back from the last recorded candle rather than from
+// today, so old fixtures keep returning data.
+func (f *FixtureDataProvider) GetStockPrice(ticker string, daysBackwards int, interval domain.Period) ([]domain.Candle, error) {
+	candles, err := f.loadCandles(ticker, interval)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return candles, nil
+	}
+
+	till, err := time.Parse(domain.CandleTimeLayout, candles[len(candles)-1].Begin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid candle begin in fixture: %w", err)
+	}
+	return filterCandles(candles, till.AddDate(0, 0, -daysBackwards), till), nil
+}
+
+func (f *FixtureDataProvider) GetPriceAt(ticker string, date time.Time) (float64, error) {
+	candles, err := f.loadCandles(ticker, domain.Period1D)
+	if err != nil {
+		return 0, err
+	}
+
+	candles = filterCandles(candles, date.AddDate(0, 0, -7), date)
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no candles found for %s at %s", ticker, date.Format("2006-01-02"))
+	}
+
+	return candles[len(candles)-1].Close, nil
+}
+
+func (f *FixtureDataProvider) GetStockInfo(ticker string) (*domain.StockInfo, error) {
+	path, err := fixtureDescriptionPath(f.dir, ticker)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no description fixture for %s: %w", ticker, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	stockInfo, err := unmarshalStockInfo(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// fixtures recorded before lot sizes were loaded have no board securities
+	path, err = fixtureBoardSecuritiesPath(f.dir, ticker)
+	if err != nil {
+		return nil, err
+	}
+	if body, err := os.ReadFile(path); err == nil {
+		var response domain.BoardSecuritiesApiResponse
+		if err := json.Unmarshal(body, &response); err == nil {
+			stockInfo.SetLotSize(response.Securities)
+		}
+	}
+
+	if body, err := os.ReadFile(fixtureFreeFloatPath(f.dir)); err == nil {
+		var response domain.FreeFloatApiResponse
+		if err := json.Unmarshal(body, &response); err == nil {
+			stockInfo.SetFreeFloat(response.FreeFloat)
+		}
+	}
+	return stockInfo, nil
+}
+
+// GetDividends returns no dividends for tickers recorded without them.
+func (f *FixtureDataProvider) GetDividends(ticker string) ([]domain.Dividends, error) {
+	path, err := fixtureDividendsPath(f.dir, ticker)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []domain.Dividends{}, nil
+		}
+		return nil, err
+	}
+	return unmarshalDividends(body)
+}
+
+// GetMarketCap uses the last hourly close and falls back to daily candles when
+// no hourly ones were recorded.
+func (f *FixtureDataProvider) GetMarketCap(ctx context.Context, ticker string) (float64, error) {
+	candles, err := f.loadCandles(ticker, domain.Period1H)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && len(candles) == 0) {
+		candles, err = f.loadCandles(ticker, domain.Period1D)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no candles recorded for %s", ticker)
+	}
+
+	stockInfo, err := f.GetStockInfo(ticker)
+	if err != nil {
+		return 0, err
+	}
+
+	return candles[len(candles)-1].Close * float64(stockInfo.NumberOfShares), nil
+}
+
+func (f *FixtureDataProvider) loadCandles(ticker string, interval domain.Period) ([]domain.Candle, error) {
+	path, err := fixtureCandlesPath(f.dir, ticker, interval)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no candles fixture for %s (interval %d): %w", ticker, interval, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return unmarshalCandles(body)
+}
+
+// filterCandles keeps candles that begin within [from, till], both dates inclusive.
+func filterCandles(candles []domain.Candle, from, till time.Time) []domain.Candle {
+	fromStr := from.Format("2006-01-02")
+	tillStr := till.Format("2006-01-02")
+
+	result := make([]domain.Candle, 0, len(candles))
+	for _, c := range candles {
+		if len(c.Begin) < 10 {
+			continue
+		}
+		day := c.Begin[:10]
+		if day >= fromStr && day <= tillStr {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+var fixtureTickerPattern = regexp.MustCompile(`^[A-Z0-9.-]+$`)
+
+// fixturePath returns the path of a fixture file of the ticker. Tickers come
+// from requests, so anything that could point outside dir is rejected.
+func fixturePath(dir, ticker, name string) (string, error) {
+	ticker = strings.ToUpper(ticker)
+	if ticker == "." || ticker == ".." || !fixtureTickerPattern.MatchString(ticker) {
+		return "", fmt.Errorf("invalid ticker %q for fixtures: %w", ticker, domain.ErrInvalidInput)
+	}
+	return filepath.Join(dir, ticker, name), nil
+}
+
+func fixtureCandlesPath(dir, ticker string, interval domain.Period) (string, error) {
+	return fixturePath(dir, ticker, fmt.Sprintf("candles_%d.json", int(interval)))
+}
+
+func fixtureDescriptionPath(dir, ticker string) (string, error) {
+	return fixturePath(dir, ticker, "description.json")
+}
+
+func fixtureBoardSecuritiesPath(dir, ticker string) (string, error) {
+	return fixturePath(dir, ticker, "board_securities.json")
+}
+
+func fixtureDividendsPath(dir, ticker string) (string, error) {
+	return fixturePath(dir, ticker, "dividends.json")
+}
+
+func fixtureFreeFloatPath(dir string) string {
+	return filepath.Join(dir, "freefloat.json")
+}
+
+// writeFixture writes through a temp file so readers never see a partial fixture.
+func writeFixture(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,149 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/domain"
+	"math"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testFixturesDir = "../../fixtures/moex"
+
+func fixtureDay(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFixtureDataProviderStockInfo(t *testing.T) {
+	f := NewFixtureDataProvider(testFixturesDir)
+
+	info, err := f.GetStockInfo("SBER")
+	if err != nil {
+		t.Fatalf("Failed to get stock info: %v", err)
+	}
+
+	if info.Ticker != "SBER" || info.ISIN != "RU0009029540" || info.ListLevel != 1 {
+		t.Errorf("Unexpected description: %+v", info)
+	}
+	if info.NumberOfShares != 21586948000 {
+		t.Errorf("Expected issue size 21586948000, got %d", info.NumberOfShares)
+	}
+	if info.Board != "TQBR" || info.Market != "shares" || info.Currency != "RUB" || !info.Traded {
+		t.Errorf("Expected primary board TQBR traded in RUB, got %+v", info)
+	}
+	if info.LotSize != 10 {
+		t.Errorf("Expected lot size 10, got %d", info.LotSize)
+	}
+	if info.FreeFloat == nil || *info.FreeFloat != 48 {
+		t.Errorf("Expected free float 48%%, got %v", info.FreeFloat)
+	}
+	// listed_till of the recorded board is in the past, but the board is traded
+	if info.Delisted() {
+		t.Error("Expected a traded security not to be delisted")
+	}
+
+	if _, err := f.GetStockInfo("GAZP"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for ticker without fixtures, got %v", err)
+	}
+}
+
+func TestFixtureDataProviderCandles(t *testing.T) {
+	f := NewFixtureDataProvider(testFixturesDir)
+
+	candles, err := f.GetStockPriceRange("SBER", fixtureDay("2025-01-06"), fixtureDay("2025-01-10"), domain.Period1D)
+	if err != nil {
+		t.Fatalf("Failed to get candles: %v", err)
+	}
+	if len(candles) != 5 {
+		t.Fatalf("Expected 5 daily candles in the first week, got %d", len(candles))
+	}
+	if candles[0].Begin != "2025-01-06 00:00:00" || candles[4].Close != 279.8 {
+		t.Errorf("Unexpected candles: first %s, last close %v", candles[0].Begin, candles[4].Close)
+	}
+
+	// days are counted back from the last recorded candle, not from today
+	recent, err := f.GetStockPrice("SBER", 7, domain.Period1D)
+	if err != nil {
+		t.Fatalf("Failed to get recent candles: %v", err)
+	}
+	if len(recent) != 6 || recent[len(recent)-1].Close != 301.1 {
+		t.Errorf("Expected 6 candles up to the last close 301.1, got %d", len(recent))
+	}
+
+	// a weekend takes the Friday close
+	price, err := f.GetPriceAt("SBER", fixtureDay("2025-01-12"))
+	if err != nil {
+		t.Fatalf("Failed to get price: %v", err)
+	}
+	if price != 279.8 {
+		t.Errorf("Expected Friday close 279.8, got %v", price)
+	}
+
+	index, err := f.GetIndexRange(domain.IndexIMOEX, fixtureDay("2025-01-01"), fixtureDay("2025-12-31"), domain.Period1D)
+	if err != nil {
+		t.Fatalf("Failed to get index candles: %v", err)
+	}
+	if len(index) != 20 {
+		t.Errorf("Expected 20 IMOEX candles, got %d", len(index))
+	}
+
+	if _, err := f.GetStockPriceRange("SBER", fixtureDay("2025-01-06"), fixtureDay("2025-01-10"), domain.Period1W); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for interval without fixture, got %v", err)
+	}
+}
+
+func TestFixtureDataProviderMarketCapAndDividends(t *testing.T) {
+	f := NewFixtureDataProvider(testFixturesDir)
+
+	// no hourly candles are recorded, so the last daily close is used
+	marketCap, err := f.GetMarketCap(context.Background(), "SBER")
+	if err != nil {
+		t.Fatalf("Failed to get market cap: %v", err)
+	}
+	if expected := 301.1 * 21586948000; math.Abs(marketCap-expected) > 1 {
+		t.Errorf("Expected market cap %v, got %v", expected, marketCap)
+	}
+
+	dividends, err := f.GetDividends("SBER")
+	if err != nil {
+		t.Fatalf("Failed to get dividends: %v", err)
+	}
+	if len(dividends) != 2 {
+		t.Fatalf("Expected 2 paid dividends, got %d", len(dividends))
+	}
+	if dividends[1].AmountPerShare != 33.3 || dividends[1].Currency != "RUB" {
+		t.Errorf("Unexpected dividend: %+v", dividends[1])
+	}
+
+	none, err := f.GetDividends("IMOEX")
+	if err != nil || len(none) != 0 {
+		t.Errorf("Expected no dividends without fixture, got %v, %v", none, err)
+	}
+}
+
+func TestFixturesRejectPathsOutsideDir(t *testing.T) {
+	f := NewFixtureDataProvider(testFixturesDir)
+	for _, ticker := range []string{"..", ".", "../SBER", "SBER/../IMOEX", "", "SBER\x00"} {
+		if _, err := f.GetStockInfo(ticker); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for ticker %q, got %v", ticker, err)
+		}
+	}
+
+	recorder := newRecordingTransport(t.TempDir())
+	for _, path := range []string{
+		"/iss/securities/...json",
+		"/iss/engines/stock/markets/shares/securities/../candles.json",
+		"/iss/securities/../dividends.json",
+	} {
+		err := recorder.record(&url.URL{Path: path, RawQuery: "interval=24"}, []byte(`{}`))
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput when recording %s, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// recordingTransport passes ISS requests through and saves successful candles,
+// description, board securities and dividends responses into a fixture directory readable by FixtureDataProvider.
+// Candle pages are merged into one file per ticker and interval.
+type recordingTransport struct {
+	dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+}
+
+func newRecordingTransport(dir string) *recordingTransport {
+	return &recordingTransport{dir: dir, next: http.DefaultTransport}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := t.record(req.URL, body); err != nil {
+		slog.Warn("failed to record MOEX response", slog.String("url", req.URL.String()), slog.Any("err", err))
+	}
+
+	return resp, nil
+}
+
+func (t *recordingTransport) record(u *url.URL, body []byte) error {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 {
+		return nil
+	}
+	last := segments[len(segments)-1]
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var path string
+	var err error
+	switch {
+	case last == "candles.json":
+		interval, err := strconv.Atoi(u.Query().Get("interval"))
+		if err != nil {
+			return fmt.Errorf("invalid interval in candles request: %w", err)
+		}
+		path, err := fixtureCandlesPath(t.dir, segments[len(segments)-2], domain.Period(interval))
+		if err != nil {
+			return err
+		}
+		return mergeCandlesFixture(path, body)
+	case last == "freefloat.json":
+		return mergeFreeFloatFixture(fixtureFreeFloatPath(t.dir), body, u.Query().Get("start"))
+	case last == "dividends.json":
+		path, err = fixtureDividendsPath(t.dir, segments[len(segments)-2])
+	case len(segments) > 4 && segments[len(segments)-2] == "securities" && segments[len(segments)-4] == "boards":
+		path, err = fixtureBoardSecuritiesPath(t.dir, strings.TrimSuffix(last, ".json"))
+	case segments[len(segments)-2] == "securities" && strings.HasSuffix(last, ".json"):
+		path, err = fixtureDescriptionPath(t.dir, strings.TrimSuffix(last, ".json"))
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return writeFixture(path, body)
+}
+
+// mergeFreeFloatFixture starts the fixture over with the first page of the
+// free-float table and appends the rows of the next ones.
+func mergeFreeFloatFixture(path string, body []byte, start string) error {
+	var incoming domain.FreeFloatApiResponse
+	if err := json.Unmarshal(body, &incoming); err != nil {
+		return err
+	}
+	if start == "" || start == "0" {
+		return writeFixture(path, body)
+	}
+	if len(incoming.FreeFloat.Data) == 0 {
+		return nil
+	}
+
+	var stored domain.FreeFloatApiResponse
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(existing, &stored); err != nil {
+		return fmt.Errorf("corrupted fixture %s: %w", path, err)
+	}
+	stored.FreeFloat.Data = append(stored.FreeFloat.Data, incoming.FreeFloat.Data...)
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return writeFixture(path, data)
+}
+
+// mergeCandlesFixture adds the rows of a candles response to the fixture file,
+// replacing rows with the same begin and keeping them ordered by begin.
+func mergeCandlesFixture(path string, body []byte) error {
+	var incoming domain.CandlesApiResponse
+	if err := json.Unmarshal(body, &incoming); err != nil {
+		return err
+	}
+
+	var stored domain.CandlesApiResponse
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return fmt.Errorf("corrupted fixture %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	rows := make(map[string][]any, len(stored.Candles.Data)+len(incoming.Candles.Data))
+	for _, row := range append(stored.Candles.Data, incoming.Candles.Data...) {
+		if len(row) != 8 {
+			continue
+		}
+		begin, ok := row[6].(string)
+		if !ok {
+			continue
+		}
+		rows[begin] = row
+	}
+
+	begins := make([]string, 0, len(rows))
+	for begin := range rows {
+		begins = append(begins, begin)
+	}
+	sort.Strings(begins)
+
+	merged := domain.CandlesApiResponse{}
+	merged.Candles.Columns = incoming.Candles.Columns
+	if len(merged.Candles.Columns) == 0 {
+		merged.Candles.Columns = stored.Candles.Columns
+	}
+	merged.Candles.Data = make([][]any, 0, len(begins))
+	for _, begin := range begins {
+		merged.Candles.Data = append(merged.Candles.Data, rows[begin])
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return writeFixture(path, data)
+}
@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const jobsLeaseKey = "financial-data:jobs:leader"
+
+// acquireLeaseScript extends the lease when it is already held by this
+// instance and takes it over only when nobody holds it.
+var acquireLeaseScript = redis.NewScript(`
+local owner = redis.call("GET", KEYS[1])
+if owner == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if owner == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`)
+
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// JobsLease is the Redis lease the replica running the background jobs holds.
+type JobsLease struct {
+	rdb *redis.Client
+	id  string
+	ttl time.Duration
+}
+
+func NewJobsLease(rdb *redis.Client, id string, ttl time.Duration) *JobsLease {
+	return &JobsLease{rdb: rdb, id: id, ttl: ttl}
+}
+
+func (l *JobsLease) Acquire(ctx context.Context) (bool, error) {
+	res, err := acquireLeaseScript.Run(ctx, l.rdb, []string{jobsLeaseKey}, l.id, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("acquire jobs lease: %w", err)
+	}
+	return res == 1, nil
+}
+
+func (l *JobsLease) Release(ctx context.Context) error {
+	if err := releaseLeaseScript.Run(ctx, l.rdb, []string{jobsLeaseKey}, l.id).Err(); err != nil {
+		return fmt.Errorf("release jobs lease: %w", err)
+	}
+	return nil
+}
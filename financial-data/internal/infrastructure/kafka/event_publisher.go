@@ -3,7 +3,9 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"financial_data/internal/domain"
 	"fmt"
+	"time"
 )
 
 type CompanyCreatedEvent struct {
@@ -18,13 +20,49 @@ type AITask struct {
 	Type   string `json:"type"`
 }
 
+// DividendAnnouncedEvent is published to the events topic when a dividend not
+// stored before shows up.
+type DividendAnnouncedEvent struct {
+	Type           string    `json:"type"`
+	Ticker         string    `json:"ticker"`
+	ExDividendDate string    `json:"exDividendDate"`
+	LastBuyDate    string    `json:"lastBuyDate"`
+	RecordDate     *string   `json:"recordDate,omitempty"`
+	AmountPerShare float64   `json:"amountPerShare"`
+	Currency       string    `json:"currency"`
+	DividendYield  *float64  `json:"dividendYield,omitempty"`
+	PublishedAt    time.Time `json:"publishedAt"`
+}
+
+// KeyRateChangedEvent is published to the events topic when a new key rate
+// comes into force. Valuations discounted with the key rate are stale after it.
+type KeyRateChangedEvent struct {
+	Type         string    `json:"type"`
+	Date         string    `json:"date"`
+	Rate         float64   `json:"rate"`
+	PreviousRate float64   `json:"previousRate"`
+	PublishedAt  time.Time `json:"publishedAt"`
+}
+
+// CompanyDelistedEvent is published to the events topic when the refresh from
+// ISS finds a company no longer traded on its board.
+type CompanyDelistedEvent struct {
+	Type        string    `json:"type"`
+	Ticker      string    `json:"ticker"`
+	Name        string    `json:"name"`
+	Board       string    `json:"board"`
+	DelistedAt  string    `json:"delistedAt"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
 type KafkaEventPublisher struct {
-	producer   *Producer
-	aiProducer *Producer
+	producer       *Producer
+	aiProducer     *Producer
+	eventsProducer *Producer
 }
 
-func NewKafkaEventPublisher(producer *Producer, aiProducer *Producer) *KafkaEventPublisher {
-	return &KafkaEventPublisher{producer: producer, aiProducer: aiProducer}
+func NewKafkaEventPublisher(producer *Producer, aiProducer *Producer, eventsProducer *Producer) *KafkaEventPublisher {
+	return &KafkaEventPublisher{producer: producer, aiProducer: aiProducer, eventsProducer: eventsProducer}
 }
 
 func (p *KafkaEventPublisher) PublishCompanyCreated(ctx context.Context, ticker, name, id string) error {
@@ -71,3 +109,64 @@ func (p *KafkaEventPublisher) PublishExpectRiskAndGrowthAnalysis(ctx context.Con
 
 	return p.aiProducer.Publish(ctx, []byte(ticker), value)
 }
+
+func (p *KafkaEventPublisher) PublishDividendAnnounced(ctx context.Context, dividend domain.Dividends) error {
+	event := DividendAnnouncedEvent{
+		Type:           "dividend-announced",
+		Ticker:         dividend.Ticker,
+		ExDividendDate: dividend.ExDividendDate.Format("2006-01-02"),
+		LastBuyDate:    dividend.LastBuyDate().Format("2006-01-02"),
+		AmountPerShare: dividend.AmountPerShare,
+		Currency:       dividend.Currency,
+		DividendYield:  dividend.DividendYield,
+		PublishedAt:    time.Now().UTC(),
+	}
+	if dividend.RecordDate != nil {
+		recordDate := dividend.RecordDate.Format("2006-01-02")
+		event.RecordDate = &recordDate
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal dividend announced event: %w", err)
+	}
+
+	return p.eventsProducer.Publish(ctx, []byte(dividend.Ticker), value)
+}
+
+func (p *KafkaEventPublisher) PublishKeyRateChanged(ctx context.Context, rate, previous domain.CBRate) error {
+	event := KeyRateChangedEvent{
+		Type:         "key-rate-changed",
+		Date:         rate.Date.Format("2006-01-02"),
+		Rate:         rate.Rate,
+		PreviousRate: previous.Rate,
+		PublishedAt:  time.Now().UTC(),
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal key rate changed event: %w", err)
+	}
+
+	return p.eventsProducer.Publish(ctx, []byte(string(domain.SeriesKeyRate)), value)
+}
+
+func (p *KafkaEventPublisher) PublishCompanyDelisted(ctx context.Context, company domain.Company) error {
+	event := CompanyDelistedEvent{
+		Type:        "company-delisted",
+		Ticker:      company.Ticker,
+		Name:        company.Name,
+		Board:       company.Board,
+		PublishedAt: time.Now().UTC(),
+	}
+	if company.DelistedAt != nil {
+		event.DelistedAt = company.DelistedAt.Format("2006-01-02")
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal company delisted event: %w", err)
+	}
+
+	return p.eventsProducer.Publish(ctx, []byte(company.Ticker), value)
+}
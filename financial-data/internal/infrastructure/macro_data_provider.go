@@ -0,0 +1,331 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"financial_data/internal/domain"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cbrCurrencyCodes are the Bank of Russia codes of currencies with official rates.
+var cbrCurrencyCodes = map[domain.MacroSeries]string{
+	domain.SeriesUSDRUB: "R01235",
+	domain.SeriesCNYRUB: "R01375",
+}
+
+var (
+	cbrTableRow  = regexp.MustCompile(`(?s)<tr[^>]*>(.*?)</tr>`)
+	cbrTableCell = regexp.MustCompile(`(?s)<td[^>]*>(.*?)</td>`)
+	htmlTag      = regexp.MustCompile(`<[^>]+>`)
+)
+
+// MacroDataProvider loads the key rate, inflation, RUONIA and official currency
+// rates from the Bank of Russia and the zero-coupon OFZ curve from MOEX ISS. The
+// key rate and RUONIA come from the DailyInfo web service; inflation has no
+// machine-readable export, so it is read from the table of the cbr.ru database page.
+type MacroDataProvider struct {
+	cbrUrl       string
+	dailyInfoUrl string
+	issUrl       string
+	client       http.Client
+}
+
+func NewMacroDataProvider() *MacroDataProvider {
+	return &MacroDataProvider{
+		cbrUrl:       "https://www.cbr.ru",
+		dailyInfoUrl: "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx",
+		issUrl:       "https://iss.moex.com/iss",
+		client: http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+type cbrKeyRates struct {
+	Records []struct {
+		Date string `xml:"DT"`
+		Rate string `xml:"Rate"`
+	} `xml:"Body>KeyRateXMLResponse>KeyRateXMLResult>KeyRate>KR"`
+}
+
+func (p *MacroDataProvider) GetKeyRates(from, till time.Time) ([]domain.CBRate, error) {
+	var response cbrKeyRates
+	if err := p.dailyInfo("KeyRateXML", from, till, &response); err != nil {
+		return nil, err
+	}
+
+	rates := make([]domain.CBRate, 0, len(response.Records))
+	for _, record := range response.Records {
+		date, err := parseDailyInfoDate(record.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key rate date %q: %w", record.Date, err)
+		}
+		rate, err := parseCBRNumber(record.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key rate %q on %s: %w", record.Rate, record.Date, err)
+		}
+		rates = append(rates, domain.CBRate{Date: date, Rate: rate})
+	}
+
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Date.Before(rates[j].Date) })
+	return rates, nil
+}
+
+func (p *MacroDataProvider) GetSeries(series domain.MacroSeries, from, till time.Time) ([]domain.MacroPoint, error) {
+	var points []domain.MacroPoint
+	var err error
+	switch series {
+	case domain.SeriesCPI:
+		points, err = p.getInflation(from, till)
+	case domain.SeriesRUONIA:
+		points, err = p.getRuonia(from, till)
+	case domain.SeriesUSDRUB, domain.SeriesCNYRUB:
+		points, err = p.getCurrencyRate(series, from, till)
+	default:
+		return nil, fmt.Errorf("series %s is not loaded from cbr.ru: %w", series, domain.ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].Date.Before(points[j].Date) })
+	return points, nil
+}
+
+func (p *MacroDataProvider) GetYieldCurve(date time.Time) ([]domain.MacroPoint, error) {
+	body, err := p.get(fmt.Sprintf("%s/engines/stock/zcyc.json?date=%s&iss.only=yearyields&iss.meta=off",
+		p.issUrl, date.Format("2006-01-02")))
+	if err != nil {
+		return nil, err
+	}
+
+	var response domain.YieldCurveApiResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	return domain.ParseYieldCurve(response.YearYields), nil
+}
+
+// getInflation reads the monthly table of inflation against the key rate and
+// the target; inflation is its third column.
+func (p *MacroDataProvider) getInflation(from, till time.Time) ([]domain.MacroPoint, error) {
+	rows, err := p.cbrTable(fmt.Sprintf("%s/hd_base/infl/?UniDbQuery.Posted=True&UniDbQuery.From=%s&UniDbQuery.To=%s",
+		p.cbrUrl, from.Format("01.2006"), till.Format("01.2006")))
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]domain.MacroPoint, 0, len(rows))
+	skipped := 0
+	for _, row := range rows {
+		if len(row) < 3 {
+			skipped++
+			continue
+		}
+		month, err := time.Parse("01.2006", row[0])
+		if err != nil {
+			skipped++
+			continue
+		}
+		value, err := parseCBRNumber(row[2])
+		if err != nil {
+			skipped++
+			continue
+		}
+		points = append(points, domain.MacroPoint{Series: domain.SeriesCPI, Date: month.AddDate(0, 1, -1), Value: value})
+	}
+	warnSkipped(domain.SeriesCPI, skipped, len(points))
+	return points, nil
+}
+
+type cbrRuonia struct {
+	Records []struct {
+		Date string `xml:"D0"`
+		Rate string `xml:"ruo"`
+	} `xml:"Body>RuoniaXMLResponse>RuoniaXMLResult>Ruonia>ro"`
+}
+
+func (p *MacroDataProvider) getRuonia(from, till time.Time) ([]domain.MacroPoint, error) {
+	var response cbrRuonia
+	if err := p.dailyInfo("RuoniaXML", from, till, &response); err != nil {
+		return nil, err
+	}
+
+	points := make([]domain.MacroPoint, 0, len(response.Records))
+	skipped := 0
+	for _, record := range response.Records {
+		date, err := parseDailyInfoDate(record.Date)
+		if err != nil {
+			skipped++
+			continue
+		}
+		value, err := parseCBRNumber(record.Rate)
+		if err != nil {
+			skipped++
+			continue
+		}
+		points = append(points, domain.MacroPoint{Series: domain.SeriesRUONIA, Date: date, Value: value})
+	}
+	warnSkipped(domain.SeriesRUONIA, skipped, len(points))
+	return points, nil
+}
+
+type cbrCurrencyDynamics struct {
+	Records []struct {
+		Date    string `xml:"Date,attr"`
+		Nominal string `xml:"Nominal"`
+		Value   string `xml:"Value"`
+	} `xml:"Record"`
+}
+
+// getCurrencyRate returns official rates per one unit of the currency; some
+// currencies were quoted per ten units in the past.
+func (p *MacroDataProvider) getCurrencyRate(series domain.MacroSeries, from, till time.Time) ([]domain.MacroPoint, error) {
+	body, err := p.get(fmt.Sprintf("%s/scripts/XML_dynamic.asp?date_req1=%s&date_req2=%s&VAL_NM_RQ=%s",
+		p.cbrUrl, from.Format("02/01/2006"), till.Format("02/01/2006"), cbrCurrencyCodes[series]))
+	if err != nil {
+		return nil, err
+	}
+
+	var dynamics cbrCurrencyDynamics
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// the response is declared as windows-1251, but the fields read here are ASCII
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := decoder.Decode(&dynamics); err != nil {
+		return nil, fmt.Errorf("failed to decode currency rates: %w", err)
+	}
+
+	points := make([]domain.MacroPoint, 0, len(dynamics.Records))
+	skipped := 0
+	for _, record := range dynamics.Records {
+		date, err := time.Parse("02.01.2006", record.Date)
+		if err != nil {
+			skipped++
+			continue
+		}
+		value, err := parseCBRNumber(record.Value)
+		if err != nil {
+			skipped++
+			continue
+		}
+		nominal, err := parseCBRNumber(record.Nominal)
+		if err != nil || nominal <= 0 {
+			nominal = 1
+		}
+		points = append(points, domain.MacroPoint{Series: series, Date: date, Value: value / nominal})
+	}
+	warnSkipped(series, skipped, len(points))
+	return points, nil
+}
+
+// cbrTable returns the text of the body cells of every table row on a cbr.ru page.
+func (p *MacroDataProvider) cbrTable(url string) ([][]string, error) {
+	body, err := p.get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range cbrTableRow.FindAllStringSubmatch(string(body), -1) {
+		cells := cbrTableCell.FindAllStringSubmatch(row[1], -1)
+		if len(cells) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(cells))
+		for _, cell := range cells {
+			values = append(values, strings.TrimSpace(html.UnescapeString(htmlTag.ReplaceAllString(cell[1], ""))))
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// dailyInfo calls a method of the Bank of Russia DailyInfo SOAP service that
+// takes a date range and decodes the response envelope into result.
+func (p *MacroDataProvider) dailyInfo(method string, from, till time.Time, result any) error {
+	envelope := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <%[1]s xmlns="http://web.cbr.ru/">
+      <fromDate>%[2]s</fromDate>
+      <ToDate>%[3]s</ToDate>
+    </%[1]s>
+  </soap:Body>
+</soap:Envelope>`, method, from.Format("2006-01-02"), till.Format("2006-01-02"))
+
+	req, err := http.NewRequest(http.MethodPost, p.dailyInfoUrl, strings.NewReader(envelope))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"http://web.cbr.ru/%s"`, method))
+
+	body, err := p.do(req)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	return nil
+}
+
+func (p *MacroDataProvider) get(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.do(req)
+}
+
+func (p *MacroDataProvider) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; trade-compass/1.0)")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status code: %d", req.URL.Host, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// warnSkipped reports rows of a response that could not be parsed, so that a
+// changed format shows up in the logs rather than as a shorter series.
+func warnSkipped(series domain.MacroSeries, skipped, parsed int) {
+	if skipped == 0 {
+		return
+	}
+	slog.Warn("skipped unparsable macro data rows", "series", series, "skipped", skipped, "parsed", parsed)
+}
+
+// parseDailyInfoDate reads dates of the DailyInfo service, e.g.
+// 2024-07-29T00:00:00+03:00, as the calendar date in Moscow.
+func parseDailyInfoDate(s string) (time.Time, error) {
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// parseCBRNumber reads numbers formatted the Russian way: decimal comma and
+// spaces between thousands.
+func parseCBRNumber(s string) (float64, error) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(strings.TrimSpace(s))
+	return strconv.ParseFloat(s, 64)
+}
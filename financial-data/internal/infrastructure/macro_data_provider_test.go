@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"financial_data/internal/domain"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const keyRateResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+  <soap:Body>
+    <KeyRateXMLResponse xmlns="http://web.cbr.ru/">
+      <KeyRateXMLResult>
+        <KeyRate xmlns="">
+          <KR><DT>2024-07-29T00:00:00+03:00</DT><Rate>18.00</Rate></KR>
+          <KR><DT>2024-07-26T00:00:00+03:00</DT><Rate>16.00</Rate></KR>
+        </KeyRate>
+      </KeyRateXMLResult>
+    </KeyRateXMLResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+const ruoniaResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <RuoniaXMLResponse xmlns="http://web.cbr.ru/">
+      <RuoniaXMLResult>
+        <Ruonia xmlns="">
+          <ro><D0>2024-07-29T00:00:00+03:00</D0><ruo>17.81</ruo><vol>512.3</vol></ro>
+          <ro><D0>2024-07-30T00:00:00+03:00</D0><ruo>n/a</ruo><vol>498.1</vol></ro>
+        </Ruonia>
+      </RuoniaXMLResult>
+    </RuoniaXMLResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+// dailyInfoServer answers DailyInfo calls with the response of the method named
+// in the SOAPAction header.
+func dailyInfoServer(t *testing.T, responses map[string]string) *MacroDataProvider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		action := strings.Trim(r.Header.Get("SOAPAction"), `"`)
+		method := strings.TrimPrefix(action, "http://web.cbr.ru/")
+		response, ok := responses[method]
+		if r.Method != http.MethodPost || !ok || !strings.Contains(string(body), "<"+method+" ") {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	p := NewMacroDataProvider()
+	p.dailyInfoUrl = server.URL
+	return p
+}
+
+func TestMacroDataProviderKeyRates(t *testing.T) {
+	p := dailyInfoServer(t, map[string]string{"KeyRateXML": keyRateResponse})
+
+	rates, err := p.GetKeyRates(fixtureDay("2024-07-26"), fixtureDay("2024-07-29"))
+	if err != nil {
+		t.Fatalf("Failed to get key rates: %v", err)
+	}
+
+	if len(rates) != 2 {
+		t.Fatalf("Expected 2 rates, got %d", len(rates))
+	}
+	if !rates[0].Date.Equal(fixtureDay("2024-07-26")) || rates[0].Rate != 16 {
+		t.Errorf("Expected 16%% on 2024-07-26 first, got %+v", rates[0])
+	}
+	if !rates[1].Date.Equal(fixtureDay("2024-07-29")) || rates[1].Rate != 18 {
+		t.Errorf("Expected 18%% on 2024-07-29, got %+v", rates[1])
+	}
+}
+
+func TestMacroDataProviderRuoniaSkipsUnparsableRows(t *testing.T) {
+	p := dailyInfoServer(t, map[string]string{"RuoniaXML": ruoniaResponse})
+
+	points, err := p.GetSeries(domain.SeriesRUONIA, fixtureDay("2024-07-29"), fixtureDay("2024-07-30"))
+	if err != nil {
+		t.Fatalf("Failed to get RUONIA: %v", err)
+	}
+
+	if len(points) != 1 {
+		t.Fatalf("Expected 1 point, got %d", len(points))
+	}
+	if !points[0].Date.Equal(fixtureDay("2024-07-29")) || points[0].Value != 17.81 || points[0].Series != domain.SeriesRUONIA {
+		t.Errorf("Unexpected point: %+v", points[0])
+	}
+}
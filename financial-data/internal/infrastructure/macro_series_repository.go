@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MacroSeriesRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMacroSeriesRepository(pool *pgxpool.Pool) *MacroSeriesRepository {
+	return &MacroSeriesRepository{pool: pool}
+}
+
+// GetRange returns points of the series dated within [from, till], earliest first.
+func (r *MacroSeriesRepository) GetRange(ctx context.Context, series domain.MacroSeries, from, till time.Time) ([]domain.MacroPoint, error) {
+	query := `
+		SELECT series, date, value
+		FROM macro_series
+		WHERE series = $1 AND date >= $2 AND date <= $3
+		ORDER BY date
+	`
+
+	rows, err := r.pool.Query(ctx, query, series, from, till)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query macro series: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]domain.MacroPoint, 0)
+	for rows.Next() {
+		var p domain.MacroPoint
+		if err := rows.Scan(&p.Series, &p.Date, &p.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan macro point: %w", err)
+		}
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating macro series: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetAsOf returns the latest point of the series dated on or before date.
+func (r *MacroSeriesRepository) GetAsOf(ctx context.Context, series domain.MacroSeries, date time.Time) (*domain.MacroPoint, error) {
+	query := `
+		SELECT series, date, value
+		FROM macro_series
+		WHERE series = $1 AND date <= $2
+		ORDER BY date DESC
+		LIMIT 1
+	`
+
+	p := &domain.MacroPoint{}
+	err := r.pool.QueryRow(ctx, query, series, date).Scan(&p.Series, &p.Date, &p.Value)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("no %s values on or before %s: %w", series, date.Format("2006-01-02"), domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get macro point: %w", err)
+	}
+
+	return p, nil
+}
+
+// GetYieldCurve returns points of every OFZ series of the latest date on or
+// before date that has a curve stored.
+func (r *MacroSeriesRepository) GetYieldCurve(ctx context.Context, date time.Time) ([]domain.MacroPoint, error) {
+	query := `
+		SELECT series, date, value
+		FROM macro_series
+		WHERE series LIKE $1 AND date = (
+			SELECT MAX(date) FROM macro_series WHERE series LIKE $1 AND date <= $2
+		)
+	`
+
+	rows, err := r.pool.Query(ctx, query, domain.OFZSeriesPrefix+"%", date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query yield curve: %w", err)
+	}
+	defer rows.Close()
+
+	var points []domain.MacroPoint
+	for rows.Next() {
+		var p domain.MacroPoint
+		if err := rows.Scan(&p.Series, &p.Date, &p.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan yield curve point: %w", err)
+		}
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating yield curve: %w", err)
+	}
+
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no yield curve on or before %s: %w", date.Format("2006-01-02"), domain.ErrNotFound)
+	}
+
+	return points, nil
+}
+
+// GetLastDate returns the date of the latest stored point of the series.
+func (r *MacroSeriesRepository) GetLastDate(ctx context.Context, series domain.MacroSeries) (time.Time, error) {
+	query := `SELECT date FROM macro_series WHERE series = $1 ORDER BY date DESC LIMIT 1`
+
+	var date time.Time
+	err := r.pool.QueryRow(ctx, query, series).Scan(&date)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, fmt.Errorf("no %s values stored: %w", series, domain.ErrNotFound)
+		}
+		return time.Time{}, fmt.Errorf("failed to get last macro point: %w", err)
+	}
+
+	return date, nil
+}
+
+// Upsert stores points, overwriting values of the same series and date so that
+// revised figures replace preliminary ones.
+func (r *MacroSeriesRepository) Upsert(ctx context.Context, points []domain.MacroPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO macro_series (series, date, value)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (series, date) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_at = NOW()
+	`
+
+	batch := &pgx.Batch{}
+	for _, p := range points {
+		if p.Series == "" {
+			return fmt.Errorf("series is empty: %w", domain.ErrInvalidInput)
+		}
+		batch.Queue(query, p.Series, p.Date, p.Value)
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to upsert macro series: %w", err)
+	}
+
+	return nil
+}
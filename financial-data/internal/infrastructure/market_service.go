@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"financial_data/internal/domain"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	MarketProviderMoex    = "moex"
+	MarketProviderFixture = "fixture"
+	MarketProviderRecord  = "record"
+)
+
+// NewMarketService picks the market data provider by MARKET_PROVIDER:
+// moex (default) queries ISS, fixture reads recorded responses from
+// MARKET_FIXTURES_DIR and record queries ISS while saving responses there.
+func NewMarketService(redis *redis.Client, companies CompanyLookup) (domain.MarketService, error) {
+	provider := getEnvOrDefault("MARKET_PROVIDER", MarketProviderMoex)
+	dir := getEnvOrDefault("MARKET_FIXTURES_DIR", "fixtures/moex")
+
+	switch provider {
+	case MarketProviderMoex:
+		return NewMoexDataProvider(redis, companies), nil
+	case MarketProviderFixture:
+		return NewFixtureDataProvider(dir), nil
+	case MarketProviderRecord:
+		return NewRecordingMoexDataProvider(redis, companies, dir), nil
+	default:
+		return nil, fmt.Errorf("unknown MARKET_PROVIDER %q (allowed: moex, fixture, record)", provider)
+	}
+}
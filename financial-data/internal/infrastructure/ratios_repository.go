@@ -19,7 +19,7 @@ func NewRatiosRepository(pool *pgxpool.Pool) *RatiosRepository {
 }
 
 const ratiosSelectColumns = `
-	ticker, year, period,
+	ticker, year, period, ttm_through,
 	price_to_earnings, price_to_book, price_to_cash_flow, ev_to_ebitda, ev_to_sales, ev_to_fcf, peg,
 	roe, roa, roic, gross_profit_margin, operating_profit_margin, net_profit_margin,
 	current_ratio, quick_ratio,
@@ -27,12 +27,13 @@ const ratiosSelectColumns = `
 	income_quality, asset_turnover, inventory_turnover, receivables_turnover,
 	eps, book_value_per_share, cash_flow_per_share, dividend_per_share, dividend_yield, payout_ratio,
 	enterprise_value, market_cap, free_cash_flow, capex, ebitda, net_debt, working_capital,
-	revenue_growth, earnings_growth, ebitda_growth, fcf_growth
+	revenue_growth, earnings_growth, ebitda_growth, fcf_growth,
+	company_type, net_interest_margin, cost_of_risk, cost_to_income, fee_income_share
 `
 
 func ratiosScanTargets(r *domain.Ratios) []any {
 	return []any{
-		&r.Ticker, &r.Year, &r.Period,
+		&r.Ticker, &r.Year, &r.Period, &r.TTMThrough,
 		&r.PriceToEarnings, &r.PriceToBook, &r.PriceToCashFlow, &r.EVToEBITDA, &r.EVToSales, &r.EVToFCF, &r.PEG,
 		&r.ROE, &r.ROA, &r.ROIC, &r.GrossProfitMargin, &r.OperatingProfitMargin, &r.NetProfitMargin,
 		&r.CurrentRatio, &r.QuickRatio,
@@ -41,6 +42,7 @@ func ratiosScanTargets(r *domain.Ratios) []any {
 		&r.EPS, &r.BookValuePerShare, &r.CashFlowPerShare, &r.DividendPerShare, &r.DividendYield, &r.PayoutRatio,
 		&r.EnterpriseValue, &r.MarketCap, &r.FreeCashFlow, &r.CAPEX, &r.EBITDA, &r.NetDebt, &r.WorkingCapital,
 		&r.RevenueGrowth, &r.EarningsGrowth, &r.EBITDAGrowth, &r.FCFGrowth,
+		&r.CompanyType, &r.NetInterestMargin, &r.CostOfRisk, &r.CostToIncome, &r.FeeIncomeShare,
 	}
 }
 
@@ -52,7 +54,8 @@ const ratiosValueColumns = `
 	income_quality, asset_turnover, inventory_turnover, receivables_turnover,
 	eps, book_value_per_share, cash_flow_per_share, dividend_per_share, dividend_yield, payout_ratio,
 	enterprise_value, market_cap, free_cash_flow, capex, ebitda, net_debt, working_capital,
-	revenue_growth, earnings_growth, ebitda_growth, fcf_growth
+	revenue_growth, earnings_growth, ebitda_growth, fcf_growth,
+	company_type, net_interest_margin, cost_of_risk, cost_to_income, fee_income_share
 `
 
 func ratiosValueArgs(r *domain.Ratios) []any {
@@ -65,6 +68,7 @@ func ratiosValueArgs(r *domain.Ratios) []any {
 		r.EPS, r.BookValuePerShare, r.CashFlowPerShare, r.DividendPerShare, r.DividendYield, r.PayoutRatio,
 		r.EnterpriseValue, r.MarketCap, r.FreeCashFlow, r.CAPEX, r.EBITDA, r.NetDebt, r.WorkingCapital,
 		r.RevenueGrowth, r.EarningsGrowth, r.EBITDAGrowth, r.FCFGrowth,
+		r.CompanyType, r.NetInterestMargin, r.CostOfRisk, r.CostToIncome, r.FeeIncomeShare,
 	}
 }
 
@@ -73,7 +77,8 @@ func (r *RatiosRepository) GetByTickerAndPeriod(ctx context.Context, ticker stri
 		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
 	}
 
-	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 AND year = $2 AND period = $3`, ratiosSelectColumns)
+	// a TTM year has a row per report it ends at; the latest one is returned
+	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 AND year = $2 AND period = $3 ORDER BY ttm_through DESC LIMIT 1`, ratiosSelectColumns)
 
 	ratios := &domain.Ratios{}
 	err := r.pool.QueryRow(ctx, query, ticker, year, period).Scan(ratiosScanTargets(ratios)...)
@@ -92,7 +97,7 @@ func (r *RatiosRepository) GetLatestByTicker(ctx context.Context, ticker string)
 		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
 	}
 
-	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 ORDER BY year DESC, period DESC LIMIT 1`, ratiosSelectColumns)
+	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 AND period <> 'TTM' ORDER BY year DESC, period DESC LIMIT 1`, ratiosSelectColumns)
 
 	ratios := &domain.Ratios{}
 	err := r.pool.QueryRow(ctx, query, ticker).Scan(ratiosScanTargets(ratios)...)
@@ -106,12 +111,32 @@ func (r *RatiosRepository) GetLatestByTicker(ctx context.Context, ticker string)
 	return ratios, nil
 }
 
+// GetLatestTTM returns the most recent trailing twelve months ratios.
+func (r *RatiosRepository) GetLatestTTM(ctx context.Context, ticker string) (*domain.Ratios, error) {
+	if ticker == "" {
+		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 AND period = 'TTM' ORDER BY year DESC, ttm_through DESC LIMIT 1`, ratiosSelectColumns)
+
+	ratios := &domain.Ratios{}
+	err := r.pool.QueryRow(ctx, query, ticker).Scan(ratiosScanTargets(ratios)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("TTM ratios not found for ticker %s: %w", ticker, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get TTM ratios: %w", err)
+	}
+
+	return ratios, nil
+}
+
 func (r *RatiosRepository) GetHistoryByTicker(ctx context.Context, ticker string) ([]domain.Ratios, error) {
 	if ticker == "" {
 		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
 	}
 
-	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 ORDER BY year DESC, period DESC`, ratiosSelectColumns)
+	query := fmt.Sprintf(`SELECT %s FROM ratios WHERE ticker = $1 ORDER BY year DESC, period DESC, ttm_through DESC`, ratiosSelectColumns)
 
 	rows, err := r.pool.Query(ctx, query, ticker)
 	if err != nil {
@@ -138,7 +163,7 @@ func (r *RatiosRepository) GetBySector(ctx context.Context, sector domain.Sector
 
 	query := `
 		SELECT
-			'' AS ticker, 0 AS year, '' AS period,
+			'' AS ticker, 0 AS year, '' AS period, NULL AS ttm_through,
 			AVG(price_to_earnings), AVG(price_to_book), AVG(price_to_cash_flow), AVG(ev_to_ebitda), AVG(ev_to_sales), AVG(ev_to_fcf), AVG(peg),
 			AVG(roe), AVG(roa), AVG(roic), AVG(gross_profit_margin), AVG(operating_profit_margin), AVG(net_profit_margin),
 			AVG(current_ratio), AVG(quick_ratio),
@@ -146,11 +171,12 @@ func (r *RatiosRepository) GetBySector(ctx context.Context, sector domain.Sector
 			AVG(income_quality), AVG(asset_turnover), AVG(inventory_turnover), AVG(receivables_turnover),
 			AVG(eps), AVG(book_value_per_share), AVG(cash_flow_per_share), AVG(dividend_per_share), AVG(dividend_yield), AVG(payout_ratio),
 			AVG(enterprise_value), AVG(market_cap), AVG(free_cash_flow), AVG(capex), AVG(ebitda), AVG(net_debt), AVG(working_capital),
-			AVG(revenue_growth), AVG(earnings_growth), AVG(ebitda_growth), AVG(fcf_growth)
+			AVG(revenue_growth), AVG(earnings_growth), AVG(ebitda_growth), AVG(fcf_growth),
+			NULL, AVG(net_interest_margin), AVG(cost_of_risk), AVG(cost_to_income), AVG(fee_income_share)
 		FROM (
 			SELECT DISTINCT ON (ticker) *
 			FROM ratios
-			WHERE sector = $1
+			WHERE sector = $1 AND period <> 'TTM'
 			ORDER BY ticker, year DESC, period DESC
 		) AS latest
 	`
@@ -164,6 +190,48 @@ func (r *RatiosRepository) GetBySector(ctx context.Context, sector domain.Sector
 	return ratios, nil
 }
 
+// GetLatestBySector returns the latest TTM row of every company in the sector,
+// or without ttm the annual rows of the latest year any company reported, so
+// that companies are compared over the same kind of period.
+func (r *RatiosRepository) GetLatestBySector(ctx context.Context, sector domain.Sector, ttm bool) ([]domain.Ratios, error) {
+	if !sector.IsValid() {
+		return nil, fmt.Errorf("invalid sector: %d: %w", sector, domain.ErrInvalidInput)
+	}
+
+	periodFilter := "period = 'YEAR' AND year = (SELECT MAX(year) FROM ratios WHERE sector = $1 AND period = 'YEAR')"
+	if ttm {
+		periodFilter = "period = 'TTM'"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT ON (ticker) %s
+		FROM ratios
+		WHERE sector = $1 AND %s
+		ORDER BY ticker, year DESC, period DESC, ttm_through DESC
+	`, ratiosSelectColumns, periodFilter)
+
+	rows, err := r.pool.Query(ctx, query, sector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sector ratios: %w", err)
+	}
+	defer rows.Close()
+
+	var result []domain.Ratios
+	for rows.Next() {
+		var ratios domain.Ratios
+		if err := rows.Scan(ratiosScanTargets(&ratios)...); err != nil {
+			return nil, fmt.Errorf("failed to scan ratios row: %w", err)
+		}
+		result = append(result, ratios)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sector ratios: %w", err)
+	}
+
+	return result, nil
+}
+
 func (r *RatiosRepository) Create(ctx context.Context, sector domain.Sector, ratios *domain.Ratios) error {
 	if ratios == nil {
 		return fmt.Errorf("ratios is nil: %w", domain.ErrInvalidInput)
@@ -177,10 +245,10 @@ func (r *RatiosRepository) Create(ctx context.Context, sector domain.Sector, rat
 
 	query := `
 		INSERT INTO ratios (
-			ticker, year, period, sector,
+			ticker, year, period, sector, ttm_through,
 			` + ratiosValueColumns + `
 		) VALUES (
-			$1, $2, $3, $4,
+			$1, $2, $3, $4, $49,
 			$5, $6, $7, $8, $9, $10, $11,
 			$12, $13, $14, $15, $16, $17,
 			$18, $19,
@@ -188,12 +256,14 @@ func (r *RatiosRepository) Create(ctx context.Context, sector domain.Sector, rat
 			$23, $24, $25, $26,
 			$27, $28, $29, $30, $31, $32,
 			$33, $34, $35, $36, $37, $38, $39,
-			$40, $41, $42, $43
+			$40, $41, $42, $43,
+			$44, $45, $46, $47, $48
 		)
 	`
 
 	args := []any{ratios.Ticker, ratios.Year, ratios.Period, sector}
 	args = append(args, ratiosValueArgs(ratios)...)
+	args = append(args, ratios.TTMThrough)
 
 	_, err := r.pool.Exec(ctx, query, args...)
 	if err != nil {
@@ -220,12 +290,15 @@ func (r *RatiosRepository) Update(ctx context.Context, ratios *domain.Ratios) er
 			income_quality = $22, asset_turnover = $23, inventory_turnover = $24, receivables_turnover = $25,
 			eps = $26, book_value_per_share = $27, cash_flow_per_share = $28, dividend_per_share = $29, dividend_yield = $30, payout_ratio = $31,
 			enterprise_value = $32, market_cap = $33, free_cash_flow = $34, capex = $35, ebitda = $36, net_debt = $37, working_capital = $38,
-			revenue_growth = $39, earnings_growth = $40, ebitda_growth = $41, fcf_growth = $42
-		WHERE ticker = $1 AND year = $2 AND period = $3
+			revenue_growth = $39, earnings_growth = $40, ebitda_growth = $41, fcf_growth = $42,
+			company_type = $43, net_interest_margin = $44, cost_of_risk = $45, cost_to_income = $46, fee_income_share = $47,
+			ttm_through = $48
+		WHERE ticker = $1 AND year = $2 AND period = $3 AND ttm_through IS NOT DISTINCT FROM $48
 	`
 
 	args := []any{ratios.Ticker, ratios.Year, ratios.Period}
 	args = append(args, ratiosValueArgs(ratios)...)
+	args = append(args, ratios.TTMThrough)
 
 	result, err := r.pool.Exec(ctx, query, args...)
 	if err != nil {
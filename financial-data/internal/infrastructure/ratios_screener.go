@@ -0,0 +1,185 @@
+package infrastructure
+
+import (
+	"context"
+	"financial_data/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// ratiosFieldColumns maps the JSON names of domain.Ratios fields to columns of
+// the ratios table. Screener field names are only ever taken from this map, so
+// user input never reaches the query text.
+var ratiosFieldColumns = map[string]string{
+	"priceToEarnings":       "price_to_earnings",
+	"priceToBook":           "price_to_book",
+	"priceToCashFlow":       "price_to_cash_flow",
+	"evToEbitda":            "ev_to_ebitda",
+	"evToSales":             "ev_to_sales",
+	"evToFcf":               "ev_to_fcf",
+	"peg":                   "peg",
+	"roe":                   "roe",
+	"roa":                   "roa",
+	"roic":                  "roic",
+	"grossProfitMargin":     "gross_profit_margin",
+	"operatingProfitMargin": "operating_profit_margin",
+	"netProfitMargin":       "net_profit_margin",
+	"currentRatio":          "current_ratio",
+	"quickRatio":            "quick_ratio",
+	"netDebtToEbitda":       "net_debt_to_ebitda",
+	"debtToEquity":          "debt_to_equity",
+	"interestCoverageRatio": "interest_coverage_ratio",
+	"incomeQuality":         "income_quality",
+	"assetTurnover":         "asset_turnover",
+	"inventoryTurnover":     "inventory_turnover",
+	"receivablesTurnover":   "receivables_turnover",
+	"eps":                   "eps",
+	"bookValuePerShare":     "book_value_per_share",
+	"cashFlowPerShare":      "cash_flow_per_share",
+	"dividendPerShare":      "dividend_per_share",
+	"dividendYield":         "dividend_yield",
+	"payoutRatio":           "payout_ratio",
+	"enterpriseValue":       "enterprise_value",
+	"marketCap":             "market_cap",
+	"freeCashFlow":          "free_cash_flow",
+	"capex":                 "capex",
+	"ebitda":                "ebitda",
+	"netDebt":               "net_debt",
+	"workingCapital":        "working_capital",
+	"revenueGrowth":         "revenue_growth",
+	"earningsGrowth":        "earnings_growth",
+	"ebitdaGrowth":          "ebitda_growth",
+	"fcfGrowth":             "fcf_growth",
+	"netInterestMargin":     "net_interest_margin",
+	"costOfRisk":            "cost_of_risk",
+	"costToIncome":          "cost_to_income",
+	"feeIncomeShare":        "fee_income_share",
+}
+
+var screenerOperators = map[domain.ScreenerOp]string{
+	domain.OpLess:         "<",
+	domain.OpLessEqual:    "<=",
+	domain.OpGreater:      ">",
+	domain.OpGreaterEqual: ">=",
+	domain.OpEqual:        "=",
+	domain.OpNotEqual:     "<>",
+}
+
+// screenerQuery accumulates positional arguments while the filter tree is
+// translated into a WHERE clause.
+type screenerQuery struct {
+	args []any
+}
+
+func (q *screenerQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *screenerQuery) where(f *domain.ScreenerFilter) (string, error) {
+	switch {
+	case f.And != nil || f.Or != nil:
+		children, joiner := f.And, " AND "
+		if f.Or != nil {
+			children, joiner = f.Or, " OR "
+		}
+		parts := make([]string, 0, len(children))
+		for i := range children {
+			part, err := q.where(&children[i])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "(" + strings.Join(parts, joiner) + ")", nil
+	case f.Sectors != nil:
+		sectors := make([]int32, len(f.Sectors))
+		for i, s := range f.Sectors {
+			sectors[i] = int32(s)
+		}
+		return "sector = ANY(" + q.arg(sectors) + ")", nil
+	default:
+		column, ok := ratiosFieldColumns[f.Field]
+		if !ok {
+			return "", fmt.Errorf("unknown field %q: %w", f.Field, domain.ErrInvalidInput)
+		}
+		op, ok := screenerOperators[f.Op]
+		if !ok {
+			return "", fmt.Errorf("invalid op %q: %w", f.Op, domain.ErrInvalidInput)
+		}
+		return fmt.Sprintf("%s %s %s", column, op, q.arg(*f.Value)), nil
+	}
+}
+
+// Screen selects rows of the requested period matching the filter. Conditions
+// on ratios that are not calculated for a company never match; such companies
+// go last when sorting by that ratio.
+func (r *RatiosRepository) Screen(ctx context.Context, req domain.ScreenerRequest) (*domain.ScreenerResult, error) {
+	if err := req.Normalize(); err != nil {
+		return nil, err
+	}
+
+	q := &screenerQuery{}
+
+	var source string
+	switch req.Period {
+	case "latest":
+		source = `SELECT DISTINCT ON (ticker) * FROM ratios WHERE period <> 'TTM' ORDER BY ticker, year DESC, period DESC`
+	case string(domain.TTM):
+		source = `SELECT DISTINCT ON (ticker) * FROM ratios WHERE period = 'TTM' ORDER BY ticker, year DESC, ttm_through DESC`
+	default:
+		source = fmt.Sprintf(`SELECT * FROM ratios WHERE year = %s AND period = %s`, q.arg(req.Year), q.arg(req.Period))
+	}
+
+	where := "TRUE"
+	if req.Filter != nil {
+		var err error
+		if where, err = q.where(req.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	orderBy := make([]string, 0, len(req.Sort)+1)
+	for _, s := range req.Sort {
+		column, ok := ratiosFieldColumns[s.Field]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q: %w", s.Field, domain.ErrInvalidInput)
+		}
+		direction := "ASC"
+		if s.Desc {
+			direction = "DESC"
+		}
+		orderBy = append(orderBy, fmt.Sprintf("%s %s NULLS LAST", column, direction))
+	}
+	orderBy = append(orderBy, "ticker")
+
+	query := fmt.Sprintf(`
+		SELECT %s, sector, COUNT(*) OVER ()
+		FROM (%s) AS screened
+		WHERE %s
+		ORDER BY %s
+		LIMIT %s OFFSET %s
+	`, ratiosSelectColumns, source, where, strings.Join(orderBy, ", "), q.arg(req.Limit), q.arg(req.Offset))
+
+	rows, err := r.pool.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run screener query: %w", err)
+	}
+	defer rows.Close()
+
+	result := &domain.ScreenerResult{Limit: req.Limit, Offset: req.Offset, Items: []domain.ScreenerItem{}}
+	for rows.Next() {
+		var item domain.ScreenerItem
+		targets := append(ratiosScanTargets(&item.Ratios), &item.Sector, &result.Total)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("failed to scan screener row: %w", err)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating screener rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"errors"
+	"financial_data/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScreenerQueryWhere(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		filter   domain.ScreenerFilter
+		expected string
+		args     []any
+		wantErr  bool
+	}{
+		{
+			name:     "condition",
+			filter:   domain.ScreenerFilter{Field: "priceToEarnings", Op: domain.OpLessEqual, Value: value(8)},
+			expected: "price_to_earnings <= $1",
+			args:     []any{8.0},
+		},
+		{
+			name: "nested and/or tree",
+			filter: domain.ScreenerFilter{And: []domain.ScreenerFilter{
+				{Field: "roe", Op: domain.OpGreater, Value: value(15)},
+				{Or: []domain.ScreenerFilter{
+					{Sectors: []domain.Sector{1, 3}},
+					{Field: "dividendYield", Op: domain.OpNotEqual, Value: value(0)},
+				}},
+			}},
+			expected: "(roe > $1 AND (sector = ANY($2) OR dividend_yield <> $3))",
+			args:     []any{15.0, []int32{1, 3}, 0.0},
+		},
+		{
+			name:    "unknown column",
+			filter:  domain.ScreenerFilter{Field: "price_to_earnings; DROP TABLE ratios", Op: domain.OpLess, Value: value(1)},
+			wantErr: true,
+		},
+		{
+			name: "unknown column in a nested group",
+			filter: domain.ScreenerFilter{Or: []domain.ScreenerFilter{
+				{Field: "roe", Op: domain.OpGreater, Value: value(15)},
+				{Field: "beta", Op: domain.OpLess, Value: value(1)},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "bad operator",
+			filter:  domain.ScreenerFilter{Field: "roe", Op: "like", Value: value(15)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &screenerQuery{}
+			got, err := q.where(&tt.filter)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidInput) {
+					t.Errorf("Expected ErrInvalidInput, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if !reflect.DeepEqual(q.args, tt.args) {
+				t.Errorf("Expected args %v, got %v", tt.args, q.args)
+			}
+		})
+	}
+}
+
+func TestRatiosFieldColumnsMatchRatiosJSON(t *testing.T) {
+	fields := make(map[string]bool)
+	ratiosType := reflect.TypeOf(domain.Ratios{})
+	for i := 0; i < ratiosType.NumField(); i++ {
+		name, _, _ := strings.Cut(ratiosType.Field(i).Tag.Get("json"), ",")
+		fields[name] = true
+	}
+
+	for field := range ratiosFieldColumns {
+		if !fields[field] {
+			t.Errorf("Screener field %q is not a JSON name of Ratios", field)
+		}
+	}
+}
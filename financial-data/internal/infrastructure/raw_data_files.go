@@ -0,0 +1,206 @@
+package infrastructure
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"financial_data/internal/domain"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const rawDataSheet = "RawData"
+
+// RawDataFileCodec reads and writes raw data tables. CSV and XLSX files have a
+// header row of domain.RawDataColumns names, JSON files are an array of reports.
+type RawDataFileCodec struct{}
+
+func NewRawDataFileCodec() *RawDataFileCodec {
+	return &RawDataFileCodec{}
+}
+
+// Decode reads every data row of a file. A malformed file is an error, while a
+// row that cannot be read is returned with its own error.
+func (c *RawDataFileCodec) Decode(format domain.RawDataFileFormat, r io.Reader) ([]domain.RawDataFileRow, error) {
+	switch format {
+	case domain.FormatCSV:
+		return decodeRawDataCSV(r)
+	case domain.FormatXLSX:
+		return decodeRawDataXLSX(r)
+	case domain.FormatJSON:
+		return decodeRawDataJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q: %w", format, domain.ErrInvalidInput)
+	}
+}
+
+func (c *RawDataFileCodec) Encode(format domain.RawDataFileFormat, w io.Writer, reports []domain.RawData) error {
+	switch format {
+	case domain.FormatCSV:
+		return encodeRawDataCSV(w, reports)
+	case domain.FormatXLSX:
+		return encodeRawDataXLSX(w, reports)
+	case domain.FormatJSON:
+		return json.NewEncoder(w).Encode(reports)
+	default:
+		return fmt.Errorf("unsupported format %q: %w", format, domain.ErrInvalidInput)
+	}
+}
+
+func decodeRawDataCSV(r io.Reader) ([]domain.RawDataFileRow, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(body), "\ufeff")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	// Excel with a Russian locale saves CSV separated by semicolons and
+	// writes numbers with a decimal comma
+	header, _, _ := strings.Cut(text, "\n")
+	decimalComma := strings.Count(header, ";") > strings.Count(header, ",")
+	if decimalComma {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %v: %w", err, domain.ErrInvalidInput)
+	}
+	return parseRawDataRecords(records, decimalComma)
+}
+
+func decodeRawDataXLSX(r io.Reader) ([]domain.RawDataFileRow, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %v: %w", err, domain.ErrInvalidInput)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("xlsx has no sheets: %w", domain.ErrInvalidInput)
+	}
+	// raw values keep numbers as stored instead of as the cell format displays them
+	records, err := f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %v: %w", err, domain.ErrInvalidInput)
+	}
+	return parseRawDataRecords(records, false)
+}
+
+func parseRawDataRecords(records [][]string, decimalComma bool) ([]domain.RawDataFileRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty: %w", domain.ErrInvalidInput)
+	}
+	header := records[0]
+	if err := domain.CheckRawDataHeader(header); err != nil {
+		return nil, err
+	}
+
+	rows := make([]domain.RawDataFileRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		data, err := domain.ParseRawDataRecord(header, record, decimalComma)
+		rows = append(rows, domain.RawDataFileRow{Row: i + 1, Data: data, Err: err})
+	}
+	return rows, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeRawDataJSON(r io.Reader) ([]domain.RawDataFileRow, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid json, expected an array of reports: %v: %w", err, domain.ErrInvalidInput)
+	}
+
+	rows := make([]domain.RawDataFileRow, 0, len(items))
+	for i, item := range items {
+		var data domain.RawData
+		if err := json.Unmarshal(item, &data); err != nil {
+			rows = append(rows, domain.RawDataFileRow{Row: i + 1, Err: err})
+			continue
+		}
+		data.Ticker = strings.ToUpper(data.Ticker)
+		rows = append(rows, domain.RawDataFileRow{Row: i + 1, Data: &data})
+	}
+	return rows, nil
+}
+
+func encodeRawDataCSV(w io.Writer, reports []domain.RawData) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(domain.RawDataColumns); err != nil {
+		return err
+	}
+	for i := range reports {
+		if err := writer.Write(domain.RawDataRecord(&reports[i])); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func encodeRawDataXLSX(w io.Writer, reports []domain.RawData) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName(f.GetSheetName(0), rawDataSheet); err != nil {
+		return err
+	}
+
+	sw, err := f.NewStreamWriter(rawDataSheet)
+	if err != nil {
+		return err
+	}
+
+	header := make([]any, len(domain.RawDataColumns))
+	for i, column := range domain.RawDataColumns {
+		header[i] = column
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	for i := range reports {
+		record := domain.RawDataRecord(&reports[i])
+		row := make([]any, len(record))
+		for j, value := range record {
+			// numbers are written as numbers so that Excel can sum them
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				row[j] = n
+			} else if x, err := strconv.ParseFloat(value, 64); err == nil {
+				row[j] = x
+			} else if value != "" {
+				row[j] = value
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	_, err = f.WriteTo(w)
+	return err
+}
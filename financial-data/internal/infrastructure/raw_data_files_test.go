@@ -0,0 +1,125 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"financial_data/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRawDataFileCodecRoundTrip(t *testing.T) {
+	i64 := func(v int64) *int64 { return &v }
+	millions := "millions"
+	eps := 12.5
+	reports := []domain.RawData{
+		{Ticker: "SBER", Year: 2024, Period: domain.YEAR, Status: domain.StatusConfirmed, ReportUnits: &millions, Revenue: i64(3_000_000), NetProfit: i64(1_500_000), BasicEPS: &eps},
+		{Ticker: "LKOH", Year: 2025, Period: domain.Q1, Status: domain.StatusDraft, Revenue: i64(2_000_000), CAPEX: i64(-150_000)},
+	}
+
+	codec := NewRawDataFileCodec()
+	for _, format := range []domain.RawDataFileFormat{domain.FormatCSV, domain.FormatXLSX, domain.FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := codec.Encode(format, &buf, reports); err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+
+			rows, err := codec.Decode(format, &buf)
+			if err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if len(rows) != len(reports) {
+				t.Fatalf("Expected %d rows, got %d", len(reports), len(rows))
+			}
+			for i, row := range rows {
+				if row.Err != nil {
+					t.Fatalf("Row %d: unexpected error: %v", row.Row, row.Err)
+				}
+				if row.Row != i+1 {
+					t.Errorf("Expected row number %d, got %d", i+1, row.Row)
+				}
+				if !reflect.DeepEqual(*row.Data, reports[i]) {
+					t.Errorf("Row %d: expected %+v, got %+v", row.Row, reports[i], *row.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeRawDataCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		revenue int64
+		eps     float64
+		rowErr  bool
+	}{
+		{
+			name:    "semicolons with decimal comma",
+			file:    "\ufeffticker;year;period;revenue;basicEps\nsber;2024;YEAR;1 234 567;12,5\n;;;;\n",
+			revenue: 1_234_567,
+			eps:     12.5,
+		},
+		{
+			name:    "commas with decimal point",
+			file:    "ticker,year,period,revenue,basicEps\nSBER,2024,YEAR,1234567,12.5\n",
+			revenue: 1_234_567,
+			eps:     12.5,
+		},
+		{
+			name:   "commas with a comma in a number",
+			file:   "ticker,year,period,revenue,basicEps\nSBER,2024,YEAR,\"1,234\",12.5\n",
+			rowErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := NewRawDataFileCodec().Decode(domain.FormatCSV, strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("Expected 1 row, got %d", len(rows))
+			}
+			row := rows[0]
+			if tt.rowErr {
+				if row.Err == nil {
+					t.Errorf("Expected a row error, got %+v", row.Data)
+				}
+				return
+			}
+			if row.Err != nil {
+				t.Fatalf("Unexpected row error: %v", row.Err)
+			}
+			if row.Data.Ticker != "SBER" || *row.Data.Revenue != tt.revenue || *row.Data.BasicEPS != tt.eps {
+				t.Errorf("Expected SBER with revenue %d and EPS %v, got %+v", tt.revenue, tt.eps, row.Data)
+			}
+		})
+	}
+}
+
+func TestDecodeRawDataRejectsMalformedFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		format domain.RawDataFileFormat
+		file   string
+	}{
+		{name: "empty csv", format: domain.FormatCSV, file: ""},
+		{name: "unknown column", format: domain.FormatCSV, file: "ticker,year,period,turnover\nSBER,2024,YEAR,1\n"},
+		{name: "json object", format: domain.FormatJSON, file: `{"ticker":"SBER"}`},
+		{name: "not an xlsx", format: domain.FormatXLSX, file: "ticker,year,period\n"},
+		{name: "unsupported format", format: "xml", file: "<reports/>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRawDataFileCodec().Decode(tt.format, strings.NewReader(tt.file))
+			if !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("Expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
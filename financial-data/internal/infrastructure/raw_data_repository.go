@@ -76,7 +76,7 @@ func (r *RawDataRepository) GetByTickerAndPeriod(ctx context.Context, ticker str
 	query := fmt.Sprintf(`SELECT %s FROM metrics WHERE ticker = $1 AND year = $2 AND period = $3 AND status = 'confirmed'`, rawDataSelectColumns)
 
 	rd := &domain.RawData{}
-	err := r.pool.QueryRow(ctx, query, ticker, year, period).Scan(rawDataScanTargets(rd)...)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, ticker, year, period).Scan(rawDataScanTargets(rd)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("metrics not found: %w", domain.ErrNotFound)
@@ -94,7 +94,7 @@ func (r *RawDataRepository) GetLatestByTicker(ctx context.Context, ticker string
 	query := fmt.Sprintf(`SELECT %s FROM metrics WHERE ticker = $1 AND status = 'confirmed' ORDER BY year DESC, period DESC LIMIT 1`, rawDataSelectColumns)
 
 	rd := &domain.RawData{}
-	err := r.pool.QueryRow(ctx, query, ticker).Scan(rawDataScanTargets(rd)...)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, ticker).Scan(rawDataScanTargets(rd)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("no metrics found for ticker %s: %w", ticker, domain.ErrNotFound)
@@ -111,7 +111,7 @@ func (r *RawDataRepository) GetHistoryByTicker(ctx context.Context, ticker strin
 
 	query := fmt.Sprintf(`SELECT %s FROM metrics WHERE ticker = $1 AND status = 'confirmed' ORDER BY year DESC, period DESC`, rawDataSelectColumns)
 
-	rows, err := r.pool.Query(ctx, query, ticker)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, ticker)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query metrics: %w", err)
 	}
@@ -139,7 +139,7 @@ func (r *RawDataRepository) GetDraftByTickerAndPeriod(ctx context.Context, ticke
 	query := fmt.Sprintf(`SELECT %s FROM metrics WHERE ticker = $1 AND year = $2 AND period = $3 AND status = 'draft'`, rawDataSelectColumns)
 
 	rd := &domain.RawData{}
-	err := r.pool.QueryRow(ctx, query, ticker, year, period).Scan(rawDataScanTargets(rd)...)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, ticker, year, period).Scan(rawDataScanTargets(rd)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -156,7 +156,7 @@ func (r *RawDataRepository) GetDraftsByTicker(ctx context.Context, ticker string
 
 	query := fmt.Sprintf(`SELECT %s FROM metrics WHERE ticker = $1 AND status = 'draft' ORDER BY year DESC, period DESC`, rawDataSelectColumns)
 
-	rows, err := r.pool.Query(ctx, query, ticker)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, ticker)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query drafts: %w", err)
 	}
@@ -183,7 +183,7 @@ func (r *RawDataRepository) ConfirmDraft(ctx context.Context, ticker string, yea
 
 	query := `UPDATE metrics SET status = 'confirmed', updated_at = NOW() WHERE ticker = $1 AND year = $2 AND period = $3 AND status = 'draft'`
 
-	result, err := r.pool.Exec(ctx, query, ticker, year, period)
+	result, err := conn(ctx, r.pool).Exec(ctx, query, ticker, year, period)
 	if err != nil {
 		return fmt.Errorf("failed to confirm draft: %w", err)
 	}
@@ -254,7 +254,7 @@ func (r *RawDataRepository) Create(ctx context.Context, rawData *domain.RawData)
 		)
 	`
 
-	_, err := r.pool.Exec(ctx, query,
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
 		rawData.Ticker, rawData.Year, rawData.Period, status, rawData.ReportUnits,
 		rawData.Revenue, rawData.CostOfRevenue, rawData.GrossProfit, rawData.OperatingExpenses,
 		rawData.OtherIncome, rawData.OtherExpenses,
@@ -325,7 +325,7 @@ func (r *RawDataRepository) Update(ctx context.Context, rawData *domain.RawData)
 		WHERE ticker = $1 AND year = $2 AND period = $3
 	`
 
-	result, err := r.pool.Exec(ctx, query,
+	result, err := conn(ctx, r.pool).Exec(ctx, query,
 		rawData.Ticker, rawData.Year, rawData.Period, status, rawData.ReportUnits,
 		rawData.Revenue, rawData.CostOfRevenue, rawData.GrossProfit, rawData.OperatingExpenses,
 		rawData.OtherIncome, rawData.OtherExpenses,
@@ -370,7 +370,7 @@ func (r *RawDataRepository) Delete(ctx context.Context, ticker string, year int,
 
 	query := `DELETE FROM metrics WHERE ticker = $1 AND year = $2 AND period = $3`
 
-	result, err := r.pool.Exec(ctx, query, ticker, year, period)
+	result, err := conn(ctx, r.pool).Exec(ctx, query, ticker, year, period)
 	if err != nil {
 		return fmt.Errorf("failed to delete metrics: %w", err)
 	}
@@ -0,0 +1,168 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RawDataSourcesRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRawDataSourcesRepository(pool *pgxpool.Pool) *RawDataSourcesRepository {
+	return &RawDataSourcesRepository{pool: pool}
+}
+
+const rawDataSourceColumns = `id, ticker, year, period, source_year, source_period, source_url, data, is_canonical, created_at, updated_at`
+
+// Save stores a source, replacing the figures of the same source report if
+// they were saved before. The canonical flag is left as is; use SetCanonical.
+func (r *RawDataSourcesRepository) Save(ctx context.Context, source *domain.RawDataSource) error {
+	if source == nil || source.Ticker == "" {
+		return fmt.Errorf("source is empty: %w", domain.ErrInvalidInput)
+	}
+	if !source.Period.IsValid() || !source.SourcePeriod.IsValid() {
+		return fmt.Errorf("invalid period: %w", domain.ErrInvalidInput)
+	}
+
+	data, err := json.Marshal(source.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal raw data source: %w", err)
+	}
+
+	query := `
+		INSERT INTO raw_data_sources (ticker, year, period, source_year, source_period, source_url, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (ticker, year, period, source_year, source_period)
+		DO UPDATE SET source_url = COALESCE(EXCLUDED.source_url, raw_data_sources.source_url),
+			data = EXCLUDED.data, updated_at = NOW()
+		RETURNING id, is_canonical, created_at, updated_at
+	`
+
+	err = conn(ctx, r.pool).QueryRow(ctx, query,
+		source.Ticker, source.Year, source.Period, source.SourceYear, source.SourcePeriod, source.SourceURL, data,
+	).Scan(&source.ID, &source.Canonical, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save raw data source: %w", err)
+	}
+
+	source.SetKind()
+	return nil
+}
+
+func (r *RawDataSourcesRepository) GetByID(ctx context.Context, id int) (*domain.RawDataSource, error) {
+	query := fmt.Sprintf(`SELECT %s FROM raw_data_sources WHERE id = $1`, rawDataSourceColumns)
+
+	source, err := scanRawDataSource(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("raw data source %d not found: %w", id, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return source, nil
+}
+
+// GetByPeriod returns the sources of a report, earliest source report first.
+func (r *RawDataSourcesRepository) GetByPeriod(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.RawDataSource, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM raw_data_sources
+		WHERE ticker = $1 AND year = $2 AND period = $3
+		ORDER BY source_year, source_period`, rawDataSourceColumns)
+
+	return r.query(ctx, query, ticker, year, period)
+}
+
+// GetByTicker returns the sources of every report of the ticker, latest period first.
+func (r *RawDataSourcesRepository) GetByTicker(ctx context.Context, ticker string) ([]domain.RawDataSource, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM raw_data_sources
+		WHERE ticker = $1
+		ORDER BY year DESC, period DESC, source_year, source_period`, rawDataSourceColumns)
+
+	return r.query(ctx, query, ticker)
+}
+
+// SetCanonical makes the source the only canonical one of its report.
+func (r *RawDataSourcesRepository) SetCanonical(ctx context.Context, id int) error {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var ticker, period string
+	var year int
+	err = tx.QueryRow(ctx, `SELECT ticker, year, period FROM raw_data_sources WHERE id = $1 FOR UPDATE`, id).
+		Scan(&ticker, &year, &period)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("raw data source %d not found: %w", id, domain.ErrNotFound)
+		}
+		return fmt.Errorf("failed to get raw data source: %w", err)
+	}
+
+	// the partial unique index allows one canonical source per report, so the
+	// previous one is reset first
+	_, err = tx.Exec(ctx, `
+		UPDATE raw_data_sources SET is_canonical = FALSE, updated_at = NOW()
+		WHERE ticker = $1 AND year = $2 AND period = $3 AND is_canonical AND id <> $4`,
+		ticker, year, period, id)
+	if err != nil {
+		return fmt.Errorf("failed to reset canonical source: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE raw_data_sources SET is_canonical = TRUE, updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to set canonical source: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *RawDataSourcesRepository) query(ctx context.Context, query string, args ...any) ([]domain.RawDataSource, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query raw data sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []domain.RawDataSource
+	for rows.Next() {
+		s, err := scanRawDataSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, *s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating raw data sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+func scanRawDataSource(row pgx.Row) (*domain.RawDataSource, error) {
+	var s domain.RawDataSource
+	var data []byte
+	err := row.Scan(&s.ID, &s.Ticker, &s.Year, &s.Period, &s.SourceYear, &s.SourcePeriod, &s.SourceURL,
+		&data, &s.Canonical, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan raw data source: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.Data); err != nil {
+		return nil, fmt.Errorf("corrupted data of raw data source %d: %w", s.ID, err)
+	}
+	s.SetKind()
+	return &s, nil
+}
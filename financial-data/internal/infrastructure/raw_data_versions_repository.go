@@ -0,0 +1,139 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RawDataVersionsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRawDataVersionsRepository(pool *pgxpool.Pool) *RawDataVersionsRepository {
+	return &RawDataVersionsRepository{pool: pool}
+}
+
+// Append stores a snapshot of rawData as the next version of its report.
+func (r *RawDataVersionsRepository) Append(ctx context.Context, rawData *domain.RawData, action domain.RawDataAction, actor string) (*domain.RawDataVersion, error) {
+	if rawData == nil || rawData.Ticker == "" {
+		return nil, fmt.Errorf("raw data is empty: %w", domain.ErrInvalidInput)
+	}
+	if actor == "" {
+		return nil, fmt.Errorf("actor is empty: %w", domain.ErrInvalidInput)
+	}
+
+	data, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal raw data snapshot: %w", err)
+	}
+
+	// writers hold LockReport, so MAX(version) is stable here; the unique
+	// constraint still rejects an append made without the lock
+	query := `
+		INSERT INTO raw_data_versions (ticker, year, period, version, action, actor, data)
+		SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, $6
+		FROM raw_data_versions
+		WHERE ticker = $1 AND year = $2 AND period = $3
+		RETURNING version, created_at
+	`
+
+	version := &domain.RawDataVersion{
+		Ticker: rawData.Ticker,
+		Year:   rawData.Year,
+		Period: rawData.Period,
+		Action: action,
+		Actor:  actor,
+		Data:   *rawData,
+	}
+	err = conn(ctx, r.pool).QueryRow(ctx, query, rawData.Ticker, rawData.Year, rawData.Period, action, actor, data).
+		Scan(&version.Version, &version.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to append raw data version: %w", err)
+	}
+
+	return version, nil
+}
+
+// LockReport serializes changes of a report until the end of the transaction
+// of ctx. Outside a transaction the lock is released right away.
+func (r *RawDataVersionsRepository) LockReport(ctx context.Context, ticker string, year int, period domain.ReportPeriod) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text || ':' || $2::text), $3::int)`, ticker, string(period), year)
+	if err != nil {
+		return fmt.Errorf("failed to lock raw data report: %w", err)
+	}
+	return nil
+}
+
+// GetVersions returns all versions of a report, oldest first.
+func (r *RawDataVersionsRepository) GetVersions(ctx context.Context, ticker string, year int, period domain.ReportPeriod) ([]domain.RawDataVersion, error) {
+	if ticker == "" {
+		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+
+	query := `
+		SELECT ticker, year, period, version, action, actor, data, created_at
+		FROM raw_data_versions
+		WHERE ticker = $1 AND year = $2 AND period = $3
+		ORDER BY version
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, ticker, year, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query raw data versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []domain.RawDataVersion
+	for rows.Next() {
+		v, err := scanRawDataVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating raw data versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+func (r *RawDataVersionsRepository) GetVersion(ctx context.Context, ticker string, year int, period domain.ReportPeriod, version int) (*domain.RawDataVersion, error) {
+	query := `
+		SELECT ticker, year, period, version, action, actor, data, created_at
+		FROM raw_data_versions
+		WHERE ticker = $1 AND year = $2 AND period = $3 AND version = $4
+	`
+
+	v, err := scanRawDataVersion(conn(ctx, r.pool).QueryRow(ctx, query, ticker, year, period, version))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("version %d of %s %d %s not found: %w", version, ticker, year, period, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func scanRawDataVersion(row pgx.Row) (*domain.RawDataVersion, error) {
+	var v domain.RawDataVersion
+	var data []byte
+	if err := row.Scan(&v.Ticker, &v.Year, &v.Period, &v.Version, &v.Action, &v.Actor, &data, &v.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan raw data version: %w", err)
+	}
+	if err := json.Unmarshal(data, &v.Data); err != nil {
+		return nil, fmt.Errorf("corrupted raw data snapshot of version %d: %w", v.Version, err)
+	}
+	return &v, nil
+}
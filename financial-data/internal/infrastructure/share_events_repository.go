@@ -0,0 +1,168 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"financial_data/internal/domain"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ShareEventsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewShareEventsRepository(pool *pgxpool.Pool) *ShareEventsRepository {
+	return &ShareEventsRepository{pool: pool}
+}
+
+func (r *ShareEventsRepository) GetByTicker(ctx context.Context, ticker string) ([]domain.ShareEvent, error) {
+	if ticker == "" {
+		return nil, fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+
+	query := `
+		SELECT id, ticker, date, type, shares_outstanding, ratio, note
+		FROM share_events
+		WHERE ticker = $1
+		ORDER BY date
+	`
+
+	rows, err := r.pool.Query(ctx, query, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query share events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.ShareEvent
+	for rows.Next() {
+		var e domain.ShareEvent
+		err := rows.Scan(&e.ID, &e.Ticker, &e.Date, &e.Type, &e.SharesOutstanding, &e.Ratio, &e.Note)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan share event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating share events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetSharesAt returns the share count after the last event on or before date.
+func (r *ShareEventsRepository) GetSharesAt(ctx context.Context, ticker string, date time.Time) (int64, error) {
+	query := `
+		SELECT shares_outstanding
+		FROM share_events
+		WHERE ticker = $1 AND date <= $2
+		ORDER BY date DESC, id DESC
+		LIMIT 1
+	`
+
+	var shares int64
+	err := r.pool.QueryRow(ctx, query, ticker, date).Scan(&shares)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("no share count for %s at %s: %w", ticker, date.Format("2006-01-02"), domain.ErrNotFound)
+		}
+		return 0, fmt.Errorf("failed to get share count: %w", err)
+	}
+
+	return shares, nil
+}
+
+func (r *ShareEventsRepository) Create(ctx context.Context, event *domain.ShareEvent) error {
+	if err := validateShareEvent(event); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO share_events (ticker, date, type, shares_outstanding, ratio, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		event.Ticker, event.Date, event.Type, event.SharesOutstanding, event.Ratio, event.Note,
+	).Scan(&event.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create share event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShareEventsRepository) Update(ctx context.Context, id int, event *domain.ShareEvent) error {
+	if id < 1 {
+		return fmt.Errorf("invalid share event ID: %d: %w", id, domain.ErrInvalidInput)
+	}
+	if err := validateShareEvent(event); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE share_events SET
+			date = $3, type = $4, shares_outstanding = $5, ratio = $6, note = $7
+		WHERE id = $1 AND ticker = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		id, event.Ticker, event.Date, event.Type, event.SharesOutstanding, event.Ratio, event.Note,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update share event: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("share event not found for ID %d: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ShareEventsRepository) Delete(ctx context.Context, ticker string, id int) error {
+	if id < 1 {
+		return fmt.Errorf("invalid share event ID: %d: %w", id, domain.ErrInvalidInput)
+	}
+
+	query := `DELETE FROM share_events WHERE id = $1 AND ticker = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, ticker)
+	if err != nil {
+		return fmt.Errorf("failed to delete share event: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("share event not found for ID %d: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func validateShareEvent(event *domain.ShareEvent) error {
+	if event == nil {
+		return fmt.Errorf("share event is nil: %w", domain.ErrInvalidInput)
+	}
+	if event.Ticker == "" {
+		return fmt.Errorf("ticker is empty: %w", domain.ErrInvalidInput)
+	}
+	if event.Date.IsZero() {
+		return fmt.Errorf("date is empty: %w", domain.ErrInvalidInput)
+	}
+	if !event.Type.IsValid() {
+		return fmt.Errorf("invalid share event type %q: %w", event.Type, domain.ErrInvalidInput)
+	}
+	if event.SharesOutstanding <= 0 {
+		return fmt.Errorf("sharesOutstanding must be positive: %w", domain.ErrInvalidInput)
+	}
+	if (event.Type == domain.ShareEventSplit || event.Type == domain.ShareEventConsolidation) && (event.Ratio == nil || *event.Ratio <= 0) {
+		return fmt.Errorf("ratio is required for %s: %w", event.Type, domain.ErrInvalidInput)
+	}
+	return nil
+}
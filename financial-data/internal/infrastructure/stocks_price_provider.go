@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"financial_data/internal/domain"
 	"fmt"
 	"io"
@@ -16,17 +17,32 @@ import (
 const (
 	PRICE_TTL   = 15 * time.Minute
 	COMPANY_TTL = 24 * time.Hour
+	// announced dividends show up in ISS during the day, so they are kept shorter than company info
+	DIVIDENDS_TTL = time.Hour
+
+	// ISS returns at most this many candles per request
+	CANDLES_PAGE_SIZE = 500
 )
 
+// CompanyLookup tells the provider which board and market a ticker trades on.
+type CompanyLookup interface {
+	GetByTicker(ctx context.Context, ticker string) (*domain.Company, error)
+}
+
 type MoexDataProvider struct {
-	baseUrl string
-	client  http.Client
-	redis   *redis.Client
+	baseUrl   string
+	client    http.Client
+	redis     *redis.Client
+	companies CompanyLookup
+	recording bool
 }
 
-func NewMoexDataProvider(redis *redis.Client) *MoexDataProvider {
+// NewMoexDataProvider routes requests by the board stored for the company;
+// tickers that are not registered are looked up on TQBR.
+func NewMoexDataProvider(redis *redis.Client, companies CompanyLookup) *MoexDataProvider {
 	m := MoexDataProvider{}
-	m.baseUrl = "https://iss.moex.com/iss/engines/stock/markets/shares/boards/TQBR/securities/"
+	m.baseUrl = "https://iss.moex.com/iss/engines/stock/markets/"
+	m.companies = companies
 	m.client = http.Client{
 		Timeout: 3 * time.Second,
 	}
@@ -34,6 +50,40 @@ func NewMoexDataProvider(redis *redis.Client) *MoexDataProvider {
 	return &m
 }
 
+// NewRecordingMoexDataProvider talks to MOEX like NewMoexDataProvider and saves
+// every response into dir in the layout read by FixtureDataProvider.
+func NewRecordingMoexDataProvider(redis *redis.Client, companies CompanyLookup, dir string) *MoexDataProvider {
+	m := NewMoexDataProvider(redis, companies)
+	m.client.Transport = newRecordingTransport(dir)
+	m.recording = true
+	return m
+}
+
+// securitiesUrl returns the ISS securities url of the board the ticker trades on.
+func (m *MoexDataProvider) securitiesUrl(ticker string) string {
+	company := domain.Company{Ticker: ticker}
+	if m.companies != nil {
+		found, err := m.companies.GetByTicker(context.TODO(), ticker)
+		switch {
+		case err == nil:
+			company = *found
+		case !errors.Is(err, domain.ErrNotFound):
+			slog.Warn("failed to get company board, using default", slog.String("ticker", ticker), slog.Any("err", err))
+		}
+	}
+	company.ApplyDefaults()
+
+	return fmt.Sprintf("%s%s/boards/%s/securities/", m.baseUrl, company.InstrumentType.Market(), company.Board)
+}
+
+func (m *MoexDataProvider) getCached(key string) (string, error) {
+	if m.recording {
+		// always go to MOEX so that the response ends up in the fixtures
+		return "", redis.Nil
+	}
+	return m.redis.Get(context.TODO(), key).Result()
+}
+
 func (m *MoexDataProvider) GetStockPrice(ticker string, daysBackwards int, interval domain.Period) ([]domain.Candle, error) {
 	if daysBackwards > 500 {
 		return nil, fmt.Errorf("MOEX API doesn't support more than 500 days. Use pagination")
@@ -43,9 +93,9 @@ func (m *MoexDataProvider) GetStockPrice(ticker string, daysBackwards int, inter
 	from := now.AddDate(0, 0, -daysBackwards).Format("2006-01-02")
 	till := now.Format("2006-01-02")
 	url := fmt.Sprintf("%s%s/candles.json?from=%s&till=%s&interval=%d&iss.meta=off",
-		m.baseUrl, ticker, from, till, int(interval))
+		m.securitiesUrl(ticker), ticker, from, till, int(interval))
 
-	cache, err := m.redis.Get(context.TODO(), url).Result()
+	cache, err := m.getCached(url)
 	if err != nil && err != redis.Nil {
 		slog.Warn("redis get error", slog.String("key", url), slog.Any("err", err))
 	}
@@ -78,18 +128,164 @@ func (m *MoexDataProvider) GetStockPrice(ticker string, daysBackwards int, inter
 	return candles, nil
 }
 
+// GetStockPriceRange loads every candle between from and till, walking ISS pages
+// with the start parameter. Results are not cached: callers are expected to store them.
+func (m *MoexDataProvider) GetStockPriceRange(ticker string, from, till time.Time, interval domain.Period) ([]domain.Candle, error) {
+	return m.candlesRange(m.securitiesUrl(ticker), ticker, from, till, interval)
+}
+
+// GetIndexRange loads candles of an index from the ISS index market the same way
+// as GetStockPriceRange.
+func (m *MoexDataProvider) GetIndexRange(index string, from, till time.Time, interval domain.Period) ([]domain.Candle, error) {
+	return m.candlesRange(m.baseUrl+"index/securities/", index, from, till, interval)
+}
+
+func (m *MoexDataProvider) candlesRange(baseUrl, secid string, from, till time.Time, interval domain.Period) ([]domain.Candle, error) {
+	var result []domain.Candle
+	for start := 0; ; {
+		url := fmt.Sprintf("%s%s/candles.json?from=%s&till=%s&interval=%d&start=%d&iss.meta=off",
+			baseUrl, secid, from.Format("2006-01-02"), till.Format("2006-01-02"), int(interval), start)
+
+		resp, err := m.client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("MOEX API returned status code: %d", resp.StatusCode)
+		}
+
+		page, err := unmarshalCandles(body)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page...)
+
+		if len(page) < CANDLES_PAGE_SIZE {
+			return result, nil
+		}
+		start += len(page)
+	}
+}
+
+// GetStockInfo loads the ISS description with the boards of the security, then
+// the lot size from the securities of its primary board and the free float from
+// the MOEX free-float coefficients.
 func (m *MoexDataProvider) GetStockInfo(ticker string) (*domain.StockInfo, error) {
-	url := fmt.Sprintf("https://iss.moex.com/iss/securities/%s.json?iss.meta=off&iss.only=description&description.columns=name,title,value", ticker)
+	url := fmt.Sprintf("https://iss.moex.com/iss/securities/%s.json?iss.meta=off&iss.only=description,boards"+
+		"&description.columns=name,title,value&boards.columns=boardid,market,engine,is_traded,listed_till,is_primary,currencyid", ticker)
+
+	body, err := m.getWithCache(url, COMPANY_TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	stockInfo, err := unmarshalStockInfo(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if stockInfo.Board != "" && stockInfo.Market != "" {
+		url := fmt.Sprintf("%s%s/boards/%s/securities/%s.json?iss.meta=off&iss.only=securities&securities.columns=SECID,LOTSIZE",
+			m.baseUrl, stockInfo.Market, stockInfo.Board, ticker)
+		body, err := m.getWithCache(url, COMPANY_TTL)
+		if err != nil {
+			slog.Warn("failed to load lot size", slog.String("ticker", ticker), slog.Any("err", err))
+			return stockInfo, nil
+		}
+		var response domain.BoardSecuritiesApiResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			slog.Warn("failed to unmarshal board securities", slog.String("ticker", ticker), slog.Any("err", err))
+			return stockInfo, nil
+		}
+		stockInfo.SetLotSize(response.Securities)
+	}
+
+	freeFloats, err := m.freeFloatTable()
+	if err != nil {
+		slog.Warn("failed to load free float", slog.String("ticker", ticker), slog.Any("err", err))
+		return stockInfo, nil
+	}
+	stockInfo.SetFreeFloat(freeFloats)
+
+	return stockInfo, nil
+}
+
+// freeFloatTable loads the free-float coefficients of all securities in the base
+// of MOEX indices page by page. The pages are the same for every ticker, so the
+// cache keeps it to one load a day.
+func (m *MoexDataProvider) freeFloatTable() (domain.IssTable, error) {
+	var table domain.IssTable
+	for start := 0; ; {
+		url := fmt.Sprintf("https://iss.moex.com/iss/statistics/engines/stock/markets/shares/freefloat.json?iss.meta=off&iss.only=freefloat&start=%d", start)
+
+		body, err := m.getWithCache(url, COMPANY_TTL)
+		if err != nil {
+			return table, err
+		}
+		var response domain.FreeFloatApiResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return table, fmt.Errorf("failed to unmarshal free float: %w", err)
+		}
+
+		page := response.FreeFloat
+		if len(page.Data) == 0 {
+			return table, nil
+		}
+		table.Columns = page.Columns
+		table.Data = append(table.Data, page.Data...)
+		start += len(page.Data)
+	}
+}
+
+// getWithCache returns the response body of url, keeping it in redis for ttl.
+func (m *MoexDataProvider) getWithCache(url string, ttl time.Duration) ([]byte, error) {
+	cache, err := m.getCached(url)
+	if err == nil {
+		return []byte(cache), nil
+	}
+	if err != redis.Nil {
+		slog.Warn("redis get error", slog.String("key", url), slog.Any("err", err))
+	}
+
+	resp, err := m.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	cache, err := m.redis.Get(context.TODO(), url).Result()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MOEX API returned status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.redis.Set(context.TODO(), url, string(body), ttl).Err(); err != nil {
+		slog.Warn("failed to cache MOEX response", slog.String("key", url), slog.Any("err", err))
+	}
+
+	return body, nil
+}
+
+func (m *MoexDataProvider) GetDividends(ticker string) ([]domain.Dividends, error) {
+	url := fmt.Sprintf("https://iss.moex.com/iss/securities/%s/dividends.json?iss.meta=off", ticker)
+
+	cache, err := m.getCached(url)
 	if err != nil && err != redis.Nil {
 		slog.Warn("redis get error", slog.String("key", url), slog.Any("err", err))
 	}
 	if err == nil {
-		if stockInfo, err := unmarshalStockInfo([]byte(cache)); err == nil {
-			return stockInfo, nil
+		if dividends, err := unmarshalDividends([]byte(cache)); err == nil {
+			return dividends, nil
 		}
-		slog.Warn("failed to unmarshal cached stock info, fetching from API", slog.String("ticker", ticker))
+		slog.Warn("failed to unmarshal cached dividends, fetching from API", slog.String("ticker", ticker))
 	}
 
 	resp, err := m.client.Get(url)
@@ -107,16 +303,16 @@ func (m *MoexDataProvider) GetStockInfo(ticker string) (*domain.StockInfo, error
 		return nil, err
 	}
 
-	stockInfo, err := unmarshalStockInfo(body)
+	dividends, err := unmarshalDividends(body)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := m.redis.Set(context.TODO(), url, string(body), COMPANY_TTL).Err(); err != nil {
-		slog.Warn("failed to cache stock info", slog.String("ticker", ticker), slog.Any("err", err))
+	if err := m.redis.Set(context.TODO(), url, string(body), DIVIDENDS_TTL).Err(); err != nil {
+		slog.Warn("failed to cache dividends", slog.String("ticker", ticker), slog.Any("err", err))
 	}
 
-	return stockInfo, nil
+	return dividends, nil
 }
 
 func unmarshalCandles(data []byte) ([]domain.Candle, error) {
@@ -132,16 +328,29 @@ func unmarshalStockInfo(data []byte) (*domain.StockInfo, error) {
 	if err := json.Unmarshal(data, &response); err != nil {
 		return nil, err
 	}
-	return domain.ParseStockInfo(response.Description.Data)
+	stockInfo, err := domain.ParseStockInfo(response.Description.Data)
+	if err != nil {
+		return nil, err
+	}
+	stockInfo.SetPrimaryBoard(response.Boards)
+	return stockInfo, nil
+}
+
+func unmarshalDividends(data []byte) ([]domain.Dividends, error) {
+	var response domain.DividendsApiResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return domain.ParseMoexDividends(response.Dividends), nil
 }
 
 func (m *MoexDataProvider) GetPriceAt(ticker string, date time.Time) (float64, error) {
 	from := date.AddDate(0, 0, -7).Format("2006-01-02")
 	till := date.Format("2006-01-02")
 	url := fmt.Sprintf("%s%s/candles.json?from=%s&till=%s&interval=24&iss.meta=off",
-		m.baseUrl, ticker, from, till)
+		m.securitiesUrl(ticker), ticker, from, till)
 
-	cache, err := m.redis.Get(context.TODO(), url).Result()
+	cache, err := m.getCached(url)
 	if err != nil && err != redis.Nil {
 		slog.Warn("redis get error", slog.String("key", url), slog.Any("err", err))
 	}
@@ -176,7 +385,7 @@ func (m *MoexDataProvider) GetPriceAt(ticker string, date time.Time) (float64, e
 	return candles[len(candles)-1].Close, nil
 }
 
-func (m *MoexDataProvider) GetMarketCap(ticker string) (float64, error) {
+func (m *MoexDataProvider) GetMarketCap(ctx context.Context, ticker string) (float64, error) {
 	price, err := m.GetStockPrice(ticker, 5, domain.Period(60))
 	if err != nil {
 		return 0, err
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS candles (
+    ticker VARCHAR(10) NOT NULL,
+    interval SMALLINT NOT NULL,
+    begin_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NOT NULL,
+
+    open NUMERIC(18, 6) NOT NULL,
+    close NUMERIC(18, 6) NOT NULL,
+    high NUMERIC(18, 6) NOT NULL,
+    low NUMERIC(18, 6) NOT NULL,
+    value NUMERIC(24, 2) NOT NULL,
+    volume NUMERIC(20, 0) NOT NULL,
+
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (ticker, interval, begin_at)
+);
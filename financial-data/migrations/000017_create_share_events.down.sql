@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trigger_update_share_events_updated_at ON share_events;
+DROP FUNCTION IF EXISTS update_share_events_updated_at();
+DROP INDEX IF EXISTS idx_share_events_ticker_date;
+DROP TABLE IF EXISTS share_events;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS share_events (
+    id SERIAL PRIMARY KEY,
+    ticker VARCHAR(10) NOT NULL,
+    date DATE NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    -- Количество акций в обращении после события
+    shares_outstanding BIGINT NOT NULL,
+    -- Новых акций на одну старую (для split/consolidation)
+    ratio NUMERIC(18, 8),
+    note TEXT,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_share_event UNIQUE (ticker, date, type)
+);
+
+CREATE INDEX idx_share_events_ticker_date ON share_events(ticker, date DESC);
+
+CREATE OR REPLACE FUNCTION update_share_events_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_update_share_events_updated_at
+    BEFORE UPDATE ON share_events
+    FOR EACH ROW
+    EXECUTE FUNCTION update_share_events_updated_at();
@@ -0,0 +1,4 @@
+ALTER TABLE companies
+    DROP COLUMN IF EXISTS issuer_ticker,
+    DROP COLUMN IF EXISTS instrument_type,
+    DROP COLUMN IF EXISTS board;
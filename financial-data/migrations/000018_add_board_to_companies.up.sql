@@ -0,0 +1,5 @@
+ALTER TABLE companies
+    ADD COLUMN board VARCHAR(12) NOT NULL DEFAULT 'TQBR',
+    ADD COLUMN instrument_type VARCHAR(20) NOT NULL DEFAULT 'share',
+    -- Тикер эмитента, чья отчётность используется (для привилегированных акций)
+    ADD COLUMN issuer_ticker VARCHAR(10);
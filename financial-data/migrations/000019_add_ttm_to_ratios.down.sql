@@ -0,0 +1,3 @@
+DELETE FROM ratios WHERE period = 'TTM';
+
+ALTER TABLE ratios DROP COLUMN ttm_through;
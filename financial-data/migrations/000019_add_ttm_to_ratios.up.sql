@@ -0,0 +1,3 @@
+-- period = 'TTM' rows hold ratios over the trailing twelve months, one per year,
+-- ending at the report stored in ttm_through
+ALTER TABLE ratios ADD COLUMN ttm_through VARCHAR(4);
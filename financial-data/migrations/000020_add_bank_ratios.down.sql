@@ -0,0 +1,6 @@
+ALTER TABLE ratios
+    DROP COLUMN company_type,
+    DROP COLUMN net_interest_margin,
+    DROP COLUMN cost_of_risk,
+    DROP COLUMN cost_to_income,
+    DROP COLUMN fee_income_share;
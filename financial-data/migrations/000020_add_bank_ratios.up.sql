@@ -0,0 +1,6 @@
+ALTER TABLE ratios
+    ADD COLUMN company_type VARCHAR(20),
+    ADD COLUMN net_interest_margin DECIMAL(15, 2),
+    ADD COLUMN cost_of_risk DECIMAL(15, 2),
+    ADD COLUMN cost_to_income DECIMAL(15, 2),
+    ADD COLUMN fee_income_share DECIMAL(15, 2);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS raw_data_versions (
+    id SERIAL PRIMARY KEY,
+    ticker VARCHAR(10) NOT NULL,
+    year INTEGER NOT NULL,
+    period VARCHAR(4) NOT NULL,
+    version INTEGER NOT NULL,
+    -- create, update, confirm, delete, rollback или baseline (состояние до начала истории)
+    action VARCHAR(20) NOT NULL,
+    -- admin, ai-service:task:<id> и т.п. (заголовок X-Actor)
+    actor VARCHAR(100) NOT NULL,
+    -- Полный снимок отчёта после изменения в формате JSON API
+    data JSONB NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_raw_data_version UNIQUE (ticker, year, period, version)
+);
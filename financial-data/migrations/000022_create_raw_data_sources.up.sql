@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS raw_data_sources (
+    id SERIAL PRIMARY KEY,
+    ticker VARCHAR(10) NOT NULL,
+    year INTEGER NOT NULL,
+    period VARCHAR(4) NOT NULL,
+    -- Отчёт, в котором опубликованы цифры: совпадает с периодом для исходных данных,
+    -- более поздний отчёт для пересчитанных (например, 2022 YEAR в отчёте за 2023 YEAR)
+    source_year INTEGER NOT NULL,
+    source_period VARCHAR(4) NOT NULL,
+    source_url TEXT,
+    -- Полный отчёт в формате JSON API
+    data JSONB NOT NULL,
+    -- Канонический источник скопирован в metrics и используется в расчётах;
+    -- смена канонического источника пишется в raw_data_versions с action = 'canonical'
+    is_canonical BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_raw_data_source UNIQUE (ticker, year, period, source_year, source_period)
+);
+
+CREATE UNIQUE INDEX idx_raw_data_sources_canonical ON raw_data_sources (ticker, year, period) WHERE is_canonical;
+CREATE INDEX idx_raw_data_sources_ticker ON raw_data_sources (ticker);
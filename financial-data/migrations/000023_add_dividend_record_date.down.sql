@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dividends_ex_date;
+ALTER TABLE dividends DROP COLUMN IF EXISTS record_date;
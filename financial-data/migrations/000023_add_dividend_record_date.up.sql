@@ -0,0 +1,4 @@
+-- Дата закрытия реестра: по ней с учётом режима расчётов T+1 (до 31.07.2023 — T+2) считается последний день покупки
+ALTER TABLE dividends ADD COLUMN record_date DATE;
+
+CREATE INDEX idx_dividends_ex_date ON dividends(ex_dividend_date);
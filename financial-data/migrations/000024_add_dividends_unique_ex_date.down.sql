@@ -0,0 +1,2 @@
+-- amount_per_share остаётся DECIMAL(16, 6): возврат к DECIMAL(10, 2) округлил бы дивиденды, загруженные из ISS
+ALTER TABLE dividends DROP CONSTRAINT IF EXISTS unique_dividend_ticker_ex_date;
@@ -0,0 +1,42 @@
+-- Импорт с MOEX ISS обновляет дивиденд по тикеру и дате отсечки, поэтому дубликаты убираются заранее.
+-- Дубликаты с разным размером или валютой не удаляются: миграция падает со списком, их нужно разобрать вручную.
+-- Из одинаковых остаётся запись с наименьшим id, удалённые пишутся в лог миграции.
+DO $$
+DECLARE
+    conflicts TEXT;
+    removed TEXT;
+BEGIN
+    SELECT string_agg(DISTINCT a.ticker || ' ' || a.ex_dividend_date, ', ')
+    INTO conflicts
+    FROM dividends a
+    JOIN dividends b ON a.ticker = b.ticker
+        AND a.ex_dividend_date = b.ex_dividend_date
+        AND a.id < b.id
+    WHERE a.amount_per_share <> b.amount_per_share
+       OR a.currency <> b.currency;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'dividends with the same ticker and ex-dividend date differ in amount or currency, resolve them before migrating: %', conflicts;
+    END IF;
+
+    WITH deleted AS (
+        DELETE FROM dividends a
+        USING dividends b
+        WHERE a.ticker = b.ticker
+          AND a.ex_dividend_date = b.ex_dividend_date
+          AND a.id > b.id
+        RETURNING a.id, a.ticker, a.ex_dividend_date, a.payment_date, a.amount_per_share, a.currency
+    )
+    SELECT string_agg(format('%s %s id=%s amount=%s %s payment=%s', ticker, ex_dividend_date, id, amount_per_share, currency, payment_date), '; ')
+    INTO removed
+    FROM deleted;
+
+    IF removed IS NOT NULL THEN
+        RAISE NOTICE 'removed duplicate dividends: %', removed;
+    END IF;
+END $$;
+
+ALTER TABLE dividends ADD CONSTRAINT unique_dividend_ticker_ex_date UNIQUE (ticker, ex_dividend_date);
+
+-- ISS отдаёт размер дивиденда точнее копейки (например, у ВТБ)
+ALTER TABLE dividends ALTER COLUMN amount_per_share TYPE DECIMAL(16, 6);
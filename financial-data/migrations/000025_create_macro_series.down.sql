@@ -0,0 +1 @@
+DROP TABLE IF EXISTS macro_series;
@@ -0,0 +1,9 @@
+-- Макроэкономические ряды: инфляция, курсы ЦБ, RUONIA, кривая ОФЗ. Ключевая ставка остаётся в cb_rates
+CREATE TABLE IF NOT EXISTS macro_series (
+    series VARCHAR(32) NOT NULL,
+    date DATE NOT NULL,
+    value DECIMAL(16, 6) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (series, date)
+);
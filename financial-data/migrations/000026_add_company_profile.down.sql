@@ -0,0 +1,10 @@
+ALTER TABLE companies
+    DROP COLUMN IF EXISTS refreshed_at,
+    DROP COLUMN IF EXISTS delisted_at,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS issue_size,
+    DROP COLUMN IF EXISTS free_float,
+    DROP COLUMN IF EXISTS list_level,
+    DROP COLUMN IF EXISTS reg_number,
+    DROP COLUMN IF EXISTS isin;
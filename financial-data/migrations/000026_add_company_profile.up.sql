@@ -0,0 +1,12 @@
+-- Профиль бумаги из описания ISS; free_float (доля акций в обращении, %) — из коэффициентов free-float MOEX или вручную
+ALTER TABLE companies
+    ADD COLUMN isin VARCHAR(12),
+    ADD COLUMN reg_number VARCHAR(64),
+    ADD COLUMN list_level SMALLINT,
+    ADD COLUMN free_float DECIMAL(5, 2),
+    ADD COLUMN issue_size BIGINT,
+    ADD COLUMN currency VARCHAR(3),
+    -- active или delisted — по статусу торгов на основном режиме
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active',
+    ADD COLUMN delisted_at DATE,
+    ADD COLUMN refreshed_at TIMESTAMP;
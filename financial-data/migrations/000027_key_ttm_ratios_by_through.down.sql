@@ -0,0 +1,10 @@
+-- по году остаётся только TTM по последнему отчёту
+DELETE FROM ratios r
+USING ratios later
+WHERE r.period = 'TTM'
+  AND later.ticker = r.ticker AND later.year = r.year AND later.period = 'TTM'
+  AND later.ttm_through > r.ttm_through;
+
+DROP INDEX ratios_ticker_year_period_ttm_through_key;
+
+ALTER TABLE ratios ADD PRIMARY KEY (ticker, year, period);
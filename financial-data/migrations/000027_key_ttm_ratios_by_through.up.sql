@@ -0,0 +1,5 @@
+-- TTM-коэффициенты хранятся по одному на каждый отчёт, которым заканчивается TTM (ttm_through),
+-- а не по одному на год: TTM по 3М не перезаписывается TTM по 6М того же года
+ALTER TABLE ratios DROP CONSTRAINT ratios_pkey;
+
+CREATE UNIQUE INDEX ratios_ticker_year_period_ttm_through_key ON ratios (ticker, year, period, COALESCE(ttm_through, ''));
@@ -1,19 +1,33 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"schedulerservice/internal/config"
+	"schedulerservice/internal/app"
 )
 
 func main() {
-	cfg, err := config.Load("tasks.yaml")
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	slog.SetDefault(logger)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	application, err := app.New(ctx)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("Failed to initialize app", "error", err)
+		os.Exit(1)
 	}
+	defer application.Shutdown()
 
-	log.Printf("Loaded %d tasks from config", len(cfg.Tasks))
-	for _, task := range cfg.Tasks {
-		log.Printf("- Task: %s, Cron: %s, Topic: %s", task.Name, task.Cron, task.Topic)
+	if err := application.Run(ctx); err != nil {
+		slog.Error("App stopped with error", "error", err)
+		os.Exit(1)
 	}
 }
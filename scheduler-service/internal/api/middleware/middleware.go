@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"schedulerservice/internal/api/response"
+)
+
+type contextKey string
+
+const (
+	contextKeyAPIKey = contextKey("api_key")
+)
+
+type MiddlewareConfig struct {
+	AdminAPIKey string
+}
+
+func NewMiddlewareConfig() (*MiddlewareConfig, error) {
+	apiKey := os.Getenv("ADMIN_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("ADMIN_API_KEY is not set")
+	}
+
+	return &MiddlewareConfig{AdminAPIKey: apiKey}, nil
+}
+
+func (m *MiddlewareConfig) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey := r.Header.Get("X-API-Key")
+		if apiKey == "" {
+			response.RespondWithError(w, r, http.StatusUnauthorized, "X-API-Key header is required", nil)
+			return
+		}
+
+		if apiKey != m.AdminAPIKey {
+			slog.Warn("Tried to access API with wrong api key", slog.String("key", apiKey))
+			response.RespondWithError(w, r, http.StatusUnauthorized, "Invalid API key", nil)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKeyAPIKey, apiKey)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"context"
+
+	"schedulerservice/internal/scheduler"
+)
+
+type JobScheduler interface {
+	Jobs(ctx context.Context) ([]scheduler.JobStatus, error)
+	Job(ctx context.Context, name string) (*scheduler.JobStatus, error)
+	Pause(ctx context.Context, name string) error
+	Resume(ctx context.Context, name string) error
+	Trigger(ctx context.Context, name string, message map[string]interface{}) error
+}
+
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
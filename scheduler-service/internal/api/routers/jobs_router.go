@@ -0,0 +1,126 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"schedulerservice/internal/api/middleware"
+	"schedulerservice/internal/api/response"
+	"schedulerservice/internal/scheduler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type JobsHandler struct {
+	scheduler JobScheduler
+	reloader  Reloader
+}
+
+type TriggerRequest struct {
+	Message map[string]interface{} `json:"message,omitempty"`
+}
+
+func NewJobsHandler(scheduler JobScheduler, reloader Reloader) *JobsHandler {
+	return &JobsHandler{scheduler: scheduler, reloader: reloader}
+}
+
+func RegisterJobsRoutes(r chi.Router, scheduler JobScheduler, reloader Reloader, m *middleware.MiddlewareConfig) {
+	handler := NewJobsHandler(scheduler, reloader)
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(m.AuthMiddleware)
+
+		protected.Get("/jobs", handler.HandleGetAll)
+		protected.Get("/jobs/{name}", handler.HandleGetByName)
+		protected.Post("/jobs/{name}/pause", handler.HandlePause)
+		protected.Post("/jobs/{name}/resume", handler.HandleResume)
+		protected.Post("/jobs/{name}/trigger", handler.HandleTrigger)
+		protected.Post("/jobs/reload", handler.HandleReload)
+	})
+}
+
+func (h *JobsHandler) HandleGetAll(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.scheduler.Jobs(r.Context())
+	if err != nil {
+		response.RespondWithError(w, r, http.StatusInternalServerError, "failed to load jobs", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, jobs, "Successfully retrieved jobs")
+}
+
+func (h *JobsHandler) HandleGetByName(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	job, err := h.scheduler.Job(r.Context(), name)
+	if err != nil {
+		respondWithJobError(w, r, "failed to load job", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, job, "Successfully retrieved job")
+}
+
+func (h *JobsHandler) HandlePause(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	if err := h.scheduler.Pause(r.Context(), name); err != nil {
+		respondWithJobError(w, r, "failed to pause job", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, nil, "Job successfully paused")
+}
+
+func (h *JobsHandler) HandleResume(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	if err := h.scheduler.Resume(r.Context(), name); err != nil {
+		respondWithJobError(w, r, "failed to resume job", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, nil, "Job successfully resumed")
+}
+
+func (h *JobsHandler) HandleTrigger(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req TriggerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.scheduler.Trigger(r.Context(), name, req.Message); err != nil {
+		respondWithJobError(w, r, "failed to trigger job", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, nil, "Job successfully triggered")
+}
+
+func (h *JobsHandler) HandleReload(w http.ResponseWriter, r *http.Request) {
+	if err := h.reloader.Reload(r.Context()); err != nil {
+		response.RespondWithError(w, r, http.StatusUnprocessableEntity, "failed to reload tasks: "+err.Error(), err)
+		return
+	}
+
+	jobs, err := h.scheduler.Jobs(r.Context())
+	if err != nil {
+		response.RespondWithError(w, r, http.StatusInternalServerError, "tasks reloaded, but failed to load jobs", err)
+		return
+	}
+
+	response.RespondWithSuccess(w, http.StatusOK, jobs, "Tasks successfully reloaded")
+}
+
+func respondWithJobError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		response.RespondWithError(w, r, http.StatusNotFound, "job not found", err)
+		return
+	}
+	response.RespondWithError(w, r, http.StatusInternalServerError, message, err)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	myMiddleware "schedulerservice/internal/api/middleware"
+	"schedulerservice/internal/api/routers"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Server struct {
+	srv *http.Server
+}
+
+func NewServer(port string, scheduler routers.JobScheduler, reloader routers.Reloader) (*Server, error) {
+	m, err := myMiddleware.NewMiddlewareConfig()
+	if err != nil {
+		return nil, fmt.Errorf("create middleware config: %w", err)
+	}
+
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.Timeout(60 * time.Second))
+
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"healthy"}`))
+	})
+
+	routers.RegisterJobsRoutes(r, scheduler, reloader, m)
+
+	return &Server{
+		srv: &http.Server{
+			Addr:         ":" + port,
+			Handler:      r,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 60 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		},
+	}, nil
+}
+
+func (s *Server) Run() error {
+	slog.Info("starting server", "address", s.srv.Addr)
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"schedulerservice/internal/api"
+	"schedulerservice/internal/config"
+	"schedulerservice/internal/financialdata"
+	"schedulerservice/internal/kafka"
+	"schedulerservice/internal/leader"
+	"schedulerservice/internal/redis"
+	"schedulerservice/internal/scheduler"
+)
+
+type App struct {
+	tasksPath   string
+	scheduler   *scheduler.Scheduler
+	elector     *leader.Elector
+	reloadBus   *redis.ReloadBus
+	server      *api.Server
+	producer    *kafka.Producer
+	redisClient *redis.Client
+}
+
+func New(ctx context.Context) (*App, error) {
+	a := &App{tasksPath: getEnv("TASKS_PATH", "tasks.yaml")}
+
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := redis.NewClient(ctx, getEnv("REDIS_URL", "redis:6379"), os.Getenv("REDIS_PASSWORD"))
+	if err != nil {
+		return nil, fmt.Errorf("create redis client: %w", err)
+	}
+
+	leaseTTL := parseDuration("LEADER_LEASE_TTL", "15s")
+	instanceID := getEnv("INSTANCE_ID", defaultInstanceID())
+	lease := redis.NewLease(redisClient, instanceID, leaseTTL)
+	elector := leader.NewElector(lease, leaseTTL/3)
+	stateStore := redis.NewStateStore(redisClient)
+
+	slog.Info("leader election configured", "instance_id", instanceID, "lease_ttl", leaseTTL)
+
+	brokers := strings.Split(getEnv("KAFKA_URL", "kafka:9092"), ",")
+	producer := kafka.NewProducer(brokers)
+
+	fdClient := financialdata.NewClient(getEnv("FINANCIAL_DATA_URL", "http://financial-data:8082"))
+
+	s, err := scheduler.New(cfg, producer, fdClient, elector, stateStore)
+	if err != nil {
+		producer.Close()
+		redisClient.Close()
+		return nil, fmt.Errorf("create scheduler: %w", err)
+	}
+
+	server, err := api.NewServer(getEnv("SERVER_PORT", "8084"), s, a)
+	if err != nil {
+		producer.Close()
+		redisClient.Close()
+		return nil, fmt.Errorf("create http server: %w", err)
+	}
+
+	a.scheduler = s
+	a.elector = elector
+	a.reloadBus = redis.NewReloadBus(redisClient, instanceID)
+	a.server = server
+	a.producer = producer
+	a.redisClient = redisClient
+	return a, nil
+}
+
+func (a *App) Run(ctx context.Context) error {
+	a.scheduler.Start()
+	slog.Info("scheduler started")
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		serverErrors <- a.server.Run()
+	}()
+
+	go a.reloadBus.Listen(ctx, func() {
+		if err := a.reloadLocal(); err != nil {
+			slog.Error("failed to reload tasks", "error", err)
+		}
+	})
+
+	electorDone := make(chan struct{})
+	go func() {
+		a.elector.Run(ctx, a.scheduler.CatchUp)
+		close(electorDone)
+	}()
+
+	select {
+	case err := <-serverErrors:
+		return fmt.Errorf("http server: %w", err)
+	case <-electorDone:
+		slog.Info("shutdown signal received")
+	}
+	return nil
+}
+
+// Reload re-reads the tasks file and reschedules jobs on this instance, then
+// asks the other replicas to do the same, since the leader that fires the jobs
+// may be any of them. The tasks file is expected to be shared by all replicas.
+func (a *App) Reload(ctx context.Context) error {
+	if err := a.reloadLocal(); err != nil {
+		return err
+	}
+	if err := a.reloadBus.Broadcast(ctx); err != nil {
+		return fmt.Errorf("tasks reloaded on this instance only: %w", err)
+	}
+	return nil
+}
+
+func (a *App) reloadLocal() error {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+	return a.scheduler.Reload(cfg)
+}
+
+func (a *App) Shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		slog.Error("failed to shutdown http server", "error", err)
+	}
+
+	if err := a.scheduler.Stop(ctx); err != nil {
+		slog.Error("failed to wait for running tasks", "error", err)
+	}
+
+	if err := a.producer.Close(); err != nil {
+		slog.Error("failed to close kafka producer", "error", err)
+	}
+
+	if err := a.redisClient.Close(); err != nil {
+		slog.Error("failed to close redis client", "error", err)
+	}
+
+	slog.Info("scheduler stopped gracefully")
+}
+
+func (a *App) loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(a.tasksPath)
+	if err != nil {
+		return nil, fmt.Errorf("load tasks from %s: %w", a.tasksPath, err)
+	}
+
+	if tz := os.Getenv("SCHEDULER_TIMEZONE"); tz != "" {
+		cfg.Timezone = tz
+	}
+
+	slog.Info("tasks loaded", "path", a.tasksPath, "count", len(cfg.Tasks), "timezone", cfg.Timezone)
+	return cfg, nil
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func parseDuration(key, fallback string) time.Duration {
+	raw := getEnv(key, fallback)
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		d, _ = time.ParseDuration(fallback)
+	}
+	return d
+}
+
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
@@ -1,21 +1,63 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+const DefaultTimezone = "Europe/Moscow"
+
+// MisfirePolicy decides what happens to runs missed while no instance was leading.
+type MisfirePolicy string
+
+const (
+	MisfireSkip     MisfirePolicy = "skip"
+	MisfireFireOnce MisfirePolicy = "fire_once"
+)
+
+func (p MisfirePolicy) IsValid() bool {
+	switch p {
+	case MisfireSkip, MisfireFireOnce:
+		return true
+	default:
+		return false
+	}
+}
+
+// FanOut makes a task publish one message per item of the given source.
+type FanOut string
+
+const (
+	FanOutNone      FanOut = ""
+	FanOutCompanies FanOut = "companies"
+)
+
+func (f FanOut) IsValid() bool {
+	switch f {
+	case FanOutNone, FanOutCompanies:
+		return true
+	default:
+		return false
+	}
+}
+
 type Task struct {
-	Name    string                 `yaml:"name"`
-	Cron    string                 `yaml:"cron"`
-	Topic   string                 `yaml:"topic"`
-	Message map[string]interface{} `yaml:"message"`
+	Name          string                 `yaml:"name"`
+	Cron          string                 `yaml:"cron"`
+	Topic         string                 `yaml:"topic"`
+	MisfirePolicy MisfirePolicy          `yaml:"misfire_policy"`
+	FanOut        FanOut                 `yaml:"fan_out"`
+	Message       map[string]interface{} `yaml:"message"`
 }
 
 type Config struct {
-	Tasks []Task `yaml:"tasks"`
+	Timezone string `yaml:"timezone"`
+	Tasks    []Task `yaml:"tasks"`
 }
 
 func Load(path string) (*Config, error) {
@@ -36,5 +78,62 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.Timezone == "" {
+		cfg.Timezone = DefaultTimezone
+	}
+	for i := range cfg.Tasks {
+		if cfg.Tasks[i].MisfirePolicy == "" {
+			cfg.Tasks[i].MisfirePolicy = MisfireSkip
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
+
+func (c *Config) Validate() error {
+	if _, err := c.Location(); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(c.Tasks))
+	for i, task := range c.Tasks {
+		if task.Name == "" {
+			return fmt.Errorf("task #%d: name is required", i)
+		}
+		if _, ok := names[task.Name]; ok {
+			return fmt.Errorf("task %s: duplicate name", task.Name)
+		}
+		names[task.Name] = struct{}{}
+
+		if task.Cron == "" {
+			return fmt.Errorf("task %s: cron is required", task.Name)
+		}
+		if task.Topic == "" {
+			return fmt.Errorf("task %s: topic is required", task.Name)
+		}
+		if !task.MisfirePolicy.IsValid() {
+			return fmt.Errorf("task %s: invalid misfire_policy %q (allowed: skip, fire_once)", task.Name, task.MisfirePolicy)
+		}
+		if !task.FanOut.IsValid() {
+			return fmt.Errorf("task %s: invalid fan_out %q (allowed: companies)", task.Name, task.FanOut)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return nil, errors.New("timezone is not set")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load timezone %s: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
@@ -24,6 +24,12 @@ func TestLoadConfig(t *testing.T) {
 	if task1.Topic != "parser.find_reports" {
 		t.Errorf("Expected topic 'parser.find_reports', got '%s'", task1.Topic)
 	}
+	if task1.MisfirePolicy != MisfireFireOnce {
+		t.Errorf("Expected misfire policy 'fire_once', got '%s'", task1.MisfirePolicy)
+	}
+	if cfg.Timezone != DefaultTimezone {
+		t.Errorf("Expected timezone '%s', got '%s'", DefaultTimezone, cfg.Timezone)
+	}
 	if task1.Message["action"] != "find_new_reports" {
 		t.Errorf("Expected action 'find_new_reports', got '%v'", task1.Message["action"])
 	}
@@ -0,0 +1,63 @@
+package financialdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"schedulerservice/internal/message"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type company struct {
+	Ticker   string `json:"ticker"`
+	Name     string `json:"name"`
+	SectorID int    `json:"sectorId"`
+}
+
+func (c *Client) ListCompanies(ctx context.Context) ([]message.Company, error) {
+	url := fmt.Sprintf("%s/companies", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call financial-data API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("financial-data API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []company `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	companies := make([]message.Company, 0, len(result.Data))
+	for _, c := range result.Data {
+		companies = append(companies, message.Company{Ticker: c.Ticker, Name: c.Name, SectorID: c.SectorID})
+	}
+	return companies, nil
+}
@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/compress"
+)
+
+type Producer struct {
+	writer *kafka.Writer
+}
+
+func NewProducer(brokers []string) *Producer {
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Balancer:               &kafka.LeastBytes{},
+		RequiredAcks:           kafka.RequireAll,
+		MaxAttempts:            10,
+		WriteBackoffMin:        100 * time.Millisecond,
+		WriteBackoffMax:        2 * time.Second,
+		BatchTimeout:           10 * time.Millisecond,
+		Compression:            compress.Snappy,
+		Async:                  false,
+		AllowAutoTopicCreation: true,
+	}
+	return &Producer{writer: writer}
+}
+
+func (p *Producer) Publish(ctx context.Context, topic string, key, value []byte) error {
+	err := p.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+	})
+	if err != nil {
+		return fmt.Errorf("kafka publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+func (p *Producer) Close() error {
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("kafka producer close: %w", err)
+	}
+	return nil
+}
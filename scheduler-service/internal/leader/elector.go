@@ -0,0 +1,97 @@
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+type Lock interface {
+	Acquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+// Elector keeps renewing a distributed lock so that only one scheduler
+// replica fires tasks at a time.
+type Elector struct {
+	lock     Lock
+	interval time.Duration
+	leader   atomic.Bool
+
+	// endTerm cancels the context given to onElected once leadership is lost.
+	// It is only touched by the goroutine running Run.
+	endTerm context.CancelFunc
+}
+
+func NewElector(lock Lock, interval time.Duration) *Elector {
+	return &Elector{lock: lock, interval: interval}
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run blocks until ctx is done. onElected is started in its own goroutine
+// every time this instance becomes the leader, so that renewing the lease does
+// not wait for it; its context is cancelled when leadership is lost.
+func (e *Elector) Run(ctx context.Context, onElected func(ctx context.Context)) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.tick(ctx, onElected)
+	for {
+		select {
+		case <-ctx.Done():
+			e.stopTerm()
+			e.resign()
+			return
+		case <-ticker.C:
+			e.tick(ctx, onElected)
+		}
+	}
+}
+
+func (e *Elector) tick(ctx context.Context, onElected func(ctx context.Context)) {
+	acquired, err := e.lock.Acquire(ctx)
+	if err != nil {
+		slog.Error("leader: failed to acquire lock", "error", err)
+		acquired = false
+	}
+
+	wasLeader := e.leader.Swap(acquired)
+	switch {
+	case acquired && !wasLeader:
+		slog.Info("leader: became leader")
+		if onElected != nil {
+			termCtx, cancel := context.WithCancel(ctx)
+			e.endTerm = cancel
+			go onElected(termCtx)
+		}
+	case !acquired && wasLeader:
+		slog.Warn("leader: lost leadership")
+		e.stopTerm()
+	}
+}
+
+func (e *Elector) stopTerm() {
+	if e.endTerm != nil {
+		e.endTerm()
+		e.endTerm = nil
+	}
+}
+
+func (e *Elector) resign() {
+	if !e.leader.Swap(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := e.lock.Release(ctx); err != nil {
+		slog.Error("leader: failed to release lock", "error", err)
+		return
+	}
+	slog.Info("leader: resigned")
+}
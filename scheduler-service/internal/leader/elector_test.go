@@ -0,0 +1,56 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeLock struct {
+	held atomic.Bool
+}
+
+func (l *fakeLock) Acquire(_ context.Context) (bool, error) {
+	return l.held.Load(), nil
+}
+
+func (l *fakeLock) Release(_ context.Context) error {
+	return nil
+}
+
+func TestElectorRenewsWhileOnElectedRuns(t *testing.T) {
+	lock := &fakeLock{}
+	lock.held.Store(true)
+	e := NewElector(lock, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	go e.Run(ctx, func(termCtx context.Context) {
+		close(started)
+		<-termCtx.Done()
+		close(stopped)
+	})
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("onElected was not called")
+	}
+	if !e.IsLeader() {
+		t.Fatal("Expected to be leader")
+	}
+
+	lock.held.Store(false)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected onElected context to be cancelled after losing leadership")
+	}
+	if e.IsLeader() {
+		t.Error("Expected leadership to be lost")
+	}
+}
@@ -0,0 +1,135 @@
+package message
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data is what message templates can refer to, e.g. {{.Now}},
+// {{.Date "2006-01-02"}} or {{.Quarter}}.
+type Data struct {
+	Now     string
+	Year    int
+	Quarter int
+
+	now time.Time
+}
+
+// CompanyData adds the company to Data for fan-out tasks, e.g. {{.Ticker}}.
+// Other tasks render against Data alone, so company fields fail there.
+type CompanyData struct {
+	Data
+	Ticker   string
+	Name     string
+	SectorID int
+}
+
+type Company struct {
+	Ticker   string
+	Name     string
+	SectorID int
+}
+
+func NewData(now time.Time) Data {
+	return Data{
+		Now:     now.Format(time.RFC3339),
+		Year:    now.Year(),
+		Quarter: (int(now.Month())-1)/3 + 1,
+		now:     now,
+	}
+}
+
+func (d Data) WithCompany(c Company) CompanyData {
+	return CompanyData{Data: d, Ticker: c.Ticker, Name: c.Name, SectorID: c.SectorID}
+}
+
+func (d Data) Date(layout string) string {
+	return d.now.Format(layout)
+}
+
+func (d Data) UUID() string {
+	return uuid.New().String()
+}
+
+// Render returns a copy of msg with every templated string value executed
+// against data, a Data or a CompanyData. Nested maps and lists are rendered
+// recursively.
+func Render(msg map[string]interface{}, data interface{}) (map[string]interface{}, error) {
+	if msg == nil {
+		return nil, nil
+	}
+
+	rendered, err := renderValue(msg, data, "")
+	if err != nil {
+		return nil, err
+	}
+	return rendered.(map[string]interface{}), nil
+}
+
+// Validate checks that every templated value in msg parses and refers to
+// company fields only when the task fans out per company.
+func Validate(msg map[string]interface{}, perCompany bool) error {
+	var data interface{} = NewData(time.Now())
+	if perCompany {
+		data = NewData(time.Now()).WithCompany(Company{Ticker: "TICKER", Name: "Name", SectorID: 1})
+	}
+	_, err := Render(msg, data)
+	return err
+}
+
+func renderValue(value interface{}, data interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderString(v, data, path)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			rendered, err := renderValue(item, data, joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			result[key] = rendered
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := renderValue(item, data, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = rendered
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+func renderString(value string, data interface{}, path string) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("parse template at %s: %w", path, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("execute template at %s: %w", path, err)
+	}
+	return sb.String(), nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
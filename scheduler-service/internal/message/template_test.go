@@ -0,0 +1,74 @@
+package message
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2025, time.August, 14, 9, 0, 0, 0, time.UTC)
+	data := NewData(now).WithCompany(Company{Ticker: "SBER", Name: "Сбербанк", SectorID: 2})
+
+	msg := map[string]interface{}{
+		"action": "refresh_news",
+		"ticker": "{{.Ticker}}",
+		"date":   `{{.Date "2006-01-02"}}`,
+		"period": "{{.Year}}-Q{{.Quarter}}",
+		"limit":  10,
+		"nested": map[string]interface{}{"at": "{{.Now}}"},
+		"tags":   []interface{}{"{{.Name}}", "static"},
+	}
+
+	rendered, err := Render(msg, data)
+	if err != nil {
+		t.Fatalf("Failed to render message: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"action": "refresh_news",
+		"ticker": "SBER",
+		"date":   "2025-08-14",
+		"period": "2025-Q3",
+		"limit":  10,
+	}
+	for key, value := range expected {
+		if rendered[key] != value {
+			t.Errorf("Expected %s '%v', got '%v'", key, value, rendered[key])
+		}
+	}
+
+	nested := rendered["nested"].(map[string]interface{})
+	if nested["at"] != "2025-08-14T09:00:00Z" {
+		t.Errorf("Expected nested.at '2025-08-14T09:00:00Z', got '%v'", nested["at"])
+	}
+
+	tags := rendered["tags"].([]interface{})
+	if tags[0] != "Сбербанк" || tags[1] != "static" {
+		t.Errorf("Expected tags [Сбербанк static], got %v", tags)
+	}
+
+	if msg["ticker"] != "{{.Ticker}}" {
+		t.Errorf("Expected source message to stay untouched, got ticker '%v'", msg["ticker"])
+	}
+}
+
+func TestRenderUnknownField(t *testing.T) {
+	_, err := Render(map[string]interface{}{"x": "{{.Unknown}}"}, NewData(time.Now()))
+	if err == nil {
+		t.Fatal("Expected error for unknown template field, got nil")
+	}
+}
+
+func TestValidateRejectsCompanyFieldsWithoutFanOut(t *testing.T) {
+	msg := map[string]interface{}{"ticker": "{{.Ticker}}", "date": `{{.Date "2006-01-02"}}`}
+
+	if err := Validate(msg, true); err != nil {
+		t.Errorf("Expected company fields to be valid for fan-out task, got %v", err)
+	}
+	if err := Validate(msg, false); err == nil {
+		t.Error("Expected error for company field in task without fan-out, got nil")
+	}
+	if err := Validate(map[string]interface{}{"date": `{{.Date "2006-01-02"}}`}, false); err != nil {
+		t.Errorf("Expected plain template to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const leaseKey = "scheduler:leader"
+
+// acquireScript extends the lease when it is already held by this instance
+// and takes it over only when nobody holds it.
+var acquireScript = redis.NewScript(`
+local owner = redis.call("GET", KEYS[1])
+if owner == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if owner == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type Lease struct {
+	rdb *redis.Client
+	id  string
+	ttl time.Duration
+}
+
+func NewLease(c *Client, id string, ttl time.Duration) *Lease {
+	return &Lease{rdb: c.rdb, id: id, ttl: ttl}
+}
+
+func (l *Lease) Acquire(ctx context.Context) (bool, error) {
+	res, err := acquireScript.Run(ctx, l.rdb, []string{leaseKey}, l.id, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("acquire lease: %w", err)
+	}
+	return res == 1, nil
+}
+
+func (l *Lease) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.rdb, []string{leaseKey}, l.id).Err(); err != nil {
+		return fmt.Errorf("release lease: %w", err)
+	}
+	return nil
+}
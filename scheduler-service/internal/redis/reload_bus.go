@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const reloadChannel = "scheduler:reload"
+
+// ReloadBus tells the other replicas to re-read the tasks file after an admin
+// reload, so the leader picks it up whichever replica served the request.
+type ReloadBus struct {
+	rdb *redis.Client
+	id  string
+}
+
+func NewReloadBus(c *Client, id string) *ReloadBus {
+	return &ReloadBus{rdb: c.rdb, id: id}
+}
+
+func (b *ReloadBus) Broadcast(ctx context.Context) error {
+	if err := b.rdb.Publish(ctx, reloadChannel, b.id).Err(); err != nil {
+		return fmt.Errorf("publish reload: %w", err)
+	}
+	return nil
+}
+
+// Listen calls onReload for every reload broadcast by another instance until
+// ctx is done.
+func (b *ReloadBus) Listen(ctx context.Context, onReload func()) {
+	sub := b.rdb.Subscribe(ctx, reloadChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if msg.Payload == b.id {
+				continue
+			}
+			slog.Info("reload requested by another instance", "instance_id", msg.Payload)
+			onReload()
+		}
+	}
+}
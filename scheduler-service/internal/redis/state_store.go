@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"schedulerservice/internal/scheduler"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	lastFiredKey = "scheduler:last_fired"
+	outcomeKey   = "scheduler:last_outcome"
+	pausedKey    = "scheduler:paused"
+	pendingKey   = "scheduler:pending_tickers"
+)
+
+type StateStore struct {
+	rdb *redis.Client
+}
+
+func NewStateStore(c *Client) *StateStore {
+	return &StateStore{rdb: c.rdb}
+}
+
+func (s *StateStore) GetLastFired(ctx context.Context, task string) (time.Time, bool, error) {
+	raw, err := s.rdb.HGet(ctx, lastFiredKey, task).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("get last fired for %s: %w", task, err)
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse last fired for %s: %w", task, err)
+	}
+	return at, true, nil
+}
+
+func (s *StateStore) SetLastFired(ctx context.Context, task string, at time.Time) error {
+	if err := s.rdb.HSet(ctx, lastFiredKey, task, at.UTC().Format(time.RFC3339Nano)).Err(); err != nil {
+		return fmt.Errorf("set last fired for %s: %w", task, err)
+	}
+	return nil
+}
+
+func (s *StateStore) GetOutcome(ctx context.Context, task string) (*scheduler.Outcome, error) {
+	raw, err := s.rdb.HGet(ctx, outcomeKey, task).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get outcome for %s: %w", task, err)
+	}
+
+	var outcome scheduler.Outcome
+	if err := json.Unmarshal(raw, &outcome); err != nil {
+		return nil, fmt.Errorf("unmarshal outcome for %s: %w", task, err)
+	}
+	return &outcome, nil
+}
+
+func (s *StateStore) SetOutcome(ctx context.Context, task string, outcome scheduler.Outcome) error {
+	data, err := json.Marshal(outcome)
+	if err != nil {
+		return fmt.Errorf("marshal outcome for %s: %w", task, err)
+	}
+	if err := s.rdb.HSet(ctx, outcomeKey, task, data).Err(); err != nil {
+		return fmt.Errorf("set outcome for %s: %w", task, err)
+	}
+	return nil
+}
+
+func (s *StateStore) IsPaused(ctx context.Context, task string) (bool, error) {
+	paused, err := s.rdb.SIsMember(ctx, pausedKey, task).Result()
+	if err != nil {
+		return false, fmt.Errorf("get pause state for %s: %w", task, err)
+	}
+	return paused, nil
+}
+
+func (s *StateStore) SetPaused(ctx context.Context, task string, paused bool) error {
+	var err error
+	if paused {
+		err = s.rdb.SAdd(ctx, pausedKey, task).Err()
+	} else {
+		err = s.rdb.SRem(ctx, pausedKey, task).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("set pause state for %s: %w", task, err)
+	}
+	return nil
+}
+
+func (s *StateStore) GetPendingTickers(ctx context.Context, task string) ([]string, error) {
+	raw, err := s.rdb.HGet(ctx, pendingKey, task).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pending tickers for %s: %w", task, err)
+	}
+
+	var tickers []string
+	if err := json.Unmarshal(raw, &tickers); err != nil {
+		return nil, fmt.Errorf("unmarshal pending tickers for %s: %w", task, err)
+	}
+	return tickers, nil
+}
+
+func (s *StateStore) SetPendingTickers(ctx context.Context, task string, tickers []string) error {
+	if len(tickers) == 0 {
+		if err := s.rdb.HDel(ctx, pendingKey, task).Err(); err != nil {
+			return fmt.Errorf("clear pending tickers for %s: %w", task, err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(tickers)
+	if err != nil {
+		return fmt.Errorf("marshal pending tickers for %s: %w", task, err)
+	}
+	if err := s.rdb.HSet(ctx, pendingKey, task, data).Err(); err != nil {
+		return fmt.Errorf("set pending tickers for %s: %w", task, err)
+	}
+	return nil
+}
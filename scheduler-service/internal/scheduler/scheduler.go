@@ -0,0 +1,432 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"schedulerservice/internal/config"
+	"schedulerservice/internal/message"
+
+	"github.com/robfig/cron/v3"
+)
+
+const publishTimeout = 2 * time.Minute
+
+// fanOutAttempts is how many times a fan-out publishes to the tickers that
+// failed before giving up on them for the run.
+const fanOutAttempts = 3
+
+var fanOutRetryDelay = 2 * time.Second
+
+var ErrJobNotFound = errors.New("job not found")
+
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key, value []byte) error
+}
+
+type CompanyLister interface {
+	ListCompanies(ctx context.Context) ([]message.Company, error)
+}
+
+type Leader interface {
+	IsLeader() bool
+}
+
+type StateStore interface {
+	GetLastFired(ctx context.Context, task string) (time.Time, bool, error)
+	SetLastFired(ctx context.Context, task string, at time.Time) error
+	GetOutcome(ctx context.Context, task string) (*Outcome, error)
+	SetOutcome(ctx context.Context, task string, outcome Outcome) error
+	IsPaused(ctx context.Context, task string) (bool, error)
+	SetPaused(ctx context.Context, task string, paused bool) error
+	// Pending tickers are the ones a fan-out run failed to deliver to; the
+	// catch-up of that run publishes to them only.
+	GetPendingTickers(ctx context.Context, task string) ([]string, error)
+	SetPendingTickers(ctx context.Context, task string, tickers []string) error
+}
+
+type job struct {
+	task     config.Task
+	schedule cron.Schedule
+	entryID  cron.EntryID
+}
+
+type Scheduler struct {
+	cron      *cron.Cron
+	publisher Publisher
+	companies CompanyLister
+	leader    Leader
+	store     StateStore
+
+	mu       sync.RWMutex
+	location *time.Location
+	jobs     map[string]*job
+	order    []string
+}
+
+func New(cfg *config.Config, publisher Publisher, companies CompanyLister, leader Leader, store StateStore) (*Scheduler, error) {
+	s := &Scheduler{
+		cron:      cron.New(),
+		publisher: publisher,
+		companies: companies,
+		leader:    leader,
+		store:     store,
+	}
+
+	if err := s.Reload(cfg); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop prevents new runs and waits for in-flight ones until ctx is done.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	done := s.cron.Stop()
+	select {
+	case <-done.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reload replaces the scheduled tasks with the ones from cfg. Nothing is
+// changed when any of the new cron expressions or message templates is invalid.
+func (s *Scheduler) Reload(cfg *config.Config) error {
+	loc, err := cfg.Location()
+	if err != nil {
+		return err
+	}
+
+	jobs := make(map[string]*job, len(cfg.Tasks))
+	order := make([]string, 0, len(cfg.Tasks))
+	for _, task := range cfg.Tasks {
+		schedule, err := cron.ParseStandard(task.Cron)
+		if err != nil {
+			return fmt.Errorf("task %s: parse cron %q: %w", task.Name, task.Cron, err)
+		}
+		if spec, ok := schedule.(*cron.SpecSchedule); ok {
+			spec.Location = loc
+		}
+		if err := message.Validate(task.Message, task.FanOut == config.FanOutCompanies); err != nil {
+			return fmt.Errorf("task %s: %w", task.Name, err)
+		}
+
+		jobs[task.Name] = &job{task: task, schedule: schedule}
+		order = append(order, task.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		s.cron.Remove(j.entryID)
+	}
+
+	for _, name := range order {
+		j := jobs[name]
+		j.entryID = s.cron.Schedule(j.schedule, cron.FuncJob(func() { s.run(name) }))
+		slog.Info("task scheduled", "task", name, "cron", j.task.Cron, "topic", j.task.Topic, "timezone", loc.String())
+	}
+
+	s.location = loc
+	s.jobs = jobs
+	s.order = order
+	return nil
+}
+
+// CatchUp handles runs missed while no instance was leading, according to
+// each task's misfire policy. It is called whenever this instance becomes leader
+// and stops early once ctx is cancelled because leadership was lost.
+func (s *Scheduler) CatchUp(ctx context.Context) {
+	loc := s.currentLocation()
+	now := time.Now().In(loc)
+
+	for _, j := range s.snapshot() {
+		if ctx.Err() != nil {
+			slog.Warn("catch-up: interrupted", "task", j.task.Name, "error", ctx.Err())
+			return
+		}
+
+		last, ok, err := s.store.GetLastFired(ctx, j.task.Name)
+		if err != nil {
+			slog.Error("catch-up: failed to get last fired time", "task", j.task.Name, "error", err)
+			continue
+		}
+
+		if !ok {
+			s.markFired(ctx, j.task.Name, now)
+			continue
+		}
+
+		missed := j.schedule.Next(last.In(loc))
+		if missed.After(now) {
+			continue
+		}
+
+		paused, err := s.store.IsPaused(ctx, j.task.Name)
+		if err != nil {
+			slog.Error("catch-up: failed to get pause state", "task", j.task.Name, "error", err)
+			continue
+		}
+
+		switch {
+		case paused:
+			slog.Info("catch-up: task is paused, skipping missed run", "task", j.task.Name, "missed_at", missed)
+			s.markFired(ctx, j.task.Name, now)
+		case j.task.MisfirePolicy == config.MisfireFireOnce:
+			slog.Info("catch-up: firing missed run", "task", j.task.Name, "missed_at", missed, "last_fired", last)
+			if s.fireAndRecord(ctx, j.task, j.task.Message, TriggerCatchUp) {
+				s.markFired(ctx, j.task.Name, now)
+			}
+		default:
+			slog.Warn("catch-up: skipping missed run", "task", j.task.Name, "missed_at", missed, "last_fired", last)
+			s.markFired(ctx, j.task.Name, now)
+		}
+	}
+}
+
+// Trigger fires the task immediately regardless of leadership and schedule.
+// A non-nil message replaces the one configured for the task.
+func (s *Scheduler) Trigger(ctx context.Context, name string, msg map[string]interface{}) error {
+	j, ok := s.lookup(name)
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	if msg == nil {
+		msg = j.task.Message
+	}
+
+	if err := s.fire(ctx, j.task, msg, TriggerManual); err != nil {
+		s.recordOutcome(ctx, j.task.Name, newOutcome(TriggerManual, err))
+		return err
+	}
+
+	s.recordOutcome(ctx, j.task.Name, newOutcome(TriggerManual, nil))
+	return nil
+}
+
+func (s *Scheduler) Pause(ctx context.Context, name string) error {
+	return s.setPaused(ctx, name, true)
+}
+
+func (s *Scheduler) Resume(ctx context.Context, name string) error {
+	return s.setPaused(ctx, name, false)
+}
+
+func (s *Scheduler) setPaused(ctx context.Context, name string, paused bool) error {
+	if _, ok := s.lookup(name); !ok {
+		return ErrJobNotFound
+	}
+	return s.store.SetPaused(ctx, name, paused)
+}
+
+func (s *Scheduler) run(name string) {
+	j, ok := s.lookup(name)
+	if !ok {
+		return
+	}
+
+	if !s.leader.IsLeader() {
+		slog.Debug("not a leader, skipping task", "task", name)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	loc := s.currentLocation()
+	now := time.Now().In(loc)
+
+	// another replica or a catch-up may have already covered this slot
+	last, ok, err := s.store.GetLastFired(ctx, name)
+	if err != nil {
+		slog.Warn("failed to get last fired time, firing anyway", "task", name, "error", err)
+	} else if ok && j.schedule.Next(last.In(loc)).After(now) {
+		slog.Info("task already fired for this slot", "task", name, "last_fired", last)
+		return
+	}
+
+	paused, err := s.store.IsPaused(ctx, name)
+	if err != nil {
+		slog.Warn("failed to get pause state, firing anyway", "task", name, "error", err)
+	}
+	if paused {
+		slog.Info("task is paused, skipping", "task", name)
+		s.recordOutcome(ctx, name, Outcome{At: time.Now(), Trigger: TriggerSchedule, Status: StatusPaused})
+		s.markFired(ctx, name, now)
+		return
+	}
+
+	if s.fireAndRecord(ctx, j.task, j.task.Message, TriggerSchedule) {
+		s.markFired(ctx, name, now)
+	}
+}
+
+func (s *Scheduler) fireAndRecord(ctx context.Context, task config.Task, msg map[string]interface{}, trigger string) bool {
+	err := s.fire(ctx, task, msg, trigger)
+	s.recordOutcome(ctx, task.Name, newOutcome(trigger, err))
+	if err != nil {
+		slog.Error("task failed", "task", task.Name, "topic", task.Topic, "trigger", trigger, "error", err)
+		return false
+	}
+
+	slog.Info("task fired", "task", task.Name, "topic", task.Topic, "trigger", trigger)
+	return true
+}
+
+func (s *Scheduler) markFired(ctx context.Context, name string, at time.Time) {
+	if err := s.store.SetLastFired(ctx, name, at); err != nil {
+		slog.Error("failed to save last fired time", "task", name, "error", err)
+	}
+}
+
+func (s *Scheduler) recordOutcome(ctx context.Context, name string, outcome Outcome) {
+	if err := s.store.SetOutcome(ctx, name, outcome); err != nil {
+		slog.Error("failed to save task outcome", "task", name, "error", err)
+	}
+}
+
+func (s *Scheduler) fire(ctx context.Context, task config.Task, msg map[string]interface{}, trigger string) error {
+	data := message.NewData(time.Now().In(s.currentLocation()))
+
+	if task.FanOut == config.FanOutCompanies {
+		return s.fireForCompanies(ctx, task, msg, data, trigger)
+	}
+
+	return s.publish(ctx, task.Topic, task.Name, msg, data)
+}
+
+// fireForCompanies publishes one message per company keyed by its ticker.
+// A failure for one ticker does not stop the others; only the failed tickers
+// are retried, and those still failing are kept as pending so that a retry of
+// the run does not deliver the message twice to the others. A manual trigger
+// adds its failures to the pending tickers instead of replacing them, so the
+// retry of a failed scheduled run is not lost.
+func (s *Scheduler) fireForCompanies(ctx context.Context, task config.Task, msg map[string]interface{}, data message.Data, trigger string) error {
+	companies, err := s.companies.ListCompanies(ctx)
+	if err != nil {
+		return fmt.Errorf("list companies: %w", err)
+	}
+
+	var previous []string
+	if trigger == TriggerCatchUp || trigger == TriggerManual {
+		previous, err = s.store.GetPendingTickers(ctx, task.Name)
+		if err != nil {
+			return fmt.Errorf("get pending tickers: %w", err)
+		}
+	}
+	if trigger == TriggerCatchUp && len(previous) > 0 {
+		companies = onlyTickers(companies, previous)
+	}
+
+	total := len(companies)
+	failed, errs := s.publishToCompanies(ctx, task, msg, data, companies)
+	for attempt := 2; attempt <= fanOutAttempts && len(failed) > 0; attempt++ {
+		select {
+		case <-ctx.Done():
+		case <-time.After(fanOutRetryDelay):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		failed, errs = s.publishToCompanies(ctx, task, msg, data, failed)
+	}
+
+	pending := make([]string, 0, len(failed))
+	if trigger == TriggerManual {
+		pending = append(pending, previous...)
+	}
+	for _, company := range failed {
+		if !slices.Contains(pending, company.Ticker) {
+			pending = append(pending, company.Ticker)
+		}
+	}
+	if err := s.store.SetPendingTickers(ctx, task.Name, pending); err != nil {
+		slog.Error("failed to save pending tickers", "task", task.Name, "error", err)
+	}
+
+	slog.Info("task fanned out", "task", task.Name, "companies", total, "failed", len(failed))
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) publishToCompanies(ctx context.Context, task config.Task, msg map[string]interface{}, data message.Data, companies []message.Company) ([]message.Company, []error) {
+	var failed []message.Company
+	var errs []error
+	for _, company := range companies {
+		if err := s.publish(ctx, task.Topic, company.Ticker, msg, data.WithCompany(company)); err != nil {
+			failed = append(failed, company)
+			errs = append(errs, fmt.Errorf("%s: %w", company.Ticker, err))
+		}
+	}
+	return failed, errs
+}
+
+func onlyTickers(companies []message.Company, tickers []string) []message.Company {
+	wanted := make(map[string]bool, len(tickers))
+	for _, t := range tickers {
+		wanted[t] = true
+	}
+
+	result := make([]message.Company, 0, len(tickers))
+	for _, c := range companies {
+		if wanted[c.Ticker] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (s *Scheduler) publish(ctx context.Context, topic, key string, msg map[string]interface{}, data interface{}) error {
+	rendered, err := message.Render(msg, data)
+	if err != nil {
+		return fmt.Errorf("render message: %w", err)
+	}
+
+	value, err := json.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	return s.publisher.Publish(ctx, topic, []byte(key), value)
+}
+
+func (s *Scheduler) lookup(name string) (job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return job{}, false
+	}
+	return *j, true
+}
+
+func (s *Scheduler) snapshot() []job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]job, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, *s.jobs[name])
+	}
+	return jobs
+}
+
+func (s *Scheduler) currentLocation() *time.Location {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.location
+}
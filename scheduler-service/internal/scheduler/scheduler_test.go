@@ -0,0 +1,423 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"schedulerservice/internal/config"
+	"schedulerservice/internal/message"
+)
+
+type publishedMessage struct {
+	topic string
+	key   string
+	value []byte
+}
+
+type fakePublisher struct {
+	messages []publishedMessage
+	// failures is how many more times publishing with the key fails
+	failures map[string]int
+}
+
+func (p *fakePublisher) Publish(_ context.Context, topic string, key, value []byte) error {
+	if p.failures[string(key)] > 0 {
+		p.failures[string(key)]--
+		return errors.New("broker unavailable")
+	}
+	p.messages = append(p.messages, publishedMessage{topic: topic, key: string(key), value: value})
+	return nil
+}
+
+type fakeCompanies []message.Company
+
+func (c fakeCompanies) ListCompanies(_ context.Context) ([]message.Company, error) {
+	return c, nil
+}
+
+type fakeLeader bool
+
+func (l fakeLeader) IsLeader() bool {
+	return bool(l)
+}
+
+type memoryStore struct {
+	lastFired map[string]time.Time
+	outcomes  map[string]Outcome
+	paused    map[string]bool
+	pending   map[string][]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		lastFired: map[string]time.Time{},
+		outcomes:  map[string]Outcome{},
+		paused:    map[string]bool{},
+		pending:   map[string][]string{},
+	}
+}
+
+func (m *memoryStore) GetLastFired(_ context.Context, task string) (time.Time, bool, error) {
+	at, ok := m.lastFired[task]
+	return at, ok, nil
+}
+
+func (m *memoryStore) SetLastFired(_ context.Context, task string, at time.Time) error {
+	m.lastFired[task] = at
+	return nil
+}
+
+func (m *memoryStore) GetOutcome(_ context.Context, task string) (*Outcome, error) {
+	outcome, ok := m.outcomes[task]
+	if !ok {
+		return nil, nil
+	}
+	return &outcome, nil
+}
+
+func (m *memoryStore) SetOutcome(_ context.Context, task string, outcome Outcome) error {
+	m.outcomes[task] = outcome
+	return nil
+}
+
+func (m *memoryStore) IsPaused(_ context.Context, task string) (bool, error) {
+	return m.paused[task], nil
+}
+
+func (m *memoryStore) SetPaused(_ context.Context, task string, paused bool) error {
+	m.paused[task] = paused
+	return nil
+}
+
+func (m *memoryStore) GetPendingTickers(_ context.Context, task string) ([]string, error) {
+	return m.pending[task], nil
+}
+
+func (m *memoryStore) SetPendingTickers(_ context.Context, task string, tickers []string) error {
+	if len(tickers) == 0 {
+		delete(m.pending, task)
+		return nil
+	}
+	m.pending[task] = tickers
+	return nil
+}
+
+func newTestScheduler(t *testing.T, leader bool, store *memoryStore, tasks ...config.Task) (*Scheduler, *fakePublisher) {
+	t.Helper()
+	publisher := &fakePublisher{}
+	companies := fakeCompanies{{Ticker: "SBER", Name: "Сбербанк", SectorID: 2}, {Ticker: "LKOH", Name: "Лукойл", SectorID: 1}}
+	s, err := New(&config.Config{Timezone: config.DefaultTimezone, Tasks: tasks}, publisher, companies, fakeLeader(leader), store)
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	return s, publisher
+}
+
+func TestNewRejectsInvalidCron(t *testing.T) {
+	cfg := &config.Config{
+		Timezone: config.DefaultTimezone,
+		Tasks:    []config.Task{{Name: "broken", Cron: "not a cron", Topic: "topic"}},
+	}
+
+	if _, err := New(cfg, &fakePublisher{}, fakeCompanies{}, fakeLeader(true), newMemoryStore()); err == nil {
+		t.Fatal("Expected error for invalid cron expression, got nil")
+	}
+}
+
+func TestFirePublishesMessage(t *testing.T) {
+	task := config.Task{
+		Name:    "find_new_reports",
+		Cron:    "0 9 * * *",
+		Topic:   "parser.find_reports",
+		Message: map[string]interface{}{"action": "find_new_reports", "time": "morning"},
+	}
+	s, publisher := newTestScheduler(t, true, newMemoryStore(), task)
+
+	if err := s.fire(context.Background(), task, task.Message, TriggerSchedule); err != nil {
+		t.Fatalf("Failed to fire task: %v", err)
+	}
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(publisher.messages))
+	}
+
+	msg := publisher.messages[0]
+	if msg.topic != "parser.find_reports" {
+		t.Errorf("Expected topic 'parser.find_reports', got '%s'", msg.topic)
+	}
+	if msg.key != "find_new_reports" {
+		t.Errorf("Expected key 'find_new_reports', got '%s'", msg.key)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(msg.value, &payload); err != nil {
+		t.Fatalf("Failed to unmarshal payload: %v", err)
+	}
+	if payload["time"] != "morning" {
+		t.Errorf("Expected time 'morning', got '%v'", payload["time"])
+	}
+}
+
+func TestRunSkipsWhenNotLeader(t *testing.T) {
+	task := config.Task{Name: "task", Cron: "* * * * *", Topic: "topic"}
+	s, publisher := newTestScheduler(t, false, newMemoryStore(), task)
+
+	s.run(task.Name)
+
+	if len(publisher.messages) != 0 {
+		t.Errorf("Expected no published messages from follower, got %d", len(publisher.messages))
+	}
+}
+
+func TestCatchUpAppliesMisfirePolicy(t *testing.T) {
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	store := newMemoryStore()
+	store.lastFired["fire"] = dayAgo
+	store.lastFired["skip"] = dayAgo
+	s, publisher := newTestScheduler(t, true, store,
+		config.Task{Name: "fire", Cron: "* * * * *", Topic: "fire-topic", MisfirePolicy: config.MisfireFireOnce},
+		config.Task{Name: "skip", Cron: "* * * * *", Topic: "skip-topic", MisfirePolicy: config.MisfireSkip},
+		config.Task{Name: "new", Cron: "* * * * *", Topic: "new-topic", MisfirePolicy: config.MisfireFireOnce},
+	)
+
+	s.CatchUp(context.Background())
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(publisher.messages))
+	}
+	if publisher.messages[0].topic != "fire-topic" {
+		t.Errorf("Expected missed run of 'fire' to be published, got topic '%s'", publisher.messages[0].topic)
+	}
+
+	for _, name := range []string{"fire", "skip", "new"} {
+		if at, ok := store.lastFired[name]; !ok || !at.After(dayAgo) {
+			t.Errorf("Expected last fired time of '%s' to be updated, got %v", name, at)
+		}
+	}
+}
+
+func TestRunSkipsPausedTask(t *testing.T) {
+	task := config.Task{Name: "task", Cron: "* * * * *", Topic: "topic"}
+	store := newMemoryStore()
+	s, publisher := newTestScheduler(t, true, store, task)
+
+	if err := s.Pause(context.Background(), task.Name); err != nil {
+		t.Fatalf("Failed to pause task: %v", err)
+	}
+	s.run(task.Name)
+
+	if len(publisher.messages) != 0 {
+		t.Errorf("Expected no published messages for paused task, got %d", len(publisher.messages))
+	}
+	if store.outcomes[task.Name].Status != StatusPaused {
+		t.Errorf("Expected outcome status '%s', got '%s'", StatusPaused, store.outcomes[task.Name].Status)
+	}
+}
+
+func TestTriggerOverridesMessage(t *testing.T) {
+	task := config.Task{
+		Name:    "task",
+		Cron:    "0 9 * * *",
+		Topic:   "topic",
+		Message: map[string]interface{}{"action": "default"},
+	}
+	store := newMemoryStore()
+	s, publisher := newTestScheduler(t, false, store, task)
+
+	err := s.Trigger(context.Background(), task.Name, map[string]interface{}{"action": "override"})
+	if err != nil {
+		t.Fatalf("Failed to trigger task: %v", err)
+	}
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(publisher.messages))
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(publisher.messages[0].value, &payload); err != nil {
+		t.Fatalf("Failed to unmarshal payload: %v", err)
+	}
+	if payload["action"] != "override" {
+		t.Errorf("Expected action 'override', got '%v'", payload["action"])
+	}
+	if store.outcomes[task.Name].Trigger != TriggerManual {
+		t.Errorf("Expected outcome trigger '%s', got '%s'", TriggerManual, store.outcomes[task.Name].Trigger)
+	}
+
+	if err := s.Trigger(context.Background(), "missing", nil); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected ErrJobNotFound for unknown job, got %v", err)
+	}
+}
+
+func TestReloadReplacesJobs(t *testing.T) {
+	s, _ := newTestScheduler(t, true, newMemoryStore(), config.Task{Name: "old", Cron: "0 9 * * *", Topic: "topic"})
+
+	err := s.Reload(&config.Config{
+		Timezone: config.DefaultTimezone,
+		Tasks:    []config.Task{{Name: "new", Cron: "0 20 * * *", Topic: "topic"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+
+	jobs, err := s.Jobs(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Name != "new" {
+		t.Fatalf("Expected only job 'new' after reload, got %+v", jobs)
+	}
+	if jobs[0].NextFireTime == nil || jobs[0].NextFireTime.Hour() != 20 {
+		t.Errorf("Expected next fire time at 20:00 Moscow time, got %v", jobs[0].NextFireTime)
+	}
+
+	err = s.Reload(&config.Config{
+		Timezone: config.DefaultTimezone,
+		Tasks:    []config.Task{{Name: "broken", Cron: "bad", Topic: "topic"}},
+	})
+	if err == nil {
+		t.Fatal("Expected error for invalid cron on reload, got nil")
+	}
+	if _, err := s.Job(context.Background(), "new"); err != nil {
+		t.Errorf("Expected job 'new' to survive failed reload, got %v", err)
+	}
+}
+
+func TestFireFansOutPerCompany(t *testing.T) {
+	task := config.Task{
+		Name:    "risk_and_growth",
+		Cron:    "0 3 * * *",
+		Topic:   "ai-analyze-tasks",
+		FanOut:  config.FanOutCompanies,
+		Message: map[string]interface{}{"ticker": "{{.Ticker}}", "type": "risk-and-growth-expect"},
+	}
+	s, publisher := newTestScheduler(t, true, newMemoryStore(), task)
+
+	if err := s.fire(context.Background(), task, task.Message, TriggerSchedule); err != nil {
+		t.Fatalf("Failed to fire task: %v", err)
+	}
+
+	if len(publisher.messages) != 2 {
+		t.Fatalf("Expected 2 published messages, got %d", len(publisher.messages))
+	}
+
+	for i, ticker := range []string{"SBER", "LKOH"} {
+		msg := publisher.messages[i]
+		if msg.key != ticker {
+			t.Errorf("Expected key '%s', got '%s'", ticker, msg.key)
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(msg.value, &payload); err != nil {
+			t.Fatalf("Failed to unmarshal payload: %v", err)
+		}
+		if payload["ticker"] != ticker {
+			t.Errorf("Expected ticker '%s' in payload, got '%v'", ticker, payload["ticker"])
+		}
+	}
+}
+
+func TestReloadRejectsInvalidTemplate(t *testing.T) {
+	s, _ := newTestScheduler(t, true, newMemoryStore())
+
+	err := s.Reload(&config.Config{
+		Timezone: config.DefaultTimezone,
+		Tasks: []config.Task{{
+			Name:    "broken",
+			Cron:    "0 9 * * *",
+			Topic:   "topic",
+			Message: map[string]interface{}{"date": "{{.Date \"2006-01-02\""},
+		}},
+	})
+	if err == nil {
+		t.Fatal("Expected error for invalid message template, got nil")
+	}
+}
+
+func publishedKeys(messages []publishedMessage) []string {
+	keys := make([]string, 0, len(messages))
+	for _, m := range messages {
+		keys = append(keys, m.key)
+	}
+	return keys
+}
+
+func TestFanOutRetriesOnlyFailedTickers(t *testing.T) {
+	fanOutRetryDelay = 0
+	task := config.Task{
+		Name:    "risk_and_growth",
+		Cron:    "0 3 * * *",
+		Topic:   "ai-analyze-tasks",
+		FanOut:  config.FanOutCompanies,
+		Message: map[string]interface{}{"ticker": "{{.Ticker}}"},
+	}
+	store := newMemoryStore()
+	s, publisher := newTestScheduler(t, true, store, task)
+
+	publisher.failures = map[string]int{"LKOH": 1}
+	if err := s.fire(context.Background(), task, task.Message, TriggerSchedule); err != nil {
+		t.Fatalf("Expected retry to deliver to LKOH, got %v", err)
+	}
+	if keys := publishedKeys(publisher.messages); len(keys) != 2 || keys[0] != "SBER" || keys[1] != "LKOH" {
+		t.Errorf("Expected each ticker to get exactly one message, got %v", keys)
+	}
+	if _, ok := store.pending[task.Name]; ok {
+		t.Errorf("Expected no pending tickers after delivery, got %v", store.pending[task.Name])
+	}
+
+	publisher.messages = nil
+	publisher.failures = map[string]int{"LKOH": fanOutAttempts}
+	if err := s.fire(context.Background(), task, task.Message, TriggerSchedule); err == nil {
+		t.Fatal("Expected error when LKOH keeps failing, got nil")
+	}
+	if pending := store.pending[task.Name]; len(pending) != 1 || pending[0] != "LKOH" {
+		t.Fatalf("Expected LKOH to be pending, got %v", pending)
+	}
+
+	publisher.messages = nil
+	if err := s.fire(context.Background(), task, task.Message, TriggerCatchUp); err != nil {
+		t.Fatalf("Failed to retry pending tickers: %v", err)
+	}
+	if keys := publishedKeys(publisher.messages); len(keys) != 1 || keys[0] != "LKOH" {
+		t.Errorf("Expected catch-up to publish to LKOH only, got %v", keys)
+	}
+}
+
+func TestManualFanOutKeepsPendingTickers(t *testing.T) {
+	fanOutRetryDelay = 0
+	task := config.Task{
+		Name:    "risk_and_growth",
+		Cron:    "0 3 * * *",
+		Topic:   "ai-analyze-tasks",
+		FanOut:  config.FanOutCompanies,
+		Message: map[string]interface{}{"ticker": "{{.Ticker}}"},
+	}
+	store := newMemoryStore()
+	s, publisher := newTestScheduler(t, true, store, task)
+
+	publisher.failures = map[string]int{"LKOH": fanOutAttempts}
+	if err := s.fire(context.Background(), task, task.Message, TriggerSchedule); err == nil {
+		t.Fatal("Expected error when LKOH keeps failing, got nil")
+	}
+
+	publisher.messages = nil
+	publisher.failures = map[string]int{"SBER": fanOutAttempts}
+	if err := s.fire(context.Background(), task, task.Message, TriggerManual); err == nil {
+		t.Fatal("Expected error when SBER keeps failing, got nil")
+	}
+	if pending := store.pending[task.Name]; len(pending) != 2 || pending[0] != "LKOH" || pending[1] != "SBER" {
+		t.Fatalf("Expected manual failures to be added to LKOH, got %v", pending)
+	}
+
+	publisher.messages = nil
+	publisher.failures = nil
+	if err := s.fire(context.Background(), task, task.Message, TriggerCatchUp); err != nil {
+		t.Fatalf("Failed to retry pending tickers: %v", err)
+	}
+	if keys := publishedKeys(publisher.messages); len(keys) != 2 {
+		t.Errorf("Expected catch-up to publish to LKOH and SBER, got %v", keys)
+	}
+}
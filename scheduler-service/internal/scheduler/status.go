@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"schedulerservice/internal/config"
+)
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerCatchUp  = "catch_up"
+	TriggerManual   = "manual"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusPaused  = "paused"
+)
+
+type Outcome struct {
+	At      time.Time `json:"at"`
+	Trigger string    `json:"trigger"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func newOutcome(trigger string, err error) Outcome {
+	outcome := Outcome{At: time.Now(), Trigger: trigger, Status: StatusSuccess}
+	if err != nil {
+		outcome.Status = StatusFailed
+		outcome.Error = err.Error()
+	}
+	return outcome
+}
+
+type JobStatus struct {
+	Name             string                 `json:"name"`
+	Cron             string                 `json:"cron"`
+	Topic            string                 `json:"topic"`
+	MisfirePolicy    config.MisfirePolicy   `json:"misfirePolicy"`
+	Message          map[string]interface{} `json:"message,omitempty"`
+	Paused           bool                   `json:"paused"`
+	NextFireTime     *time.Time             `json:"nextFireTime,omitempty"`
+	PreviousFireTime *time.Time             `json:"previousFireTime,omitempty"`
+	LastOutcome      *Outcome               `json:"lastOutcome,omitempty"`
+}
+
+func (s *Scheduler) Jobs(ctx context.Context) ([]JobStatus, error) {
+	jobs := s.snapshot()
+	result := make([]JobStatus, 0, len(jobs))
+	for _, j := range jobs {
+		status, err := s.status(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *status)
+	}
+	return result, nil
+}
+
+func (s *Scheduler) Job(ctx context.Context, name string) (*JobStatus, error) {
+	j, ok := s.lookup(name)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return s.status(ctx, j)
+}
+
+func (s *Scheduler) status(ctx context.Context, j job) (*JobStatus, error) {
+	loc := s.currentLocation()
+	status := &JobStatus{
+		Name:          j.task.Name,
+		Cron:          j.task.Cron,
+		Topic:         j.task.Topic,
+		MisfirePolicy: j.task.MisfirePolicy,
+		Message:       j.task.Message,
+	}
+
+	paused, err := s.store.IsPaused(ctx, j.task.Name)
+	if err != nil {
+		return nil, err
+	}
+	status.Paused = paused
+
+	if !paused {
+		next := j.schedule.Next(time.Now().In(loc))
+		status.NextFireTime = &next
+	}
+
+	last, ok, err := s.store.GetLastFired(ctx, j.task.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		last = last.In(loc)
+		status.PreviousFireTime = &last
+	}
+
+	outcome, err := s.store.GetOutcome(ctx, j.task.Name)
+	if err != nil {
+		return nil, err
+	}
+	status.LastOutcome = outcome
+
+	return status, nil
+}